@@ -0,0 +1,15 @@
+package ingress
+
+type Ingress struct {
+	Class                  string
+	Gateway                Gateway
+	NodePortRange          string
+	PassthroughAnnotations string
+	Provider               string
+}
+
+type Gateway struct {
+	Name        string
+	Namespace   string
+	SectionName string
+}
@@ -1,9 +1,11 @@
 package api
 
 import (
+	"github.com/giantswarm/kvm-operator/v4/flag/service/installation/workload/kubernetes/api/audit"
 	"github.com/giantswarm/kvm-operator/v4/flag/service/installation/workload/kubernetes/api/auth"
 )
 
 type API struct {
-	Auth auth.Auth
+	Audit audit.Audit
+	Auth  auth.Auth
 }
@@ -0,0 +1,6 @@
+package networkpolicy
+
+type NetworkPolicy struct {
+	IngressControllerSelector string
+	MonitoringSelector        string
+}
@@ -0,0 +1,7 @@
+package orphanpod
+
+type OrphanPod struct {
+	GracePeriod string
+	MaxRecycled string
+	Recycle     string
+}
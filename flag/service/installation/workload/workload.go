@@ -1,9 +1,15 @@
 package workload
 
 import (
+	"github.com/giantswarm/kvm-operator/v4/flag/service/installation/workload/ingress"
 	"github.com/giantswarm/kvm-operator/v4/flag/service/installation/workload/kubernetes"
+	"github.com/giantswarm/kvm-operator/v4/flag/service/installation/workload/networkpolicy"
+	"github.com/giantswarm/kvm-operator/v4/flag/service/installation/workload/orphanpod"
 )
 
 type Workload struct {
-	Kubernetes kubernetes.Kubernetes
+	Ingress       ingress.Ingress
+	Kubernetes    kubernetes.Kubernetes
+	NetworkPolicy networkpolicy.NetworkPolicy
+	OrphanPod     orphanpod.OrphanPod
 }
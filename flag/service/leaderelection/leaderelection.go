@@ -0,0 +1,9 @@
+package leaderelection
+
+type LeaderElection struct {
+	Enabled       string
+	LeaseDuration string
+	Namespace     string
+	RenewDeadline string
+	RetryPeriod   string
+}
@@ -4,16 +4,20 @@ import (
 	"github.com/giantswarm/operatorkit/v5/pkg/flag/service/kubernetes"
 
 	"github.com/giantswarm/kvm-operator/v4/flag/service/installation"
+	"github.com/giantswarm/kvm-operator/v4/flag/service/leaderelection"
 	"github.com/giantswarm/kvm-operator/v4/flag/service/rbac"
 	"github.com/giantswarm/kvm-operator/v4/flag/service/registry"
+	"github.com/giantswarm/kvm-operator/v4/flag/service/shard"
 	"github.com/giantswarm/kvm-operator/v4/flag/service/workload"
 )
 
 type Service struct {
 	Installation            installation.Installation
 	Kubernetes              kubernetes.Kubernetes
+	LeaderElection          leaderelection.LeaderElection
 	RBAC                    rbac.RBAC
 	Registry                registry.Registry
+	Shard                   shard.Shard
 	TerminateUnhealthyNodes string
 	Workload                workload.Workload
 }
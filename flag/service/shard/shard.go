@@ -0,0 +1,6 @@
+package shard
+
+type Shard struct {
+	Count string
+	Index string
+}
@@ -2,6 +2,7 @@ package main
 
 import (
 	"fmt"
+	"time"
 
 	"github.com/giantswarm/microerror"
 	"github.com/giantswarm/microkit/command"
@@ -105,12 +106,33 @@ func mainError() error {
 	daemonCommand.PersistentFlags().String(f.Service.Installation.DNS.Servers, "", "Comma separated list of DNS servers.")
 	daemonCommand.PersistentFlags().String(f.Service.Installation.NTP.Servers, "", "Comma separated list of NTPservers.")
 
+	daemonCommand.PersistentFlags().String(f.Service.Installation.Workload.Ingress.Class, "", "Ingress class of workload cluster API and etcd ingresses. When empty no ingress class is set.")
+	daemonCommand.PersistentFlags().String(f.Service.Installation.Workload.Ingress.Gateway.Name, "", "Name of the Gateway workload cluster TLS routes are attached to when the gateway provider is used.")
+	daemonCommand.PersistentFlags().String(f.Service.Installation.Workload.Ingress.Gateway.Namespace, "", "Namespace of the Gateway workload cluster TLS routes are attached to when the gateway provider is used.")
+	daemonCommand.PersistentFlags().String(f.Service.Installation.Workload.Ingress.Gateway.SectionName, "", "Optional listener of the Gateway workload cluster TLS routes are attached to when the gateway provider is used.")
+	daemonCommand.PersistentFlags().String(f.Service.Installation.Workload.Ingress.NodePortRange, "", "Range of management cluster node ports allocated to workload cluster port mappings without node port, e.g. 30100-30999. When empty node ports are chosen by the Kubernetes API.")
+	daemonCommand.PersistentFlags().String(f.Service.Installation.Workload.Ingress.PassthroughAnnotations, `nginx.ingress.kubernetes.io/ssl-passthrough: "true"`, "Template of the YAML annotations enabling TLS passthrough on workload cluster API and etcd ingresses. It may use {{ .ClusterID }}, {{ .Host }}, {{ .ServiceName }} and {{ .ServicePort }}.")
+	daemonCommand.PersistentFlags().String(f.Service.Installation.Workload.Ingress.Provider, "ingress", "How workload cluster API and etcd endpoints are exposed, either ingress for Ingress objects or gateway for Gateway API TLSRoute objects.")
+	daemonCommand.PersistentFlags().String(f.Service.Installation.Workload.Kubernetes.API.Audit.Endpoint, "", "Endpoint workload cluster API server audit logs are shipped to, e.g. https://audit.example.com/ingest or syslog+tcp://audit.example.com:514. When empty audit logs are not shipped.")
+	daemonCommand.PersistentFlags().String(f.Service.Installation.Workload.Kubernetes.API.Audit.Policy, "", "Audit policy of workload cluster API servers in YAML format. When empty the default policy of k8scloudconfig is used.")
+
 	daemonCommand.PersistentFlags().String(f.Service.Installation.Workload.Kubernetes.API.Auth.Provider.OIDC.ClientID, "", "OIDC authorization provider ClientID.")
 	daemonCommand.PersistentFlags().String(f.Service.Installation.Workload.Kubernetes.API.Auth.Provider.OIDC.IssuerURL, "", "OIDC authorization provider IssuerURL.")
 	daemonCommand.PersistentFlags().String(f.Service.Installation.Workload.Kubernetes.API.Auth.Provider.OIDC.UsernameClaim, "", "OIDC authorization provider UsernameClaim.")
 	daemonCommand.PersistentFlags().String(f.Service.Installation.Workload.Kubernetes.API.Auth.Provider.OIDC.UsernamePrefix, "", "OIDC authorization provider UsernamePrefix.")
 	daemonCommand.PersistentFlags().String(f.Service.Installation.Workload.Kubernetes.API.Auth.Provider.OIDC.GroupsClaim, "", "OIDC authorization provider GroupsClaim.")
 	daemonCommand.PersistentFlags().String(f.Service.Installation.Workload.Kubernetes.API.Auth.Provider.OIDC.GroupsPrefix, "", "OIDC authorization provider GroupsPrefix.")
+	daemonCommand.PersistentFlags().String(f.Service.Installation.Workload.NetworkPolicy.IngressControllerSelector, "kubernetes.io/metadata.name=kube-system", "Label selector of the namespaces the ingress controller exposing workload cluster API and etcd endpoints runs in. Traffic from other namespaces to the VM pods is denied.")
+	daemonCommand.PersistentFlags().String(f.Service.Installation.Workload.NetworkPolicy.MonitoringSelector, "kubernetes.io/metadata.name=monitoring", "Label selector of the namespaces the monitoring scraping the VM pods runs in.")
+	daemonCommand.PersistentFlags().Duration(f.Service.Installation.Workload.OrphanPod.GracePeriod, 30*time.Minute, "Duration after which running VM pods without registered workload cluster node are reported as orphaned.")
+	daemonCommand.PersistentFlags().Int(f.Service.Installation.Workload.OrphanPod.MaxRecycled, 1, "Maximum number of orphaned VM pods of a workload cluster being recycled at once.")
+	daemonCommand.PersistentFlags().Bool(f.Service.Installation.Workload.OrphanPod.Recycle, false, "Whether to recycle orphaned VM pods by deleting them.")
+
+	daemonCommand.PersistentFlags().Bool(f.Service.LeaderElection.Enabled, false, "Whether controllers only run in the replica holding the leader election lease. Required when running more than one replica.")
+	daemonCommand.PersistentFlags().Duration(f.Service.LeaderElection.LeaseDuration, 15*time.Second, "Duration standby replicas wait before taking over the leader election lease which has not been renewed.")
+	daemonCommand.PersistentFlags().String(f.Service.LeaderElection.Namespace, "", "Namespace of the leader election lease. Required when leader election is enabled.")
+	daemonCommand.PersistentFlags().Duration(f.Service.LeaderElection.RenewDeadline, 10*time.Second, "Duration the leading replica retries renewing the leader election lease before giving up leadership.")
+	daemonCommand.PersistentFlags().Duration(f.Service.LeaderElection.RetryPeriod, 2*time.Second, "Interval in which replicas try to acquire or renew the leader election lease.")
 
 	daemonCommand.PersistentFlags().String(f.Service.RBAC.ClusterRole.General, "", "Name of existing general ClusterRole to be used for workload cluster node pods.")
 	daemonCommand.PersistentFlags().String(f.Service.RBAC.ClusterRole.PSP, "", "Name of existing ClusterRole with PSP to be used for workload cluster node pods.")
@@ -126,6 +148,9 @@ func mainError() error {
 	daemonCommand.PersistentFlags().String(f.Service.Registry.Domain, "docker.io", "Image registry domain.")
 	daemonCommand.PersistentFlags().StringSlice(f.Service.Registry.Mirrors, []string{}, `Image registry mirror domains. Can be set only if registry domain is "docker.io".`)
 
+	daemonCommand.PersistentFlags().Int(f.Service.Shard.Count, 1, "Number of operator instances clusters are distributed across. Clusters are assigned by hashing their cluster ID.")
+	daemonCommand.PersistentFlags().Int(f.Service.Shard.Index, 0, "Index of the shard of clusters reconciled by this operator instance, between 0 and shard count minus 1.")
+
 	daemonCommand.PersistentFlags().String(f.Service.Workload.Ignition.Path, "/opt/ignition", "Default path for the ignition base directory.")
 	daemonCommand.PersistentFlags().String(f.Service.Workload.Proxy.HTTP, "", "URL of proxy for HTTP requests.")
 	daemonCommand.PersistentFlags().String(f.Service.Workload.Proxy.HTTPS, "", "URL of proxy for HTTPS requests.")
@@ -0,0 +1,23 @@
+package shard
+
+import (
+	"github.com/giantswarm/microerror"
+)
+
+var invalidConfigError = &microerror.Error{
+	Kind: "invalidConfigError",
+}
+
+// IsInvalidConfig asserts invalidConfigError.
+func IsInvalidConfig(err error) bool {
+	return microerror.Cause(err) == invalidConfigError
+}
+
+var invalidClusterError = &microerror.Error{
+	Kind: "invalidClusterError",
+}
+
+// IsInvalidCluster asserts invalidClusterError.
+func IsInvalidCluster(err error) bool {
+	return microerror.Cause(err) == invalidClusterError
+}
@@ -0,0 +1,165 @@
+// Package shard distributes clusters across operator instances. Every cluster
+// is assigned to a shard by hashing its cluster ID, which is taken from the
+// label.Cluster label of the KVMConfig and of all VM pods of the cluster, so
+// that all controllers of an instance select the same clusters. KVMConfigs
+// must therefore be labeled with their cluster ID, which is validated when
+// they are reconciled.
+package shard
+
+import (
+	"fmt"
+	"hash/fnv"
+
+	"github.com/giantswarm/apiextensions/v3/pkg/apis/provider/v1alpha1"
+
+	"github.com/giantswarm/microerror"
+	"k8s.io/apimachinery/pkg/labels"
+
+	"github.com/giantswarm/kvm-operator/v4/pkg/label"
+)
+
+// legacyLabelCluster is the cluster ID label of VM pods created before the
+// new style label.Cluster was introduced.
+const legacyLabelCluster = "cluster"
+
+// Shard is the part of all clusters an operator instance is responsible for.
+// The zero value and a count of one select all clusters.
+type Shard struct {
+	Index int
+	Count int
+}
+
+// Validate returns an error in case the index is not within the count.
+func (s Shard) Validate() error {
+	if s.Count < 0 {
+		return microerror.Maskf(invalidConfigError, "shard count must not be negative")
+	}
+	if !s.Enabled() && s.Index != 0 {
+		return microerror.Maskf(invalidConfigError, "shard index must be 0 when shard count is %d", s.Count)
+	}
+	if s.Enabled() && (s.Index < 0 || s.Index >= s.Count) {
+		return microerror.Maskf(invalidConfigError, "shard index must be between 0 and %d", s.Count-1)
+	}
+
+	return nil
+}
+
+// Enabled returns true in case clusters are distributed across more than one
+// shard.
+func (s Shard) Enabled() bool {
+	return s.Count > 1
+}
+
+// Matches returns true in case the object with the given labels belongs to a
+// cluster of this shard. Objects without cluster ID label belong to the first
+// shard, so that exactly one instance reports unlabeled KVMConfigs as invalid
+// via ValidateCluster.
+func (s Shard) Matches(l map[string]string) bool {
+	if !s.Enabled() {
+		return true
+	}
+
+	id := ClusterID(l)
+	if id == "" {
+		return s.Index == 0
+	}
+
+	return Of(id, s.Count) == s.Index
+}
+
+// ValidateCluster returns an error in case the given KVMConfig is not labeled
+// with its cluster ID while clusters are distributed across shards. VM pods
+// are always labeled with the cluster ID, so an unlabeled KVMConfig could be
+// reconciled by another instance than its pods.
+func (s Shard) ValidateCluster(cr v1alpha1.KVMConfig) error {
+	if !s.Enabled() {
+		return nil
+	}
+
+	if ClusterID(cr.GetLabels()) != cr.Spec.Cluster.ID {
+		return microerror.Maskf(invalidClusterError, "KVMConfig %s/%s must have the %#q label set to its cluster ID %#q", cr.Namespace, cr.Name, label.Cluster, cr.Spec.Cluster.ID)
+	}
+
+	return nil
+}
+
+// Selector returns a selector matching the given labels of objects belonging
+// to a cluster of this shard.
+func (s Shard) Selector(set labels.Set) labels.Selector {
+	selector := labels.SelectorFromSet(set)
+	if !s.Enabled() {
+		return selector
+	}
+
+	return shardSelector{Selector: selector, shard: s}
+}
+
+// String returns a human readable representation of the shard, e.g. "1/3".
+func (s Shard) String() string {
+	if !s.Enabled() {
+		return "0/1"
+	}
+
+	return fmt.Sprintf("%d/%d", s.Index, s.Count)
+}
+
+// ClusterID returns the cluster ID given by the cluster ID label of an object
+// or an empty string in case it has none.
+func ClusterID(l map[string]string) string {
+	id := l[label.Cluster]
+	if id == "" {
+		id = l[legacyLabelCluster]
+	}
+
+	return id
+}
+
+// Of returns the index of the shard the cluster with the given ID is assigned
+// to when clusters are distributed across the given number of shards.
+func Of(clusterID string, count int) int {
+	h := fnv.New32a()
+	_, _ = h.Write([]byte(clusterID))
+
+	return int(h.Sum32() % uint32(count))
+}
+
+// shardSelector extends a label selector with the shard assignment. The
+// assignment cannot be expressed as label requirements, so it is only applied
+// when matching labels, which is how operatorkit filters events. Sharding is
+// match-only: String returns the base selector, so that lists and watches
+// using it are valid and still return the objects of all shards.
+type shardSelector struct {
+	labels.Selector
+	shard Shard
+}
+
+func (s shardSelector) Matches(l labels.Labels) bool {
+	if !s.Selector.Matches(l) {
+		return false
+	}
+
+	m := map[string]string{}
+	for _, k := range []string{label.Cluster, legacyLabelCluster} {
+		if l.Has(k) {
+			m[k] = l.Get(k)
+		}
+	}
+
+	return s.shard.Matches(m)
+}
+
+func (s shardSelector) Empty() bool {
+	return false
+}
+
+func (s shardSelector) String() string {
+	return s.Selector.String()
+}
+
+func (s shardSelector) Add(r ...labels.Requirement) labels.Selector {
+	return shardSelector{Selector: s.Selector.Add(r...), shard: s.shard}
+}
+
+func (s shardSelector) DeepCopySelector() labels.Selector {
+	return shardSelector{Selector: s.Selector.DeepCopySelector(), shard: s.shard}
+}
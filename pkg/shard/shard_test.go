@@ -0,0 +1,235 @@
+package shard
+
+import (
+	"strconv"
+	"testing"
+
+	"github.com/giantswarm/apiextensions/v3/pkg/apis/provider/v1alpha1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+
+	"github.com/giantswarm/kvm-operator/v4/pkg/label"
+)
+
+func Test_Shard_Selector(t *testing.T) {
+	base := labels.Set{
+		label.OperatorVersion: "4.0.0",
+	}
+
+	testCases := []struct {
+		name     string
+		shard    Shard
+		labels   labels.Set
+		expected bool
+	}{
+		{
+			name:     "case 0: sharding disabled",
+			shard:    Shard{},
+			labels:   labels.Set{label.OperatorVersion: "4.0.0", label.Cluster: "al9qy"},
+			expected: true,
+		},
+		{
+			name:     "case 1: base selector not matching",
+			shard:    Shard{},
+			labels:   labels.Set{label.OperatorVersion: "3.0.0", label.Cluster: "al9qy"},
+			expected: false,
+		},
+		{
+			name:     "case 2: cluster of this shard",
+			shard:    Shard{Index: Of("al9qy", 3), Count: 3},
+			labels:   labels.Set{label.OperatorVersion: "4.0.0", label.Cluster: "al9qy"},
+			expected: true,
+		},
+		{
+			name:     "case 3: cluster of another shard",
+			shard:    Shard{Index: (Of("al9qy", 3) + 1) % 3, Count: 3},
+			labels:   labels.Set{label.OperatorVersion: "4.0.0", label.Cluster: "al9qy"},
+			expected: false,
+		},
+		{
+			name:     "case 4: legacy cluster label",
+			shard:    Shard{Index: Of("al9qy", 3), Count: 3},
+			labels:   labels.Set{label.OperatorVersion: "4.0.0", "cluster": "al9qy"},
+			expected: true,
+		},
+		{
+			name:     "case 5: no cluster label on first shard",
+			shard:    Shard{Index: 0, Count: 3},
+			labels:   labels.Set{label.OperatorVersion: "4.0.0"},
+			expected: true,
+		},
+		{
+			name:     "case 6: no cluster label on other shard",
+			shard:    Shard{Index: 1, Count: 3},
+			labels:   labels.Set{label.OperatorVersion: "4.0.0"},
+			expected: false,
+		},
+		{
+			name:     "case 7: base selector not matching cluster of this shard",
+			shard:    Shard{Index: Of("al9qy", 3), Count: 3},
+			labels:   labels.Set{label.OperatorVersion: "3.0.0", label.Cluster: "al9qy"},
+			expected: false,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			selector := tc.shard.Selector(base)
+
+			result := selector.Matches(tc.labels)
+			if result != tc.expected {
+				t.Fatalf("expected %t, got %t", tc.expected, result)
+			}
+
+			_, err := labels.Parse(selector.String())
+			if err != nil {
+				t.Fatalf("expected selector %#q to be valid, got %#v", selector.String(), err)
+			}
+			if selector.String() != labels.SelectorFromSet(base).String() {
+				t.Fatalf("expected selector %#q, got %#q", labels.SelectorFromSet(base).String(), selector.String())
+			}
+		})
+	}
+}
+
+func Test_Shard_distribution(t *testing.T) {
+	count := 3
+	shards := make([]Shard, count)
+	for i := range shards {
+		shards[i] = Shard{Index: i, Count: count}
+	}
+
+	// Every cluster is selected by exactly one shard.
+	for i := 0; i < 100; i++ {
+		l := labels.Set{label.Cluster: "c" + strconv.Itoa(i)}
+
+		var matches int
+		for _, s := range shards {
+			if s.Matches(l) {
+				matches++
+			}
+		}
+
+		if matches != 1 {
+			t.Fatalf("expected cluster %#q to be selected by 1 shard, got %d", l[label.Cluster], matches)
+		}
+	}
+}
+
+func Test_Shard_Validate(t *testing.T) {
+	testCases := []struct {
+		name         string
+		shard        Shard
+		errorMatcher func(error) bool
+	}{
+		{
+			name:  "case 0: sharding disabled",
+			shard: Shard{},
+		},
+		{
+			name:  "case 1: last shard",
+			shard: Shard{Index: 2, Count: 3},
+		},
+		{
+			name:         "case 2: index out of range",
+			shard:        Shard{Index: 3, Count: 3},
+			errorMatcher: IsInvalidConfig,
+		},
+		{
+			name:         "case 3: index without sharding",
+			shard:        Shard{Index: 1, Count: 1},
+			errorMatcher: IsInvalidConfig,
+		},
+		{
+			name:         "case 4: negative count",
+			shard:        Shard{Count: -1},
+			errorMatcher: IsInvalidConfig,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := tc.shard.Validate()
+
+			switch {
+			case err == nil && tc.errorMatcher == nil:
+				// correct; carry on
+			case err != nil && tc.errorMatcher == nil:
+				t.Fatalf("error == %#v, want nil", err)
+			case err == nil && tc.errorMatcher != nil:
+				t.Fatalf("error == nil, want non-nil")
+			case !tc.errorMatcher(err):
+				t.Fatalf("error == %#v, want matching", err)
+			}
+		})
+	}
+}
+
+func Test_Shard_ValidateCluster(t *testing.T) {
+	newCluster := func(id string, l map[string]string) v1alpha1.KVMConfig {
+		return v1alpha1.KVMConfig{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      id,
+				Namespace: "default",
+				Labels:    l,
+			},
+			Spec: v1alpha1.KVMConfigSpec{
+				Cluster: v1alpha1.Cluster{
+					ID: id,
+				},
+			},
+		}
+	}
+
+	testCases := []struct {
+		name         string
+		shard        Shard
+		cluster      v1alpha1.KVMConfig
+		errorMatcher func(error) bool
+	}{
+		{
+			name:    "case 0: sharding disabled",
+			shard:   Shard{},
+			cluster: newCluster("al9qy", nil),
+		},
+		{
+			name:    "case 1: labeled cluster",
+			shard:   Shard{Index: 1, Count: 3},
+			cluster: newCluster("al9qy", map[string]string{label.Cluster: "al9qy"}),
+		},
+		{
+			name:    "case 2: cluster with legacy label",
+			shard:   Shard{Index: 1, Count: 3},
+			cluster: newCluster("b2bbb", map[string]string{"cluster": "b2bbb"}),
+		},
+		{
+			name:         "case 3: unlabeled cluster",
+			shard:        Shard{Index: 1, Count: 3},
+			cluster:      newCluster("b2bbb", nil),
+			errorMatcher: IsInvalidCluster,
+		},
+		{
+			name:         "case 4: cluster labeled with another cluster ID",
+			shard:        Shard{Index: 1, Count: 3},
+			cluster:      newCluster("al9qy", map[string]string{label.Cluster: "b2bbb"}),
+			errorMatcher: IsInvalidCluster,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := tc.shard.ValidateCluster(tc.cluster)
+
+			switch {
+			case err == nil && tc.errorMatcher == nil:
+				// correct; carry on
+			case err != nil && tc.errorMatcher == nil:
+				t.Fatalf("error == %#v, want nil", err)
+			case err == nil && tc.errorMatcher != nil:
+				t.Fatalf("error == nil, want non-nil")
+			case !tc.errorMatcher(err):
+				t.Fatalf("error == %#v, want matching", err)
+			}
+		})
+	}
+}
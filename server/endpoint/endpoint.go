@@ -6,6 +6,9 @@ import (
 	"github.com/giantswarm/microerror"
 	"github.com/giantswarm/micrologger"
 
+	"github.com/giantswarm/kvm-operator/v4/server/endpoint/inventory/lister"
+	"github.com/giantswarm/kvm-operator/v4/server/endpoint/inventory/searcher"
+	"github.com/giantswarm/kvm-operator/v4/server/endpoint/readyz"
 	"github.com/giantswarm/kvm-operator/v4/service"
 )
 
@@ -16,8 +19,11 @@ type Config struct {
 
 // Endpoint is the endpoint collection.
 type Endpoint struct {
-	Healthz *healthz.Endpoint
-	Version *versionendpoint.Endpoint
+	Healthz           *healthz.Endpoint
+	InventoryLister   *lister.Endpoint
+	InventorySearcher *searcher.Endpoint
+	Readyz            *readyz.Endpoint
+	Version           *versionendpoint.Endpoint
 }
 
 func New(config Config) (*Endpoint, error) {
@@ -35,6 +41,45 @@ func New(config Config) (*Endpoint, error) {
 		}
 	}
 
+	var inventoryListerEndpoint *lister.Endpoint
+	{
+		c := lister.Config{
+			Logger:  config.Logger,
+			Service: config.Service.Inventory,
+		}
+
+		inventoryListerEndpoint, err = lister.New(c)
+		if err != nil {
+			return nil, microerror.Mask(err)
+		}
+	}
+
+	var inventorySearcherEndpoint *searcher.Endpoint
+	{
+		c := searcher.Config{
+			Logger:  config.Logger,
+			Service: config.Service.Inventory,
+		}
+
+		inventorySearcherEndpoint, err = searcher.New(c)
+		if err != nil {
+			return nil, microerror.Mask(err)
+		}
+	}
+
+	var readyzEndpoint *readyz.Endpoint
+	{
+		c := readyz.Config{
+			Logger:  config.Logger,
+			Service: config.Service.Readiness,
+		}
+
+		readyzEndpoint, err = readyz.New(c)
+		if err != nil {
+			return nil, microerror.Mask(err)
+		}
+	}
+
 	var versionEndpoint *versionendpoint.Endpoint
 	{
 		c := versionendpoint.Config{
@@ -49,8 +94,11 @@ func New(config Config) (*Endpoint, error) {
 	}
 
 	newEndpoint := &Endpoint{
-		Healthz: healthzEndpoint,
-		Version: versionEndpoint,
+		Healthz:           healthzEndpoint,
+		InventoryLister:   inventoryListerEndpoint,
+		InventorySearcher: inventorySearcherEndpoint,
+		Readyz:            readyzEndpoint,
+		Version:           versionEndpoint,
 	}
 
 	return newEndpoint, nil
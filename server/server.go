@@ -15,6 +15,7 @@ import (
 	"github.com/giantswarm/kvm-operator/v4/pkg/project"
 	"github.com/giantswarm/kvm-operator/v4/server/endpoint"
 	"github.com/giantswarm/kvm-operator/v4/service"
+	"github.com/giantswarm/kvm-operator/v4/service/inventory"
 )
 
 // Config represents the configuration used to create a new server object.
@@ -73,6 +74,9 @@ func New(config Config) (*Server, error) {
 
 			Endpoints: []microserver.Endpoint{
 				endpointCollection.Healthz,
+				endpointCollection.InventoryLister,
+				endpointCollection.InventorySearcher,
+				endpointCollection.Readyz,
 				endpointCollection.Version,
 			},
 			ErrorEncoder: errorEncoder,
@@ -105,7 +109,17 @@ func errorEncoder(ctx context.Context, err error, w http.ResponseWriter) {
 	rErr := err.(microserver.ResponseError)
 	uErr := rErr.Underlying()
 
-	rErr.SetCode(microserver.CodeInternalError)
 	rErr.SetMessage(uErr.Error())
-	w.WriteHeader(http.StatusInternalServerError)
+
+	switch {
+	case inventory.IsNotFound(uErr):
+		rErr.SetCode(microserver.CodeResourceNotFound)
+		w.WriteHeader(http.StatusNotFound)
+	case inventory.IsNotYetAvailable(uErr):
+		rErr.SetCode(microserver.CodeNotYetAvailable)
+		w.WriteHeader(http.StatusServiceUnavailable)
+	default:
+		rErr.SetCode(microserver.CodeInternalError)
+		w.WriteHeader(http.StatusInternalServerError)
+	}
 }
@@ -0,0 +1,169 @@
+package cloudconfig
+
+import (
+	"fmt"
+	"net"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/giantswarm/microerror"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/validation"
+	"k8s.io/apimachinery/pkg/util/yaml"
+)
+
+const (
+	// auditLogPath is where the API server writes its audit log to. The flag is
+	// set by k8scloudconfig and the directory is mounted into the API server pod
+	// from the master's host.
+	auditLogPath = "/var/log/apiserver/audit.log"
+	// auditPolicyFile is the key of the audit policy within the files rendered
+	// by k8scloudconfig.
+	auditPolicyFile = "policies/audit-policy.yaml"
+
+	auditForwarderPath     = "/opt/bin/apiserver-audit-forwarder"
+	auditForwarderUnitName = "apiserver-audit-forwarder.service"
+)
+
+const auditForwarderUnit = `[Unit]
+Description=Ships API server audit logs to an external endpoint
+Requires=k8s-kubelet.service
+After=k8s-kubelet.service
+
+[Service]
+Restart=always
+RestartSec=10
+ExecStart=` + auditForwarderPath + `
+
+[Install]
+WantedBy=multi-user.target`
+
+// auditExtraArgs returns the API server flags complementing the audit flags
+// set by k8scloudconfig. Events are written as JSON lines so that they can be
+// shipped one by one by the audit forwarder.
+func auditExtraArgs() []string {
+	return []string{
+		"--audit-log-format=json",
+	}
+}
+
+// auditForwarderScript returns the script following the API server audit log
+// and shipping every event to the given endpoint. Supported endpoints are
+// http(s)://host[:port]/path, where events are POSTed one by one, and
+// syslog://host:port, syslog+udp://host:port and syslog+tcp://host:port.
+func auditForwarderScript(endpoint string) (string, error) {
+	u, err := parseAuditEndpoint(endpoint)
+	if err != nil {
+		return "", microerror.Mask(err)
+	}
+
+	var ship string
+	switch u.Scheme {
+	case "http", "https":
+		ship = fmt.Sprintf(`while read -r line; do
+  curl --silent --show-error --max-time 10 -X POST -H 'Content-Type: application/json' --data-binary "$line" '%s' || echo "failed to ship audit event"
+done`, u.String())
+	case "syslog", "syslog+udp":
+		ship = fmt.Sprintf("logger --server '%s' --port '%s' --udp --tag kube-apiserver-audit", u.Hostname(), u.Port())
+	case "syslog+tcp":
+		ship = fmt.Sprintf("logger --server '%s' --port '%s' --tcp --tag kube-apiserver-audit", u.Hostname(), u.Port())
+	}
+
+	script := fmt.Sprintf(`#!/bin/bash
+
+set -o pipefail
+
+while [ ! -f '%[1]s' ]; do
+  echo 'Waiting for %[1]s to be written'
+  sleep 10
+done
+
+tail -n 0 -F '%[1]s' | %[2]s`, auditLogPath, ship)
+
+	return script, nil
+}
+
+// parseAuditEndpoint parses and validates the given audit endpoint. The
+// endpoint is rendered into the forwarder script running as root on every
+// master. Its host must therefore be a DNS-1123 subdomain or an IP address and
+// its port must be numeric, so that none of them can inject shell commands.
+func parseAuditEndpoint(endpoint string) (*url.URL, error) {
+	// The endpoint ends up single quoted in the forwarder script. It must
+	// therefore not contain quotes itself.
+	if strings.ContainsAny(endpoint, `'"`) {
+		return nil, microerror.Maskf(invalidConfigError, "audit endpoint %#q must not contain quotes", endpoint)
+	}
+
+	u, err := url.Parse(endpoint)
+	if err != nil {
+		return nil, microerror.Maskf(invalidConfigError, "audit endpoint %#q is invalid: %s", endpoint, err)
+	}
+
+	switch u.Scheme {
+	case "http", "https":
+		if u.Host == "" {
+			return nil, microerror.Maskf(invalidConfigError, "audit endpoint %#q must define a host", endpoint)
+		}
+	case "syslog", "syslog+udp", "syslog+tcp":
+		_, _, err := net.SplitHostPort(u.Host)
+		if err != nil || u.Port() == "" {
+			return nil, microerror.Maskf(invalidConfigError, "audit endpoint %#q must define host and port", endpoint)
+		}
+	default:
+		return nil, microerror.Maskf(invalidConfigError, "audit endpoint %#q must use one of the schemes http, https, syslog, syslog+udp or syslog+tcp", endpoint)
+	}
+
+	if net.ParseIP(u.Hostname()) == nil && len(validation.IsDNS1123Subdomain(u.Hostname())) != 0 {
+		return nil, microerror.Maskf(invalidConfigError, "audit endpoint %#q must define a DNS-1123 host name or an IP address as host", endpoint)
+	}
+
+	if u.Port() != "" {
+		port, err := strconv.Atoi(u.Port())
+		if err != nil || len(validation.IsValidPortNum(port)) != 0 {
+			return nil, microerror.Maskf(invalidConfigError, "audit endpoint %#q must define a port between 1 and 65535", endpoint)
+		}
+	}
+
+	return u, nil
+}
+
+// mergeAudit returns the installation wide audit configuration with all fields
+// replaced which are set in the given cluster specific configuration.
+func mergeAudit(installation AuditConfig, cluster *AuditConfig) AuditConfig {
+	if cluster == nil {
+		return installation
+	}
+
+	merged := installation
+	if cluster.Endpoint != "" {
+		merged.Endpoint = cluster.Endpoint
+	}
+	if cluster.Policy != "" {
+		merged.Policy = cluster.Policy
+	}
+
+	return merged
+}
+
+func validateAudit(config AuditConfig) error {
+	if config.Endpoint != "" {
+		_, err := parseAuditEndpoint(config.Endpoint)
+		if err != nil {
+			return microerror.Mask(err)
+		}
+	}
+
+	if config.Policy != "" {
+		var typeMeta metav1.TypeMeta
+		err := yaml.NewYAMLOrJSONDecoder(strings.NewReader(config.Policy), 4096).Decode(&typeMeta)
+		if err != nil {
+			return microerror.Maskf(invalidConfigError, "audit policy is invalid: %s", err)
+		}
+		if typeMeta.Kind != "Policy" || !strings.HasPrefix(typeMeta.APIVersion, "audit.k8s.io/") {
+			return microerror.Maskf(invalidConfigError, "audit policy must be of kind %#q in API group %#q", "Policy", "audit.k8s.io")
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,159 @@
+package cloudconfig
+
+import (
+	"strings"
+	"testing"
+)
+
+func Test_auditForwarderScript(t *testing.T) {
+	testCases := []struct {
+		Name            string
+		Endpoint        string
+		ExpectedCommand string
+		ErrorMatcher    func(error) bool
+	}{
+		{
+			Name:            "http endpoint",
+			Endpoint:        "http://127.0.0.1:8080/audit",
+			ExpectedCommand: "--data-binary \"$line\" 'http://127.0.0.1:8080/audit'",
+		},
+		{
+			Name:            "syslog endpoint defaults to udp",
+			Endpoint:        "syslog://127.0.0.1:514",
+			ExpectedCommand: "logger --server '127.0.0.1' --port '514' --udp",
+		},
+		{
+			Name:            "syslog tcp endpoint",
+			Endpoint:        "syslog+tcp://audit.example.com:6514",
+			ExpectedCommand: "logger --server 'audit.example.com' --port '6514' --tcp",
+		},
+		{
+			Name:         "syslog endpoint without port",
+			Endpoint:     "syslog://audit.example.com",
+			ErrorMatcher: IsInvalidConfig,
+		},
+		{
+			Name:         "unsupported scheme",
+			Endpoint:     "ftp://audit.example.com",
+			ErrorMatcher: IsInvalidConfig,
+		},
+		{
+			Name:            "syslog ipv6 endpoint",
+			Endpoint:        "syslog+udp://[fd00::1]:514",
+			ExpectedCommand: "logger --server 'fd00::1' --port '514' --udp",
+		},
+		{
+			Name:         "syslog host with command substitution",
+			Endpoint:     "syslog://a$(id):514",
+			ErrorMatcher: IsInvalidConfig,
+		},
+		{
+			Name:         "syslog host with backticks",
+			Endpoint:     "syslog://a`id`:514",
+			ErrorMatcher: IsInvalidConfig,
+		},
+		{
+			Name:         "syslog host with semicolon",
+			Endpoint:     "syslog://a;id:514",
+			ErrorMatcher: IsInvalidConfig,
+		},
+		{
+			Name:         "syslog host with spaces",
+			Endpoint:     "syslog://a%20id:514",
+			ErrorMatcher: IsInvalidConfig,
+		},
+		{
+			Name:         "http host with command substitution",
+			Endpoint:     "http://a$(id)/audit",
+			ErrorMatcher: IsInvalidConfig,
+		},
+		{
+			Name:         "syslog non numeric port",
+			Endpoint:     "syslog://audit.example.com:$(id)",
+			ErrorMatcher: IsInvalidConfig,
+		},
+		{
+			Name:         "syslog port out of range",
+			Endpoint:     "syslog://audit.example.com:65536",
+			ErrorMatcher: IsInvalidConfig,
+		},
+		{
+			Name:         "quoted endpoint",
+			Endpoint:     "http://audit.example.com/'; rm -rf /'",
+			ErrorMatcher: IsInvalidConfig,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.Name, func(t *testing.T) {
+			script, err := auditForwarderScript(tc.Endpoint)
+
+			switch {
+			case err == nil && tc.ErrorMatcher == nil:
+				// correct; carry on
+			case err != nil && tc.ErrorMatcher == nil:
+				t.Fatalf("error == %#v, want nil", err)
+			case err == nil && tc.ErrorMatcher != nil:
+				t.Fatalf("error == nil, want non-nil")
+			case !tc.ErrorMatcher(err):
+				t.Fatalf("error == %#v, want matching", err)
+			case tc.ErrorMatcher(err):
+				return
+			}
+
+			if !strings.Contains(script, tc.ExpectedCommand) {
+				t.Fatalf("expected script to contain %#q got %#q", tc.ExpectedCommand, script)
+			}
+		})
+	}
+}
+
+func Test_validateAudit(t *testing.T) {
+	testCases := []struct {
+		Name         string
+		Config       AuditConfig
+		ErrorMatcher func(error) bool
+	}{
+		{
+			Name:   "empty config",
+			Config: AuditConfig{},
+		},
+		{
+			Name: "valid policy",
+			Config: AuditConfig{
+				Policy: "apiVersion: audit.k8s.io/v1\nkind: Policy\nrules:\n- level: Metadata\n",
+			},
+		},
+		{
+			Name: "policy of wrong kind",
+			Config: AuditConfig{
+				Policy: "apiVersion: v1\nkind: ConfigMap\n",
+			},
+			ErrorMatcher: IsInvalidConfig,
+		},
+		{
+			Name: "malformed policy",
+			Config: AuditConfig{
+				Policy: "kind: [",
+			},
+			ErrorMatcher: IsInvalidConfig,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.Name, func(t *testing.T) {
+			err := validateAudit(tc.Config)
+
+			switch {
+			case err == nil && tc.ErrorMatcher == nil:
+				// correct; carry on
+			case err != nil && tc.ErrorMatcher == nil:
+				t.Fatalf("error == %#v, want nil", err)
+			case err == nil && tc.ErrorMatcher != nil:
+				t.Fatalf("error == nil, want non-nil")
+			case !tc.ErrorMatcher(err):
+				t.Fatalf("error == %#v, want matching", err)
+			}
+		})
+	}
+}
@@ -16,6 +16,11 @@ const (
 	IscsiInitiatorNameFilePath    = "/etc/iscsi/initiatorname.iscsi"
 	IscsiInitiatorFilePermissions = 0644
 
+	// oidcCAFilePath is where the CA bundle of a cluster specific OIDC
+	// provider is written to. The directory is mounted into the API server
+	// pod already.
+	oidcCAFilePath = "/etc/kubernetes/ssl/oidc-ca.pem"
+
 	IscsiConfigFilePath        = "/etc/iscsi/iscsid.conf"
 	IscsiConfigFilePermissions = 0644
 	IscsiConfigFileContent     = `
@@ -62,6 +67,7 @@ type Config struct {
 	// Dependencies.
 	Logger micrologger.Logger
 
+	Audit           AuditConfig
 	DockerhubToken  string
 	IgnitionPath    string
 	OIDC            OIDCConfig
@@ -84,12 +90,11 @@ type CloudConfig struct {
 	// Dependencies.
 	logger micrologger.Logger
 
-	dockerhubToken  string
-	ignitionPath    string
-	k8sAPIExtraArgs []string
-	proxy           proxyConfig
-	registryMirrors []string
-	ssoPublicKey    string
+	audit        AuditConfig
+	egress       egress
+	ignitionPath string
+	oidc         OIDCConfig
+	ssoPublicKey string
 }
 
 type proxyConfig struct {
@@ -98,21 +103,34 @@ type proxyConfig struct {
 	noProxy string
 }
 
+// AuditConfig represents the audit configuration of the API server.
+type AuditConfig struct {
+	// Endpoint is the optional HTTP or syslog endpoint audit logs are shipped
+	// to. See auditForwarderScript for the supported formats.
+	Endpoint string `json:"endpoint,omitempty"`
+	// Policy is the optional audit policy in YAML format. When empty the
+	// default policy of k8scloudconfig is used.
+	Policy string `json:"policy,omitempty"`
+}
+
 // OIDCConfig represents the configuration of the OIDC authorization provider
 type OIDCConfig struct {
-	ClientID       string
-	IssuerURL      string
-	UsernameClaim  string
-	UsernamePrefix string
-	GroupsClaim    string
-	GroupsPrefix   string
+	ClientID       string `json:"clientID"`
+	IssuerURL      string `json:"issuerURL"`
+	UsernameClaim  string `json:"usernameClaim"`
+	UsernamePrefix string `json:"usernamePrefix"`
+	GroupsClaim    string `json:"groupsClaim"`
+	GroupsPrefix   string `json:"groupsPrefix"`
+	// CAPEM is an optional PEM encoded CA bundle used by the API server to
+	// verify the issuer's serving certificate.
+	CAPEM string `json:"caPEM"`
 }
 
 // ProxyConfig represents the configuration of the proxy
 type ProxyConfig struct {
-	HTTP    string
-	HTTPS   string
-	NoProxy []string
+	HTTP    string   `json:"http,omitempty"`
+	HTTPS   string   `json:"https,omitempty"`
+	NoProxy []string `json:"noProxy,omitempty"`
 }
 
 // New creates a new configured cloud config service.
@@ -126,43 +144,61 @@ func New(config Config) (*CloudConfig, error) {
 		return nil, microerror.Maskf(invalidConfigError, "%T.IgnitionPath must not be empty", config)
 	}
 
-	var k8sAPIExtraArgs []string
-	{
-		if config.OIDC.ClientID != "" {
-			k8sAPIExtraArgs = append(k8sAPIExtraArgs, fmt.Sprintf("--oidc-client-id=%s", config.OIDC.ClientID))
-		}
-		if config.OIDC.IssuerURL != "" {
-			k8sAPIExtraArgs = append(k8sAPIExtraArgs, fmt.Sprintf("--oidc-issuer-url=%s", config.OIDC.IssuerURL))
-		}
-		if config.OIDC.UsernameClaim != "" {
-			k8sAPIExtraArgs = append(k8sAPIExtraArgs, fmt.Sprintf("--oidc-username-claim=%s", config.OIDC.UsernameClaim))
-		}
-		if config.OIDC.UsernamePrefix != "" {
-			k8sAPIExtraArgs = append(k8sAPIExtraArgs, fmt.Sprintf("'--oidc-username-prefix=%s'", config.OIDC.UsernamePrefix))
-		}
-		if config.OIDC.GroupsClaim != "" {
-			k8sAPIExtraArgs = append(k8sAPIExtraArgs, fmt.Sprintf("--oidc-groups-claim=%s", config.OIDC.GroupsClaim))
-		}
-		if config.OIDC.GroupsPrefix != "" {
-			k8sAPIExtraArgs = append(k8sAPIExtraArgs, fmt.Sprintf("'--oidc-groups-prefix=%s'", config.OIDC.GroupsPrefix))
-		}
+	err := validateAudit(config.Audit)
+	if err != nil {
+		return nil, microerror.Mask(err)
 	}
 
 	newCloudConfig := &CloudConfig{
 		// Dependencies.
 		logger: config.Logger,
 
-		dockerhubToken:  config.DockerhubToken,
-		ignitionPath:    config.IgnitionPath,
-		k8sAPIExtraArgs: k8sAPIExtraArgs,
-		proxy: proxyConfig{
-			http:    config.Proxy.HTTP,
-			https:   config.Proxy.HTTPS,
-			noProxy: strings.Join(config.Proxy.NoProxy, ","),
+		audit: config.Audit,
+		egress: egress{
+			dockerhubToken: config.DockerhubToken,
+			proxy: proxyConfig{
+				http:    config.Proxy.HTTP,
+				https:   config.Proxy.HTTPS,
+				noProxy: strings.Join(config.Proxy.NoProxy, ","),
+			},
+			registryMirrors: config.RegistryMirrors,
 		},
-		registryMirrors: config.RegistryMirrors,
-		ssoPublicKey:    config.SSOPublicKey,
+		ignitionPath: config.IgnitionPath,
+		oidc:         config.OIDC,
+		ssoPublicKey: config.SSOPublicKey,
 	}
 
 	return newCloudConfig, nil
 }
+
+// oidcExtraArgs returns the API server flags configuring the given OIDC
+// authorization provider. The CA file flag is only set in case a CA bundle is
+// provided, since it is then written to oidcCAFilePath by the master
+// extension.
+func oidcExtraArgs(config OIDCConfig) []string {
+	var args []string
+
+	if config.ClientID != "" {
+		args = append(args, fmt.Sprintf("--oidc-client-id=%s", config.ClientID))
+	}
+	if config.IssuerURL != "" {
+		args = append(args, fmt.Sprintf("--oidc-issuer-url=%s", config.IssuerURL))
+	}
+	if config.UsernameClaim != "" {
+		args = append(args, fmt.Sprintf("--oidc-username-claim=%s", config.UsernameClaim))
+	}
+	if config.UsernamePrefix != "" {
+		args = append(args, fmt.Sprintf("'--oidc-username-prefix=%s'", config.UsernamePrefix))
+	}
+	if config.GroupsClaim != "" {
+		args = append(args, fmt.Sprintf("--oidc-groups-claim=%s", config.GroupsClaim))
+	}
+	if config.GroupsPrefix != "" {
+		args = append(args, fmt.Sprintf("'--oidc-groups-prefix=%s'", config.GroupsPrefix))
+	}
+	if config.CAPEM != "" {
+		args = append(args, fmt.Sprintf("--oidc-ca-file=%s", oidcCAFilePath))
+	}
+
+	return args
+}
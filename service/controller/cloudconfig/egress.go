@@ -0,0 +1,65 @@
+package cloudconfig
+
+import (
+	"strings"
+
+	k8scloudconfig "github.com/giantswarm/k8scloudconfig/v10/pkg/template"
+)
+
+// EgressConfig represents the cluster specific proxy and registry
+// configuration. It is usually read from a Secret, since proxy URLs and the
+// DockerHub token carry credentials.
+type EgressConfig struct {
+	DockerhubToken string       `json:"dockerhubToken,omitempty"`
+	Proxy          *ProxyConfig `json:"proxy,omitempty"`
+	// RegistryDomain is the registry images are pulled from. It is not
+	// rendered by the cloud config service itself but used to build the
+	// images handed over via IgnitionTemplateData.
+	RegistryDomain  string   `json:"registryDomain,omitempty"`
+	RegistryMirrors []string `json:"registryMirrors,omitempty"`
+}
+
+// egress holds the effective egress settings of a cluster as rendered into the
+// template params.
+type egress struct {
+	dockerhubToken  string
+	proxy           proxyConfig
+	registryMirrors []string
+}
+
+// mergeEgress returns the installation wide egress settings overridden by the
+// fields set in the given cluster specific configuration. The proxy is
+// replaced as a whole, so that a cluster never mixes its own proxy with the
+// installation's exceptions. Registry mirrors only apply to docker.io, which
+// is why a cluster specific registry domain drops the installation wide
+// mirrors.
+func mergeEgress(installation egress, cluster *EgressConfig) egress {
+	if cluster == nil {
+		return installation
+	}
+
+	merged := installation
+	if cluster.DockerhubToken != "" {
+		merged.dockerhubToken = cluster.DockerhubToken
+	}
+	if cluster.Proxy != nil {
+		merged.proxy = proxyConfig{
+			http:    cluster.Proxy.HTTP,
+			https:   cluster.Proxy.HTTPS,
+			noProxy: strings.Join(cluster.Proxy.NoProxy, ","),
+		}
+	}
+	if cluster.RegistryDomain != "" || len(cluster.RegistryMirrors) > 0 {
+		merged.registryMirrors = cluster.RegistryMirrors
+	}
+
+	return merged
+}
+
+func (e egress) apply(params *k8scloudconfig.Params) {
+	params.DockerhubToken = e.dockerhubToken
+	params.Proxy.HTTP = e.proxy.http
+	params.Proxy.HTTPS = e.proxy.https
+	params.Proxy.NoProxy = e.proxy.noProxy
+	params.RegistryMirrors = e.registryMirrors
+}
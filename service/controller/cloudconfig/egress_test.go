@@ -0,0 +1,77 @@
+package cloudconfig
+
+import (
+	"reflect"
+	"testing"
+)
+
+func Test_mergeEgress(t *testing.T) {
+	installation := egress{
+		dockerhubToken: "installation-token",
+		proxy: proxyConfig{
+			http:    "http://proxy.installation.example.com:3128",
+			https:   "http://proxy.installation.example.com:3128",
+			noProxy: "installation.example.com",
+		},
+		registryMirrors: []string{"https://mirror.installation.example.com"},
+	}
+
+	testCases := []struct {
+		Name     string
+		Cluster  *EgressConfig
+		Expected egress
+	}{
+		{
+			Name:     "no cluster specific config",
+			Cluster:  nil,
+			Expected: installation,
+		},
+		{
+			Name: "cluster specific token",
+			Cluster: &EgressConfig{
+				DockerhubToken: "cluster-token",
+			},
+			Expected: egress{
+				dockerhubToken:  "cluster-token",
+				proxy:           installation.proxy,
+				registryMirrors: installation.registryMirrors,
+			},
+		},
+		{
+			Name: "cluster specific proxy replaces installation proxy",
+			Cluster: &EgressConfig{
+				Proxy: &ProxyConfig{
+					HTTPS:   "http://proxy.cluster.example.com:3128",
+					NoProxy: []string{"cluster.example.com", "10.0.0.0/8"},
+				},
+			},
+			Expected: egress{
+				dockerhubToken: installation.dockerhubToken,
+				proxy: proxyConfig{
+					https:   "http://proxy.cluster.example.com:3128",
+					noProxy: "cluster.example.com,10.0.0.0/8",
+				},
+				registryMirrors: installation.registryMirrors,
+			},
+		},
+		{
+			Name: "cluster specific registry drops installation mirrors",
+			Cluster: &EgressConfig{
+				RegistryDomain: "registry.cluster.example.com",
+			},
+			Expected: egress{
+				dockerhubToken: installation.dockerhubToken,
+				proxy:          installation.proxy,
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.Name, func(t *testing.T) {
+			merged := mergeEgress(installation, tc.Cluster)
+			if !reflect.DeepEqual(merged, tc.Expected) {
+				t.Fatalf("expected %#v got %#v", tc.Expected, merged)
+			}
+		})
+	}
+}
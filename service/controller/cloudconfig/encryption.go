@@ -0,0 +1,59 @@
+package cloudconfig
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"strings"
+)
+
+const (
+	// encryptionConfigFile is the key of the API server encryption config
+	// within the files rendered by k8scloudconfig.
+	encryptionConfigFile = "k8s-resource/k8s-encryption-config.yaml"
+	// initialEncryptionKeyName is the name k8scloudconfig gives the initial
+	// encryption key of a cluster.
+	initialEncryptionKeyName = "key1"
+)
+
+// encryptionConfig returns the API server encryption config for the given
+// ordered list of keys. The API server encrypts with the first key and
+// decrypts with any of them. It replaces the config rendered by
+// k8scloudconfig, which only supports a single key, once keys are rotated.
+func encryptionConfig(keys []string, initialKey string) string {
+	var sb strings.Builder
+
+	sb.WriteString(`kind: EncryptionConfiguration
+apiVersion: apiserver.config.k8s.io/v1
+resources:
+  - resources:
+    - secrets
+    providers:
+    - aescbc:
+        keys:
+`)
+
+	for _, k := range keys {
+		sb.WriteString(fmt.Sprintf("        - name: %s\n", encryptionKeyName(k, initialKey)))
+		sb.WriteString(fmt.Sprintf("          secret: %s\n", k))
+	}
+
+	sb.WriteString("    - identity: {}\n")
+
+	return sb.String()
+}
+
+// encryptionKeyName returns the name of the given key within the encryption
+// config. The API server prefixes encrypted data with the name of the key
+// used, so names have to stay the same for as long as a key is in use. Keys
+// therefore get a name derived from their content, except the initial key
+// which keeps the name given by k8scloudconfig.
+func encryptionKeyName(k string, initialKey string) string {
+	if k == initialKey {
+		return initialEncryptionKeyName
+	}
+
+	sum := sha256.Sum256([]byte(k))
+
+	return fmt.Sprintf("key-%s", hex.EncodeToString(sum[:])[:12])
+}
@@ -0,0 +1,26 @@
+package cloudconfig
+
+import (
+	"strings"
+	"testing"
+)
+
+func Test_encryptionConfig(t *testing.T) {
+	rotating := encryptionConfig([]string{"new", "initial"}, "initial")
+	rotated := encryptionConfig([]string{"new"}, "initial")
+
+	if !strings.Contains(rotating, "- name: key1\n          secret: initial\n") {
+		t.Fatalf("expected initial key to keep its name, got\n%s", rotating)
+	}
+	if strings.Index(rotating, "secret: new") > strings.Index(rotating, "secret: initial") {
+		t.Fatalf("expected new key to be used for encryption, got\n%s", rotating)
+	}
+
+	name := encryptionKeyName("new", "initial")
+	if name == initialEncryptionKeyName {
+		t.Fatalf("expected new key to get its own name, got %#q", name)
+	}
+	if !strings.Contains(rotated, "- name: "+name+"\n") {
+		t.Fatalf("expected new key to keep its name %#q after rotation, got\n%s", name, rotated)
+	}
+}
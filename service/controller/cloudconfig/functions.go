@@ -2,6 +2,7 @@ package cloudconfig
 
 import (
 	"context"
+	"sort"
 	"sync"
 
 	"github.com/giantswarm/certs/v3/pkg/certs"
@@ -23,6 +24,29 @@ var workerCertFiles = certFileMapping{
 	certs.CalicoEtcdClientCert: certs.NewFilesCalicoEtcdClient,
 }
 
+// MasterCerts returns the certificates rendered into the ignition of masters.
+func MasterCerts() []certs.Cert {
+	return sortedCerts(masterCertFiles)
+}
+
+// WorkerCerts returns the certificates rendered into the ignition of workers.
+func WorkerCerts() []certs.Cert {
+	return sortedCerts(workerCertFiles)
+}
+
+func sortedCerts(mapping certFileMapping) []certs.Cert {
+	var list []certs.Cert
+	for cert := range mapping {
+		list = append(list, cert)
+	}
+
+	sort.Slice(list, func(i, j int) bool {
+		return list[i] < list[j]
+	})
+
+	return list
+}
+
 func fetchCertFiles(ctx context.Context, searcher certs.Interface, clusterID string, mapping certFileMapping) ([]certs.File, error) {
 	group, groupCtx := errgroup.WithContext(ctx)
 
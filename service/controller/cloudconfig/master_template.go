@@ -2,6 +2,7 @@ package cloudconfig
 
 import (
 	"context"
+	"encoding/base64"
 	"fmt"
 
 	"github.com/giantswarm/apiextensions/v3/pkg/apis/provider/v1alpha1"
@@ -15,6 +16,19 @@ import (
 // NewMasterTemplate generates a new worker cloud config template and returns it
 // as a base64 encoded string.
 func (c *CloudConfig) NewMasterTemplate(ctx context.Context, cr v1alpha1.KVMConfig, data IgnitionTemplateData, node v1alpha1.ClusterNode, nodeIndex int) (string, error) {
+	oidc := c.oidc
+	if data.OIDC != nil {
+		oidc = *data.OIDC
+	}
+
+	audit := mergeAudit(c.audit, data.Audit)
+	{
+		err := validateAudit(audit)
+		if err != nil {
+			return "", microerror.Mask(err)
+		}
+	}
+
 	var extension *masterExtension
 	{
 		certFiles, err := fetchCertFiles(ctx, data.CertsSearcher, key.ClusterID(cr), masterCertFiles)
@@ -22,15 +36,20 @@ func (c *CloudConfig) NewMasterTemplate(ctx context.Context, cr v1alpha1.KVMConf
 			return "", microerror.Mask(err)
 		}
 		extension = &masterExtension{
-			certs:        certFiles,
-			customObject: cr,
-			nodeIndex:    nodeIndex,
+			auditEndpoint: audit.Endpoint,
+			certs:         certFiles,
+			customObject:  cr,
+			nodeIndex:     nodeIndex,
+			oidcCAPEM:     oidc.CAPEM,
 		}
 	}
 
 	var params k8scloudconfig.Params
 	{
 		params.APIServerEncryptionKey = string(data.ClusterKeys.APIServerEncryptionKey)
+		if len(data.EncryptionKeys) > 0 {
+			params.APIServerEncryptionKey = data.EncryptionKeys[0]
+		}
 		params.BaseDomain = key.BaseDomain(cr)
 		params.Cluster = cr.Spec.Cluster
 		// Ingress controller service remains in k8scloudconfig and will be
@@ -44,15 +63,11 @@ func (c *CloudConfig) NewMasterTemplate(ctx context.Context, cr v1alpha1.KVMConf
 		params.Extension = extension
 		params.ImagePullProgressDeadline = key.DefaultImagePullProgressDeadline
 		params.Node = node
-		params.Kubernetes.Apiserver.CommandExtraArgs = c.k8sAPIExtraArgs
+		params.Kubernetes.Apiserver.CommandExtraArgs = append(oidcExtraArgs(oidc), auditExtraArgs()...)
 		params.Images = data.Images
 		params.Versions = data.Versions
-		params.Proxy.HTTP = c.proxy.http
-		params.Proxy.HTTPS = c.proxy.https
-		params.Proxy.NoProxy = c.proxy.noProxy
-		params.RegistryMirrors = c.registryMirrors
 		params.SSOPublicKey = c.ssoPublicKey
-		params.DockerhubToken = c.dockerhubToken
+		mergeEgress(c.egress, data.Egress).apply(&params)
 
 		ignitionPath := k8scloudconfig.GetIgnitionPath(c.ignitionPath)
 		{
@@ -62,6 +77,14 @@ func (c *CloudConfig) NewMasterTemplate(ctx context.Context, cr v1alpha1.KVMConf
 				return "", microerror.Mask(err)
 			}
 		}
+
+		if len(data.EncryptionKeys) > 0 {
+			params.Files[encryptionConfigFile] = base64.StdEncoding.EncodeToString([]byte(encryptionConfig(data.EncryptionKeys, string(data.ClusterKeys.APIServerEncryptionKey))))
+		}
+
+		if audit.Policy != "" {
+			params.Files[auditPolicyFile] = base64.StdEncoding.EncodeToString([]byte(audit.Policy))
+		}
 	}
 
 	var newCloudConfig *k8scloudconfig.CloudConfig
@@ -87,9 +110,11 @@ func (c *CloudConfig) NewMasterTemplate(ctx context.Context, cr v1alpha1.KVMConf
 }
 
 type masterExtension struct {
-	certs        []certs.File
-	customObject v1alpha1.KVMConfig
-	nodeIndex    int
+	auditEndpoint string
+	certs         []certs.File
+	customObject  v1alpha1.KVMConfig
+	nodeIndex     int
+	oidcCAPEM     string
 }
 
 func (e *masterExtension) Files() ([]k8scloudconfig.FileAsset, error) {
@@ -143,6 +168,45 @@ func (e *masterExtension) Files() ([]k8scloudconfig.FileAsset, error) {
 	}
 	filesMeta = append(filesMeta, iscsiConfigFile)
 
+	if e.oidcCAPEM != "" {
+		oidcCAFile := k8scloudconfig.FileMetadata{
+			AssetContent: e.oidcCAPEM,
+			Path:         oidcCAFilePath,
+			Owner: k8scloudconfig.Owner{
+				User: k8scloudconfig.User{
+					Name: FileOwnerUserName,
+				},
+				Group: k8scloudconfig.Group{
+					Name: FileOwnerGroupName,
+				},
+			},
+			Permissions: 0644,
+		}
+		filesMeta = append(filesMeta, oidcCAFile)
+	}
+
+	if e.auditEndpoint != "" {
+		script, err := auditForwarderScript(e.auditEndpoint)
+		if err != nil {
+			return nil, microerror.Mask(err)
+		}
+
+		auditForwarderFile := k8scloudconfig.FileMetadata{
+			AssetContent: script,
+			Path:         auditForwarderPath,
+			Owner: k8scloudconfig.Owner{
+				User: k8scloudconfig.User{
+					Name: FileOwnerUserName,
+				},
+				Group: k8scloudconfig.Group{
+					Name: FileOwnerGroupName,
+				},
+			},
+			Permissions: 0755,
+		}
+		filesMeta = append(filesMeta, auditForwarderFile)
+	}
+
 	calicoKubeKillFile := k8scloudconfig.FileMetadata{
 		AssetContent: calicoKubeKillScript,
 		Path:         "/opt/calico-kube-kill",
@@ -262,6 +326,14 @@ WantedBy=multi-user.target`,
 		},
 	}
 
+	if e.auditEndpoint != "" {
+		unitsMeta = append(unitsMeta, k8scloudconfig.UnitMetadata{
+			AssetContent: auditForwarderUnit,
+			Name:         auditForwarderUnitName,
+			Enabled:      true,
+		})
+	}
+
 	var newUnits []k8scloudconfig.UnitAsset
 
 	for _, fm := range unitsMeta {
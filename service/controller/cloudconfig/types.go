@@ -13,4 +13,23 @@ type IgnitionTemplateData struct {
 	ClusterKeys   randomkeys.Cluster
 	Images        k8scloudconfig.Images
 	Versions      k8scloudconfig.Versions
+
+	// Audit is the cluster specific audit configuration. Fields set in it
+	// replace the respective fields of the installation wide audit
+	// configuration of the cloud config service.
+	Audit *AuditConfig
+	// EncryptionKeys is the ordered list of API server encryption keys of a
+	// cluster whose keys have been rotated. The first key is used to encrypt
+	// secrets, all keys are used to decrypt them. When empty the key of
+	// ClusterKeys is used.
+	EncryptionKeys []string
+
+	// Egress is the cluster specific proxy and registry configuration. Fields
+	// set in it replace the respective installation wide settings of the
+	// cloud config service.
+	Egress *EgressConfig
+
+	// OIDC is the cluster specific OIDC configuration. When set it replaces
+	// the installation wide OIDC configuration of the cloud config service.
+	OIDC *OIDCConfig
 }
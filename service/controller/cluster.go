@@ -8,23 +8,37 @@ import (
 	"github.com/giantswarm/micrologger"
 	"github.com/giantswarm/operatorkit/v5/pkg/controller"
 	workloadcluster "github.com/giantswarm/tenantcluster/v4/pkg/tenantcluster"
-	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/record"
 
 	"github.com/giantswarm/kvm-operator/v4/pkg/label"
 	"github.com/giantswarm/kvm-operator/v4/pkg/project"
+	"github.com/giantswarm/kvm-operator/v4/pkg/shard"
+)
+
+const (
+	// IngressProviderGateway exposes workload cluster API and etcd endpoints
+	// through Gateway API TLSRoute objects.
+	IngressProviderGateway = "gateway"
+	// IngressProviderIngress exposes workload cluster API and etcd endpoints
+	// through Ingress objects.
+	IngressProviderIngress = "ingress"
 )
 
 type ClusterConfig struct {
 	CertsSearcher   certs.Interface
+	EventRecorder   record.EventRecorder
 	K8sClient       k8sclient.Interface
 	Logger          micrologger.Logger
 	WorkloadCluster workloadcluster.Interface
 
+	Audit              ClusterConfigAudit
 	ClusterRoleGeneral string
 	ClusterRolePSP     string
 	DNSServers         string
 	IgnitionPath       string
+	Ingress            ClusterConfigIngress
+	NetworkPolicy      ClusterConfigNetworkPolicy
 	NTPServers         string
 	OIDC               ClusterConfigOIDC
 	Proxy              Proxy
@@ -33,6 +47,40 @@ type ClusterConfig struct {
 	DockerhubToken  string
 	RegistryDomain  string
 	RegistryMirrors []string
+
+	Shard shard.Shard
+}
+
+// ClusterConfigAudit represents the installation wide audit configuration of
+// workload cluster API servers.
+type ClusterConfigAudit struct {
+	Endpoint string
+	Policy   string
+}
+
+// ClusterConfigIngress represents the installation wide configuration of the
+// ingresses and node ports exposing workload clusters.
+type ClusterConfigIngress struct {
+	Class                  string
+	Gateway                ClusterConfigGateway
+	NodePortRange          string
+	PassthroughAnnotations string
+	Provider               string
+}
+
+// ClusterConfigGateway represents the Gateway workload cluster TLS routes are
+// attached to when the gateway ingress provider is used.
+type ClusterConfigGateway struct {
+	Name        string
+	Namespace   string
+	SectionName string
+}
+
+// ClusterConfigNetworkPolicy represents the installation wide configuration of
+// the network policies isolating workload cluster namespaces.
+type ClusterConfigNetworkPolicy struct {
+	IngressControllerSelector string
+	MonitoringSelector        string
 }
 
 // ClusterConfigOIDC represents the configuration of the OIDC authorization
@@ -77,7 +125,7 @@ func NewCluster(config ClusterConfig) (*Cluster, error) {
 			NewRuntimeObjectFunc: func() runtime.Object {
 				return new(v1alpha1.KVMConfig)
 			},
-			Selector: labels.SelectorFromSet(map[string]string{
+			Selector: config.Shard.Selector(map[string]string{
 				label.OperatorVersion: project.Version(),
 			}),
 
@@ -12,16 +12,24 @@ import (
 
 	"github.com/giantswarm/kvm-operator/v4/service/controller/cloudconfig"
 	"github.com/giantswarm/kvm-operator/v4/service/controller/key"
+	"github.com/giantswarm/kvm-operator/v4/service/controller/resource/certstatus"
 	"github.com/giantswarm/kvm-operator/v4/service/controller/resource/clusterrolebinding"
 	"github.com/giantswarm/kvm-operator/v4/service/controller/resource/configmap"
 	"github.com/giantswarm/kvm-operator/v4/service/controller/resource/deployment"
+	"github.com/giantswarm/kvm-operator/v4/service/controller/resource/encryptionkeyrotation"
 	"github.com/giantswarm/kvm-operator/v4/service/controller/resource/ingress"
+	"github.com/giantswarm/kvm-operator/v4/service/controller/resource/lastreconciled"
 	"github.com/giantswarm/kvm-operator/v4/service/controller/resource/namespace"
+	"github.com/giantswarm/kvm-operator/v4/service/controller/resource/networkpolicy"
 	"github.com/giantswarm/kvm-operator/v4/service/controller/resource/nodecontroller"
 	"github.com/giantswarm/kvm-operator/v4/service/controller/resource/nodeindexstatus"
+	"github.com/giantswarm/kvm-operator/v4/service/controller/resource/nodeportstatus"
+	"github.com/giantswarm/kvm-operator/v4/service/controller/resource/portstatus"
 	"github.com/giantswarm/kvm-operator/v4/service/controller/resource/pvc"
 	"github.com/giantswarm/kvm-operator/v4/service/controller/resource/service"
 	"github.com/giantswarm/kvm-operator/v4/service/controller/resource/serviceaccount"
+	"github.com/giantswarm/kvm-operator/v4/service/controller/resource/shardstatus"
+	"github.com/giantswarm/kvm-operator/v4/service/controller/resource/tlsroute"
 )
 
 func newClusterResources(config ClusterConfig) ([]resource.Interface, error) {
@@ -45,6 +53,10 @@ func newClusterResources(config ClusterConfig) ([]resource.Interface, error) {
 		c := cloudconfig.Config{
 			Logger: config.Logger,
 
+			Audit: cloudconfig.AuditConfig{
+				Endpoint: config.Audit.Endpoint,
+				Policy:   config.Audit.Policy,
+			},
 			DockerhubToken: config.DockerhubToken,
 			IgnitionPath:   config.IgnitionPath,
 			OIDC: cloudconfig.OIDCConfig{
@@ -109,6 +121,27 @@ func newClusterResources(config ClusterConfig) ([]resource.Interface, error) {
 		}
 	}
 
+	var networkPolicyResource resource.Interface
+	{
+		c := networkpolicy.Config{
+			K8sClient: config.K8sClient.K8sClient(),
+			Logger:    config.Logger,
+
+			IngressControllerSelector: config.NetworkPolicy.IngressControllerSelector,
+			MonitoringSelector:        config.NetworkPolicy.MonitoringSelector,
+		}
+
+		ops, err := networkpolicy.New(c)
+		if err != nil {
+			return nil, microerror.Mask(err)
+		}
+
+		networkPolicyResource, err = toCRUDResource(config.Logger, ops)
+		if err != nil {
+			return nil, microerror.Mask(err)
+		}
+	}
+
 	var serviceAccountResource resource.Interface
 	{
 		c := serviceaccount.Config{
@@ -127,6 +160,22 @@ func newClusterResources(config ClusterConfig) ([]resource.Interface, error) {
 		}
 	}
 
+	var encryptionKeyRotationResource resource.Interface
+	{
+		c := encryptionkeyrotation.Config{
+			G8sClient:       config.K8sClient.G8sClient(),
+			K8sClient:       config.K8sClient.K8sClient(),
+			KeyWatcher:      randomkeysSearcher,
+			Logger:          config.Logger,
+			WorkloadCluster: config.WorkloadCluster,
+		}
+
+		encryptionKeyRotationResource, err = encryptionkeyrotation.New(c)
+		if err != nil {
+			return nil, microerror.Mask(err)
+		}
+	}
+
 	var configMapResource resource.Interface
 	{
 		c := configmap.Config{
@@ -173,11 +222,39 @@ func newClusterResources(config ClusterConfig) ([]resource.Interface, error) {
 		}
 	}
 
+	// The workload cluster API and etcd endpoints are either exposed through
+	// Ingress objects or through Gateway API TLSRoute objects.
 	var ingressResource resource.Interface
-	{
+	switch config.Ingress.Provider {
+	case IngressProviderGateway:
+		c := tlsroute.Config{
+			DynClient: config.K8sClient.DynClient(),
+			G8sClient: config.K8sClient.G8sClient(),
+			K8sClient: config.K8sClient.K8sClient(),
+			Logger:    config.Logger,
+
+			GatewayName:        config.Ingress.Gateway.Name,
+			GatewayNamespace:   config.Ingress.Gateway.Namespace,
+			GatewaySectionName: config.Ingress.Gateway.SectionName,
+		}
+
+		ops, err := tlsroute.New(c)
+		if err != nil {
+			return nil, microerror.Mask(err)
+		}
+
+		ingressResource, err = toCRUDResource(config.Logger, ops)
+		if err != nil {
+			return nil, microerror.Mask(err)
+		}
+	case IngressProviderIngress, "":
 		c := ingress.Config{
+			DynClient: config.K8sClient.DynClient(),
 			K8sClient: config.K8sClient.K8sClient(),
 			Logger:    config.Logger,
+
+			Class:                  config.Ingress.Class,
+			PassthroughAnnotations: config.Ingress.PassthroughAnnotations,
 		}
 
 		ops, err := ingress.New(c)
@@ -189,6 +266,8 @@ func newClusterResources(config ClusterConfig) ([]resource.Interface, error) {
 		if err != nil {
 			return nil, microerror.Mask(err)
 		}
+	default:
+		return nil, microerror.Maskf(invalidConfigError, "%T.Ingress.Provider must be %#q or %#q, got %#q", config, IngressProviderIngress, IngressProviderGateway, config.Ingress.Provider)
 	}
 
 	var nodeIndexStatusResource resource.Interface
@@ -204,9 +283,54 @@ func newClusterResources(config ClusterConfig) ([]resource.Interface, error) {
 		}
 	}
 
+	var portStatusResource resource.Interface
+	{
+		c := portstatus.Config{
+			G8sClient: config.K8sClient.G8sClient(),
+			Logger:    config.Logger,
+		}
+
+		portStatusResource, err = portstatus.New(c)
+		if err != nil {
+			return nil, microerror.Mask(err)
+		}
+	}
+
+	var nodePortStatusResource resource.Interface
+	{
+		c := nodeportstatus.Config{
+			G8sClient: config.K8sClient.G8sClient(),
+			K8sClient: config.K8sClient.K8sClient(),
+			Logger:    config.Logger,
+
+			NodePortRange: config.Ingress.NodePortRange,
+		}
+
+		nodePortStatusResource, err = nodeportstatus.New(c)
+		if err != nil {
+			return nil, microerror.Mask(err)
+		}
+	}
+
+	var certStatusResource resource.Interface
+	{
+		c := certstatus.Config{
+			CertsSearcher: config.CertsSearcher,
+			EventRecorder: config.EventRecorder,
+			G8sClient:     config.K8sClient.G8sClient(),
+			Logger:        config.Logger,
+		}
+
+		certStatusResource, err = certstatus.New(c)
+		if err != nil {
+			return nil, microerror.Mask(err)
+		}
+	}
+
 	var pvcResource resource.Interface
 	{
 		c := pvc.Config{
+			G8sClient: config.K8sClient.G8sClient(),
 			K8sClient: config.K8sClient.K8sClient(),
 			Logger:    config.Logger,
 		}
@@ -225,6 +349,7 @@ func newClusterResources(config ClusterConfig) ([]resource.Interface, error) {
 	var serviceResource resource.Interface
 	{
 		c := service.Config{
+			G8sClient: config.K8sClient.G8sClient(),
 			K8sClient: config.K8sClient.K8sClient(),
 			Logger:    config.Logger,
 		}
@@ -240,6 +365,22 @@ func newClusterResources(config ClusterConfig) ([]resource.Interface, error) {
 		}
 	}
 
+	var shardStatusResource resource.Interface
+	{
+		c := shardstatus.Config{
+			EventRecorder: config.EventRecorder,
+			G8sClient:     config.K8sClient.G8sClient(),
+			Logger:        config.Logger,
+
+			Shard: config.Shard,
+		}
+
+		shardStatusResource, err = shardstatus.New(c)
+		if err != nil {
+			return nil, microerror.Mask(err)
+		}
+	}
+
 	var statusResource resource.Interface
 	{
 		c := statusresource.ResourceConfig{
@@ -262,6 +403,7 @@ func newClusterResources(config ClusterConfig) ([]resource.Interface, error) {
 	var nodeControllerResource resource.Interface
 	{
 		c := nodecontroller.Config{
+			G8sClient:       config.K8sClient.G8sClient(),
 			K8sClient:       config.K8sClient.CtrlClient(),
 			Logger:          config.Logger,
 			WorkloadCluster: config.WorkloadCluster,
@@ -273,18 +415,37 @@ func newClusterResources(config ClusterConfig) ([]resource.Interface, error) {
 		}
 	}
 
+	var lastReconciledResource resource.Interface
+	{
+		c := lastreconciled.Config{
+			Logger: config.Logger,
+		}
+
+		lastReconciledResource, err = lastreconciled.New(c)
+		if err != nil {
+			return nil, microerror.Mask(err)
+		}
+	}
+
 	resources := []resource.Interface{
+		shardStatusResource,
 		statusResource,
 		nodeIndexStatusResource,
+		portStatusResource,
+		nodePortStatusResource,
+		certStatusResource,
 		clusterRoleBindingResource,
 		namespaceResource,
+		networkPolicyResource,
 		serviceAccountResource,
+		encryptionKeyRotationResource,
 		configMapResource,
 		pvcResource,
 		deploymentResource,
 		ingressResource,
 		serviceResource,
 		nodeControllerResource,
+		lastReconciledResource,
 	}
 
 	{
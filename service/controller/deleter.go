@@ -10,22 +10,32 @@ import (
 	"github.com/giantswarm/micrologger"
 	"github.com/giantswarm/operatorkit/v5/pkg/controller"
 	workloadcluster "github.com/giantswarm/tenantcluster/v4/pkg/tenantcluster"
-	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/record"
 
 	"github.com/giantswarm/kvm-operator/v4/pkg/label"
 	"github.com/giantswarm/kvm-operator/v4/pkg/project"
+	"github.com/giantswarm/kvm-operator/v4/pkg/shard"
 )
 
 const deleterResyncPeriod = time.Minute * 2
 
 type DeleterConfig struct {
 	CertsSearcher   certs.Interface
+	EventRecorder   record.EventRecorder
 	K8sClient       k8sclient.Interface
 	Logger          micrologger.Logger
 	WorkloadCluster workloadcluster.Interface
 
+	OrphanPod   DeleterConfigOrphanPod
 	ProjectName string
+	Shard       shard.Shard
+}
+
+type DeleterConfigOrphanPod struct {
+	GracePeriod time.Duration
+	MaxRecycled int
+	Recycle     bool
 }
 
 type Deleter struct {
@@ -51,7 +61,7 @@ func NewDeleter(config DeleterConfig) (*Deleter, error) {
 			Logger:       config.Logger,
 			Resources:    resources,
 			ResyncPeriod: deleterResyncPeriod,
-			Selector: labels.SelectorFromSet(map[string]string{
+			Selector: config.Shard.Selector(map[string]string{
 				label.OperatorVersion: project.Version(),
 			}),
 			NewRuntimeObjectFunc: func() runtime.Object {
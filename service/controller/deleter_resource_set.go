@@ -15,9 +15,14 @@ func newDeleterResources(config DeleterConfig) ([]resource.Interface, error) {
 	var nodeResource resource.Interface
 	{
 		c := node.Config{
+			EventRecorder:   config.EventRecorder,
 			K8sClient:       config.K8sClient.K8sClient(),
 			Logger:          config.Logger,
 			WorkloadCluster: config.WorkloadCluster,
+
+			OrphanPodGracePeriod: config.OrphanPod.GracePeriod,
+			OrphanPodMaxRecycled: config.OrphanPod.MaxRecycled,
+			OrphanPodRecycle:     config.OrphanPod.Recycle,
 		}
 
 		nodeResource, err = node.New(c)
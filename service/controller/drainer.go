@@ -6,11 +6,11 @@ import (
 	"github.com/giantswarm/micrologger"
 	"github.com/giantswarm/operatorkit/v5/pkg/controller"
 	corev1 "k8s.io/api/core/v1"
-	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/runtime"
 
 	"github.com/giantswarm/kvm-operator/v4/pkg/label"
 	"github.com/giantswarm/kvm-operator/v4/pkg/project"
+	"github.com/giantswarm/kvm-operator/v4/pkg/shard"
 	"github.com/giantswarm/kvm-operator/v4/service/controller/key"
 )
 
@@ -19,6 +19,7 @@ type DrainerConfig struct {
 	Logger    micrologger.Logger
 
 	ProjectName string
+	Shard       shard.Shard
 }
 
 type Drainer struct {
@@ -46,7 +47,7 @@ func NewDrainer(config DrainerConfig) (*Drainer, error) {
 			},
 			Logger:    config.Logger,
 			Resources: resources,
-			Selector: labels.SelectorFromSet(map[string]string{
+			Selector: config.Shard.Selector(map[string]string{
 				key.PodWatcherLabel:   project.Name(),
 				label.OperatorVersion: project.Version(),
 			}),
@@ -7,57 +7,115 @@ import (
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
-	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	"github.com/giantswarm/kvm-operator/v4/service/controller/key"
 )
 
-func (c *Controller) ensureCreated(ctx context.Context, workloadNode corev1.Node) (reconcile.Result, error) {
+func (c *Controller) ensureCreated(ctx context.Context, workloadNode corev1.Node) error {
 	var managementPod corev1.Pod
 	err := c.managementK8sClient.Get(ctx, key.NodePodObjectKey(c.cluster, workloadNode), &managementPod)
 	if errors.IsNotFound(err) {
 		// assume the pod is already deleted
 		c.logger.Debugf(ctx, "node pod not found")
-		return key.RequeueNone, nil
+		return nil
 	} else if err != nil {
-		return key.RequeueErrorShort, microerror.Mask(err)
+		return microerror.Mask(err)
 	}
 
-	condition, shouldUpdate := calculateCreatedPodNodeCondition(workloadNode, managementPod)
-	if !shouldUpdate {
-		return key.RequeueNone, nil
+	kubeletVersion := workloadNode.Status.NodeInfo.KubeletVersion
+	if kubeletVersion != "" && managementPod.GetAnnotations()[key.AnnotationWorkloadClusterKubeletVersion] != kubeletVersion {
+		c.logger.Debugf(ctx, "patching pod kubelet version annotation to %#q", kubeletVersion)
+
+		patch := client.MergeFrom(managementPod.DeepCopy())
+		annotations := managementPod.GetAnnotations()
+		if annotations == nil {
+			annotations = map[string]string{}
+		}
+		annotations[key.AnnotationWorkloadClusterKubeletVersion] = kubeletVersion
+		managementPod.SetAnnotations(annotations)
+
+		err = c.managementK8sClient.Patch(ctx, &managementPod, patch)
+		if errors.IsNotFound(err) {
+			c.logger.Debugf(ctx, "node pod not found")
+			return nil
+		} else if err != nil {
+			return microerror.Mask(err)
+		}
 	}
 
-	c.logger.Debugf(ctx, "patching pod node status condition to %#v", condition)
-	err = c.managementK8sClient.Status().Patch(ctx, &managementPod, podConditionPatch{PodCondition: condition})
+	conditions := calculateCreatedPodNodeConditions(workloadNode, managementPod)
+	if len(conditions) > 0 {
+		c.logger.Debugf(ctx, "patching pod node status conditions to %#v", conditions)
+		err = c.managementK8sClient.Status().Patch(ctx, &managementPod, podConditionsPatch(conditions))
+		if err != nil {
+			return microerror.Mask(err)
+		}
+	}
+
+	err = c.ensureLabelsAndTaints(ctx, workloadNode, managementPod)
 	if err != nil {
-		return key.RequeueErrorShort, microerror.Mask(err)
+		return microerror.Mask(err)
 	}
 
-	return key.RequeueNone, nil
+	return nil
 }
 
-func calculateCreatedPodNodeCondition(node corev1.Node, pod corev1.Pod) (corev1.PodCondition, bool) {
-	nodeCondition, _ := key.FindNodeCondition(node, corev1.NodeReady)
-	currentPodCondition, currentPodConditionFound := key.FindPodCondition(pod, key.WorkloadClusterNodeReady)
+// calculateCreatedPodNodeConditions returns the pod conditions mirroring the
+// state of the given node which changed their status compared to the given pod.
+func calculateCreatedPodNodeConditions(node corev1.Node, pod corev1.Pod) []corev1.PodCondition {
+	var desiredPodConditions []corev1.PodCondition
+	for _, nodeConditionType := range mirroredNodeConditionTypes {
+		nodeCondition, _ := key.FindNodeCondition(node, nodeConditionType)
+
+		desiredPodCondition := corev1.PodCondition{
+			Type:    key.WorkloadClusterNodeConditions[nodeConditionType],
+			Reason:  nodeCondition.Reason,
+			Message: nodeCondition.Message,
+		}
 
-	desiredPodCondition := corev1.PodCondition{
-		Type:    key.WorkloadClusterNodeReady,
-		Reason:  nodeCondition.Reason,
-		Message: nodeCondition.Message,
+		switch nodeCondition.Status {
+		case corev1.ConditionTrue, corev1.ConditionFalse:
+			desiredPodCondition.Status = nodeCondition.Status
+		default:
+			desiredPodCondition.Status = corev1.ConditionUnknown
+		}
+
+		desiredPodConditions = append(desiredPodConditions, desiredPodCondition)
 	}
 
-	switch nodeCondition.Status {
-	case corev1.ConditionTrue, corev1.ConditionFalse:
-		desiredPodCondition.Status = nodeCondition.Status
-	default:
-		desiredPodCondition.Status = corev1.ConditionUnknown
+	{
+		desiredPodCondition := corev1.PodCondition{
+			Type:   key.WorkloadClusterNodeUnschedulable,
+			Reason: "NodeSchedulable",
+			Status: corev1.ConditionFalse,
+		}
+		if node.Spec.Unschedulable {
+			desiredPodCondition.Reason = "NodeCordoned"
+			desiredPodCondition.Message = "node cordoned in workload cluster"
+			desiredPodCondition.Status = corev1.ConditionTrue
+		}
+
+		desiredPodConditions = append(desiredPodConditions, desiredPodCondition)
 	}
 
-	transition := !currentPodConditionFound || desiredPodCondition.Status != currentPodCondition.Status
-	if transition {
-		desiredPodCondition.LastTransitionTime = metav1.Now()
+	return transitionedPodConditions(pod, desiredPodConditions)
+}
+
+// transitionedPodConditions filters the given desired conditions down to the
+// ones whose status differs from the current conditions of the given pod and
+// sets their transition time.
+func transitionedPodConditions(pod corev1.Pod, desiredPodConditions []corev1.PodCondition) []corev1.PodCondition {
+	var transitioned []corev1.PodCondition
+	for _, desiredPodCondition := range desiredPodConditions {
+		currentPodCondition, currentPodConditionFound := key.FindPodCondition(pod, desiredPodCondition.Type)
+
+		transition := !currentPodConditionFound || desiredPodCondition.Status != currentPodCondition.Status
+		if transition {
+			desiredPodCondition.LastTransitionTime = metav1.Now()
+			transitioned = append(transitioned, desiredPodCondition)
+		}
 	}
 
-	return desiredPodCondition, transition
+	return transitioned
 }
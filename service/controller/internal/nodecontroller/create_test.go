@@ -0,0 +1,153 @@
+package nodecontroller
+
+import (
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+
+	"github.com/giantswarm/kvm-operator/v4/service/controller/key"
+)
+
+func Test_calculateCreatedPodNodeConditions(t *testing.T) {
+	testCases := []struct {
+		name     string
+		node     corev1.Node
+		pod      corev1.Pod
+		expected map[corev1.PodConditionType]corev1.ConditionStatus
+	}{
+		{
+			name: "case 0: all conditions are created for a new pod",
+			node: corev1.Node{
+				Status: corev1.NodeStatus{
+					Conditions: []corev1.NodeCondition{
+						{Type: corev1.NodeReady, Status: corev1.ConditionTrue},
+						{Type: corev1.NodeMemoryPressure, Status: corev1.ConditionFalse},
+						{Type: corev1.NodeDiskPressure, Status: corev1.ConditionTrue},
+						{Type: corev1.NodePIDPressure, Status: corev1.ConditionFalse},
+						{Type: corev1.NodeNetworkUnavailable, Status: corev1.ConditionFalse},
+					},
+				},
+			},
+			expected: map[corev1.PodConditionType]corev1.ConditionStatus{
+				key.WorkloadClusterNodeReady:              corev1.ConditionTrue,
+				key.WorkloadClusterNodeMemoryPressure:     corev1.ConditionFalse,
+				key.WorkloadClusterNodeDiskPressure:       corev1.ConditionTrue,
+				key.WorkloadClusterNodePIDPressure:        corev1.ConditionFalse,
+				key.WorkloadClusterNodeNetworkUnavailable: corev1.ConditionFalse,
+				key.WorkloadClusterNodeUnschedulable:      corev1.ConditionFalse,
+			},
+		},
+		{
+			name: "case 1: only changed conditions are returned, missing node conditions are unknown",
+			node: corev1.Node{
+				Spec: corev1.NodeSpec{
+					Unschedulable: true,
+				},
+				Status: corev1.NodeStatus{
+					Conditions: []corev1.NodeCondition{
+						{Type: corev1.NodeReady, Status: corev1.ConditionTrue},
+						{Type: corev1.NodeMemoryPressure, Status: corev1.ConditionTrue},
+					},
+				},
+			},
+			pod: corev1.Pod{
+				Status: corev1.PodStatus{
+					Conditions: []corev1.PodCondition{
+						{Type: key.WorkloadClusterNodeReady, Status: corev1.ConditionTrue},
+						{Type: key.WorkloadClusterNodeMemoryPressure, Status: corev1.ConditionFalse},
+						{Type: key.WorkloadClusterNodeDiskPressure, Status: corev1.ConditionUnknown},
+						{Type: key.WorkloadClusterNodePIDPressure, Status: corev1.ConditionUnknown},
+						{Type: key.WorkloadClusterNodeNetworkUnavailable, Status: corev1.ConditionFalse},
+						{Type: key.WorkloadClusterNodeUnschedulable, Status: corev1.ConditionFalse},
+					},
+				},
+			},
+			expected: map[corev1.PodConditionType]corev1.ConditionStatus{
+				key.WorkloadClusterNodeMemoryPressure:     corev1.ConditionTrue,
+				key.WorkloadClusterNodeNetworkUnavailable: corev1.ConditionUnknown,
+				key.WorkloadClusterNodeUnschedulable:      corev1.ConditionTrue,
+			},
+		},
+		{
+			name: "case 2: nothing is returned when nothing changed",
+			node: corev1.Node{
+				Status: corev1.NodeStatus{
+					Conditions: []corev1.NodeCondition{
+						{Type: corev1.NodeReady, Status: corev1.ConditionFalse, Reason: "KubeletNotReady"},
+					},
+				},
+			},
+			pod: corev1.Pod{
+				Status: corev1.PodStatus{
+					Conditions: []corev1.PodCondition{
+						{Type: key.WorkloadClusterNodeReady, Status: corev1.ConditionFalse},
+						{Type: key.WorkloadClusterNodeMemoryPressure, Status: corev1.ConditionUnknown},
+						{Type: key.WorkloadClusterNodeDiskPressure, Status: corev1.ConditionUnknown},
+						{Type: key.WorkloadClusterNodePIDPressure, Status: corev1.ConditionUnknown},
+						{Type: key.WorkloadClusterNodeNetworkUnavailable, Status: corev1.ConditionUnknown},
+						{Type: key.WorkloadClusterNodeUnschedulable, Status: corev1.ConditionFalse},
+					},
+				},
+			},
+			expected: map[corev1.PodConditionType]corev1.ConditionStatus{},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			conditions := calculateCreatedPodNodeConditions(tc.node, tc.pod)
+
+			result := map[corev1.PodConditionType]corev1.ConditionStatus{}
+			for _, c := range conditions {
+				if c.LastTransitionTime.IsZero() {
+					t.Fatalf("expected last transition time of %#q to be set", c.Type)
+				}
+				result[c.Type] = c.Status
+			}
+
+			if len(result) != len(tc.expected) {
+				t.Fatalf("expected %#v got %#v", tc.expected, result)
+			}
+			for conditionType, status := range tc.expected {
+				if result[conditionType] != status {
+					t.Fatalf("expected %#v got %#v", tc.expected, result)
+				}
+			}
+		})
+	}
+}
+
+func Test_calculateDeletedPodNodeConditions(t *testing.T) {
+	pod := corev1.Pod{
+		Status: corev1.PodStatus{
+			Conditions: []corev1.PodCondition{
+				{Type: key.WorkloadClusterNodeReady, Status: corev1.ConditionTrue},
+				{Type: key.WorkloadClusterNodeMemoryPressure, Status: corev1.ConditionUnknown},
+				{Type: key.WorkloadClusterNodeDiskPressure, Status: corev1.ConditionFalse},
+				{Type: key.WorkloadClusterNodeUnschedulable, Status: corev1.ConditionTrue},
+			},
+		},
+	}
+
+	expected := map[corev1.PodConditionType]corev1.ConditionStatus{
+		key.WorkloadClusterNodeReady:              corev1.ConditionFalse,
+		key.WorkloadClusterNodeDiskPressure:       corev1.ConditionUnknown,
+		key.WorkloadClusterNodePIDPressure:        corev1.ConditionUnknown,
+		key.WorkloadClusterNodeNetworkUnavailable: corev1.ConditionUnknown,
+		key.WorkloadClusterNodeUnschedulable:      corev1.ConditionUnknown,
+	}
+
+	result := map[corev1.PodConditionType]corev1.ConditionStatus{}
+	for _, c := range calculateDeletedPodNodeConditions(pod) {
+		result[c.Type] = c.Status
+	}
+
+	if len(result) != len(expected) {
+		t.Fatalf("expected %#v got %#v", expected, result)
+	}
+	for conditionType, status := range expected {
+		if result[conditionType] != status {
+			t.Fatalf("expected %#v got %#v", expected, result)
+		}
+	}
+}
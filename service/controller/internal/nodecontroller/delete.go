@@ -7,50 +7,70 @@ import (
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
-	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 
 	"github.com/giantswarm/kvm-operator/v4/service/controller/key"
 )
 
-func (c *Controller) ensureDeleted(ctx context.Context, workloadNode corev1.Node) (reconcile.Result, error) {
+func (c *Controller) ensureDeleted(ctx context.Context, name string) error {
+	workloadNode := corev1.Node{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: name,
+		},
+	}
+
 	var managementPod corev1.Pod
 	err := c.managementK8sClient.Get(ctx, key.NodePodObjectKey(c.cluster, workloadNode), &managementPod)
 	if errors.IsNotFound(err) {
 		// assume the pod is already deleted
 		c.logger.Debugf(ctx, "node pod not found")
-		return key.RequeueNone, nil
+		return nil
 	} else if err != nil {
-		return key.RequeueErrorShort, microerror.Mask(err)
+		return microerror.Mask(err)
 	}
 
-	condition, shouldUpdate := calculateDeletedPodNodeCondition(managementPod)
-	if !shouldUpdate {
-		return key.RequeueNone, nil
+	conditions := calculateDeletedPodNodeConditions(managementPod)
+	if len(conditions) == 0 {
+		return nil
 	}
 
-	c.logger.Debugf(ctx, "patching pod node status condition to %#v", condition)
-	err = c.managementK8sClient.Status().Patch(ctx, &managementPod, podConditionPatch{PodCondition: condition})
+	c.logger.Debugf(ctx, "patching pod node status conditions to %#v", conditions)
+	err = c.managementK8sClient.Status().Patch(ctx, &managementPod, podConditionsPatch(conditions))
 	if err != nil {
-		return key.RequeueErrorShort, microerror.Mask(err)
+		return microerror.Mask(err)
 	}
 
-	return key.RequeueNone, nil
+	return nil
 }
 
-func calculateDeletedPodNodeCondition(pod corev1.Pod) (corev1.PodCondition, bool) {
-	currentPodCondition, currentPodConditionFound := key.FindPodCondition(pod, key.WorkloadClusterNodeReady)
+// calculateDeletedPodNodeConditions returns the pod conditions which changed
+// their status once the node of the given pod got deleted. The node is not
+// ready anymore and the state of all other mirrored conditions is unknown.
+func calculateDeletedPodNodeConditions(pod corev1.Pod) []corev1.PodCondition {
+	desiredPodConditions := []corev1.PodCondition{
+		{
+			Type:    key.WorkloadClusterNodeReady,
+			Reason:  "NodeDeleted",
+			Message: "node deleted in workload cluster",
+			Status:  corev1.ConditionFalse,
+		},
+	}
 
-	desiredPodCondition := corev1.PodCondition{
-		Type:    key.WorkloadClusterNodeReady,
-		Reason:  "NodeDeleted",
-		Message: "node deleted in workload cluster",
-		Status:  corev1.ConditionFalse,
+	var podConditionTypes []corev1.PodConditionType
+	for _, nodeConditionType := range mirroredNodeConditionTypes {
+		if nodeConditionType != corev1.NodeReady {
+			podConditionTypes = append(podConditionTypes, key.WorkloadClusterNodeConditions[nodeConditionType])
+		}
 	}
+	podConditionTypes = append(podConditionTypes, key.WorkloadClusterNodeUnschedulable)
 
-	transition := !currentPodConditionFound || desiredPodCondition.Status != currentPodCondition.Status
-	if transition {
-		desiredPodCondition.LastTransitionTime = metav1.Now()
+	for _, podConditionType := range podConditionTypes {
+		desiredPodConditions = append(desiredPodConditions, corev1.PodCondition{
+			Type:    podConditionType,
+			Reason:  "NodeDeleted",
+			Message: "node deleted in workload cluster",
+			Status:  corev1.ConditionUnknown,
+		})
 	}
 
-	return desiredPodCondition, transition
+	return transitionedPodConditions(pod, desiredPodConditions)
 }
@@ -0,0 +1,169 @@
+package nodecontroller
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/giantswarm/microerror"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/giantswarm/kvm-operator/v4/service/controller/key"
+)
+
+// ensureLabelsAndTaints applies the labels and taints configured for the given
+// node via key.AnnotationNodeLabelsAndTaints. Labels and taints applied before
+// are tracked on the node, so that drift is corrected and labels and taints
+// removed from the configuration are removed from the node as well.
+func (c *Controller) ensureLabelsAndTaints(ctx context.Context, workloadNode corev1.Node, managementPod corev1.Pod) error {
+	desired, err := key.NodeLabelsAndTaintsFor(c.cluster, managementPod.GetLabels()[key.LabelApp], managementPod.GetLabels()["node"])
+	if err != nil {
+		return microerror.Mask(err)
+	}
+
+	updated, shouldUpdate := calculateNodeLabelsAndTaints(workloadNode, desired)
+	if !shouldUpdate {
+		return nil
+	}
+
+	c.logger.Debugf(ctx, "updating node labels and taints")
+	_, err = c.workloadK8sClient.CoreV1().Nodes().Update(ctx, &updated, metav1.UpdateOptions{})
+	if err != nil {
+		return microerror.Mask(err)
+	}
+	c.logger.Debugf(ctx, "updated node labels and taints")
+
+	return nil
+}
+
+// calculateNodeLabelsAndTaints returns the given node with the desired labels
+// and taints applied and the labels and taints applied previously, but not
+// desired anymore, removed. False is returned in case the node does not need
+// to be updated.
+func calculateNodeLabelsAndTaints(node corev1.Node, desired key.NodeLabelsAndTaints) (corev1.Node, bool) {
+	updated := *node.DeepCopy()
+
+	labels := map[string]string{}
+	for k, v := range updated.GetLabels() {
+		labels[k] = v
+	}
+	for _, k := range splitManaged(updated.GetAnnotations()[key.AnnotationManagedNodeLabels]) {
+		if _, ok := desired.Labels[k]; !ok {
+			delete(labels, k)
+		}
+	}
+	var managedLabels []string
+	for k, v := range desired.Labels {
+		labels[k] = v
+		managedLabels = append(managedLabels, k)
+	}
+
+	var taints []corev1.Taint
+	{
+		managed := map[string]bool{}
+		for _, id := range splitManaged(updated.GetAnnotations()[key.AnnotationManagedNodeTaints]) {
+			managed[id] = true
+		}
+
+		for _, t := range updated.Spec.Taints {
+			if managed[taintID(t)] || containsTaint(desired.Taints, t) {
+				continue
+			}
+			taints = append(taints, t)
+		}
+	}
+	var managedTaints []string
+	for _, t := range desired.Taints {
+		for _, current := range updated.Spec.Taints {
+			// Keep the time a NoExecute taint has been added, since it defines
+			// when tolerating pods get evicted.
+			if current.MatchTaint(&t) && current.Value == t.Value {
+				t.TimeAdded = current.TimeAdded
+			}
+		}
+		taints = append(taints, t)
+		managedTaints = append(managedTaints, taintID(t))
+	}
+
+	annotations := map[string]string{}
+	for k, v := range updated.GetAnnotations() {
+		annotations[k] = v
+	}
+	setManaged(annotations, key.AnnotationManagedNodeLabels, managedLabels)
+	setManaged(annotations, key.AnnotationManagedNodeTaints, managedTaints)
+
+	changed := !reflect.DeepEqual(labels, nonNilMap(updated.GetLabels())) ||
+		!reflect.DeepEqual(annotations, nonNilMap(updated.GetAnnotations())) ||
+		!equalTaints(taints, updated.Spec.Taints)
+
+	updated.SetLabels(labels)
+	updated.SetAnnotations(annotations)
+	updated.Spec.Taints = taints
+
+	return updated, changed
+}
+
+func containsTaint(taints []corev1.Taint, t corev1.Taint) bool {
+	for _, c := range taints {
+		if c.MatchTaint(&t) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// equalTaints compares the given taints ignoring their order.
+func equalTaints(a, b []corev1.Taint) bool {
+	if len(a) != len(b) {
+		return false
+	}
+
+	for _, t := range a {
+		found := false
+		for _, c := range b {
+			if c.MatchTaint(&t) && c.Value == t.Value && reflect.DeepEqual(c.TimeAdded, t.TimeAdded) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+
+	return true
+}
+
+func nonNilMap(m map[string]string) map[string]string {
+	if m == nil {
+		return map[string]string{}
+	}
+
+	return m
+}
+
+func setManaged(annotations map[string]string, annotation string, managed []string) {
+	if len(managed) == 0 {
+		delete(annotations, annotation)
+		return
+	}
+
+	sort.Strings(managed)
+	annotations[annotation] = strings.Join(managed, ",")
+}
+
+func splitManaged(v string) []string {
+	if v == "" {
+		return nil
+	}
+
+	return strings.Split(v, ",")
+}
+
+func taintID(t corev1.Taint) string {
+	return fmt.Sprintf("%s:%s", t.Key, t.Effect)
+}
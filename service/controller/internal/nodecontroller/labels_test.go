@@ -0,0 +1,140 @@
+package nodecontroller
+
+import (
+	"reflect"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/giantswarm/kvm-operator/v4/service/controller/key"
+)
+
+func Test_calculateNodeLabelsAndTaints(t *testing.T) {
+	testCases := []struct {
+		name                string
+		node                corev1.Node
+		desired             key.NodeLabelsAndTaints
+		expectedLabels      map[string]string
+		expectedAnnotations map[string]string
+		expectedTaints      []corev1.Taint
+		expectedUpdate      bool
+	}{
+		{
+			name: "case 0: nothing desired and nothing managed",
+			node: corev1.Node{
+				ObjectMeta: metav1.ObjectMeta{
+					Labels: map[string]string{"role": "worker"},
+				},
+			},
+			expectedLabels:      map[string]string{"role": "worker"},
+			expectedAnnotations: map[string]string{},
+			expectedUpdate:      false,
+		},
+		{
+			name: "case 1: desired labels and taints are applied",
+			node: corev1.Node{
+				ObjectMeta: metav1.ObjectMeta{
+					Labels: map[string]string{"role": "worker"},
+				},
+				Spec: corev1.NodeSpec{
+					Taints: []corev1.Taint{
+						{Key: "node.kubernetes.io/unschedulable", Effect: corev1.TaintEffectNoSchedule},
+					},
+				},
+			},
+			desired: key.NodeLabelsAndTaints{
+				Labels: map[string]string{"team": "a"},
+				Taints: []corev1.Taint{
+					{Key: "dedicated", Value: "a", Effect: corev1.TaintEffectNoSchedule},
+				},
+			},
+			expectedLabels: map[string]string{"role": "worker", "team": "a"},
+			expectedAnnotations: map[string]string{
+				key.AnnotationManagedNodeLabels: "team",
+				key.AnnotationManagedNodeTaints: "dedicated:NoSchedule",
+			},
+			expectedTaints: []corev1.Taint{
+				{Key: "node.kubernetes.io/unschedulable", Effect: corev1.TaintEffectNoSchedule},
+				{Key: "dedicated", Value: "a", Effect: corev1.TaintEffectNoSchedule},
+			},
+			expectedUpdate: true,
+		},
+		{
+			name: "case 2: drift is corrected and labels and taints not desired anymore are removed",
+			node: corev1.Node{
+				ObjectMeta: metav1.ObjectMeta{
+					Annotations: map[string]string{
+						key.AnnotationManagedNodeLabels: "team,tier",
+						key.AnnotationManagedNodeTaints: "dedicated:NoSchedule",
+					},
+					Labels: map[string]string{"role": "worker", "team": "b", "tier": "x", "zone": "z"},
+				},
+				Spec: corev1.NodeSpec{
+					Taints: []corev1.Taint{
+						{Key: "dedicated", Value: "a", Effect: corev1.TaintEffectNoSchedule},
+					},
+				},
+			},
+			desired: key.NodeLabelsAndTaints{
+				Labels: map[string]string{"team": "a"},
+			},
+			expectedLabels: map[string]string{"role": "worker", "team": "a", "zone": "z"},
+			expectedAnnotations: map[string]string{
+				key.AnnotationManagedNodeLabels: "team",
+			},
+			expectedUpdate: true,
+		},
+		{
+			name: "case 3: nothing changes when the node is up to date",
+			node: corev1.Node{
+				ObjectMeta: metav1.ObjectMeta{
+					Annotations: map[string]string{
+						key.AnnotationManagedNodeLabels: "team",
+						key.AnnotationManagedNodeTaints: "dedicated:NoSchedule",
+					},
+					Labels: map[string]string{"role": "worker", "team": "a"},
+				},
+				Spec: corev1.NodeSpec{
+					Taints: []corev1.Taint{
+						{Key: "dedicated", Value: "a", Effect: corev1.TaintEffectNoSchedule},
+					},
+				},
+			},
+			desired: key.NodeLabelsAndTaints{
+				Labels: map[string]string{"team": "a"},
+				Taints: []corev1.Taint{
+					{Key: "dedicated", Value: "a", Effect: corev1.TaintEffectNoSchedule},
+				},
+			},
+			expectedLabels: map[string]string{"role": "worker", "team": "a"},
+			expectedAnnotations: map[string]string{
+				key.AnnotationManagedNodeLabels: "team",
+				key.AnnotationManagedNodeTaints: "dedicated:NoSchedule",
+			},
+			expectedTaints: []corev1.Taint{
+				{Key: "dedicated", Value: "a", Effect: corev1.TaintEffectNoSchedule},
+			},
+			expectedUpdate: false,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			updated, shouldUpdate := calculateNodeLabelsAndTaints(tc.node, tc.desired)
+
+			if shouldUpdate != tc.expectedUpdate {
+				t.Fatalf("expected update %t got %t", tc.expectedUpdate, shouldUpdate)
+			}
+			if !reflect.DeepEqual(updated.GetLabels(), tc.expectedLabels) {
+				t.Fatalf("expected labels %#v got %#v", tc.expectedLabels, updated.GetLabels())
+			}
+			if !reflect.DeepEqual(updated.GetAnnotations(), tc.expectedAnnotations) {
+				t.Fatalf("expected annotations %#v got %#v", tc.expectedAnnotations, updated.GetAnnotations())
+			}
+			if !reflect.DeepEqual(updated.Spec.Taints, tc.expectedTaints) {
+				t.Fatalf("expected taints %#v got %#v", tc.expectedTaints, updated.Spec.Taints)
+			}
+		})
+	}
+}
@@ -10,18 +10,18 @@ import (
 	"k8s.io/apimachinery/pkg/util/json"
 )
 
-type podConditionPatch struct {
-	v1.PodCondition
-}
+// podConditionsPatch merges the given conditions into the pod status. Pod
+// conditions are merged by type so conditions not part of the patch are kept.
+type podConditionsPatch []v1.PodCondition
 
-func (p podConditionPatch) Type() types.PatchType {
+func (p podConditionsPatch) Type() types.PatchType {
 	return types.StrategicMergePatchType
 }
 
-func (p podConditionPatch) Data(_ runtime.Object) ([]byte, error) {
-	conditionJSON, err := json.Marshal(p)
+func (p podConditionsPatch) Data(_ runtime.Object) ([]byte, error) {
+	conditionsJSON, err := json.Marshal([]v1.PodCondition(p))
 	if err != nil {
 		return nil, microerror.Mask(err)
 	}
-	return []byte(fmt.Sprintf("{\"status\":{\"conditions\":[%s]}}", conditionJSON)), nil
+	return []byte(fmt.Sprintf("{\"status\":{\"conditions\":%s}}", conditionsJSON)), nil
 }
@@ -0,0 +1,23 @@
+package workloadinformer
+
+import (
+	"github.com/giantswarm/microerror"
+)
+
+var invalidConfigError = &microerror.Error{
+	Kind: "invalidConfigError",
+}
+
+// IsInvalidConfig asserts invalidConfigError.
+func IsInvalidConfig(err error) bool {
+	return microerror.Cause(err) == invalidConfigError
+}
+
+var panicError = &microerror.Error{
+	Kind: "panicError",
+}
+
+// IsPanic asserts panicError.
+func IsPanic(err error) bool {
+	return microerror.Cause(err) == panicError
+}
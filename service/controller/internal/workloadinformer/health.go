@@ -0,0 +1,79 @@
+package workloadinformer
+
+import (
+	"time"
+)
+
+const (
+	// HealthHealthy means the node informer of a workload cluster is synced
+	// and its last request against the workload cluster API succeeded.
+	HealthHealthy = "Healthy"
+	// HealthPanicked means the handler of a workload cluster panicked and did
+	// not successfully reconcile any node since.
+	HealthPanicked = "Panicked"
+	// HealthSyncing means the node informer of a workload cluster did not yet
+	// list the nodes of the workload cluster.
+	HealthSyncing = "Syncing"
+	// HealthUnhealthy means the last request of the node informer against the
+	// workload cluster API failed.
+	HealthUnhealthy = "Unhealthy"
+)
+
+// Health describes the connection of the node informer of a workload cluster
+// to the workload cluster API.
+type Health struct {
+	// LastError is the error of the last failed request against the workload
+	// cluster API.
+	LastError error
+	// LastErrorTime is the time of the last failed request against the
+	// workload cluster API.
+	LastErrorTime time.Time
+	// LastPanic is the recovered panic of the last handler call which
+	// panicked.
+	LastPanic error
+	// LastPanicTime is the time of the last handler call which panicked.
+	LastPanicTime time.Time
+	// LastReconcileTime is the time of the last handler call which succeeded.
+	LastReconcileTime time.Time
+	// LastSuccessTime is the time of the last successful request against the
+	// workload cluster API.
+	LastSuccessTime time.Time
+	// Started is the time the node informer was started.
+	Started time.Time
+	// Synced is true once the node informer listed the nodes of the workload
+	// cluster.
+	Synced bool
+}
+
+// Status returns HealthHealthy, HealthPanicked, HealthSyncing or
+// HealthUnhealthy.
+func (h Health) Status() string {
+	if !h.LastErrorTime.IsZero() && !h.LastSuccessTime.After(h.LastErrorTime) {
+		return HealthUnhealthy
+	}
+	if !h.Synced {
+		return HealthSyncing
+	}
+	if !h.LastPanicTime.IsZero() && !h.LastReconcileTime.After(h.LastPanicTime) {
+		return HealthPanicked
+	}
+
+	return HealthHealthy
+}
+
+// UnhealthySince returns how long the node informer has not been able to talk
+// to the workload cluster API. Zero is returned for informers talking to the
+// workload cluster API just fine, even if their handler panicked, since
+// restarting the informer does not help with that.
+func (h Health) UnhealthySince(now time.Time) time.Duration {
+	if status := h.Status(); status == HealthHealthy || status == HealthPanicked {
+		return 0
+	}
+
+	since := h.LastSuccessTime
+	if since.IsZero() {
+		since = h.Started
+	}
+
+	return now.Sub(since)
+}
@@ -0,0 +1,86 @@
+package workloadinformer
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func Test_Health_Status(t *testing.T) {
+	now := time.Date(2021, 6, 1, 12, 0, 0, 0, time.UTC)
+
+	testCases := []struct {
+		name                   string
+		health                 Health
+		expectedStatus         string
+		expectedUnhealthySince time.Duration
+	}{
+		{
+			name: "case 0: synced",
+			health: Health{
+				LastSuccessTime: now.Add(-time.Minute),
+				Started:         now.Add(-time.Hour),
+				Synced:          true,
+			},
+			expectedStatus: HealthHealthy,
+		},
+		{
+			name: "case 1: not synced yet",
+			health: Health{
+				Started: now.Add(-time.Minute),
+			},
+			expectedStatus:         HealthSyncing,
+			expectedUnhealthySince: time.Minute,
+		},
+		{
+			name: "case 2: failed request",
+			health: Health{
+				LastError:       errors.New("connection refused"),
+				LastErrorTime:   now.Add(-time.Minute),
+				LastSuccessTime: now.Add(-time.Hour),
+				Started:         now.Add(-2 * time.Hour),
+				Synced:          true,
+			},
+			expectedStatus:         HealthUnhealthy,
+			expectedUnhealthySince: time.Hour,
+		},
+		{
+			name: "case 3: handler panicked",
+			health: Health{
+				LastPanic:         errors.New("test panic"),
+				LastPanicTime:     now.Add(-time.Minute),
+				LastReconcileTime: now.Add(-time.Hour),
+				LastSuccessTime:   now.Add(-time.Hour),
+				Started:           now.Add(-2 * time.Hour),
+				Synced:            true,
+			},
+			expectedStatus: HealthPanicked,
+		},
+		{
+			name: "case 4: handler reconciled after panic",
+			health: Health{
+				LastPanic:         errors.New("test panic"),
+				LastPanicTime:     now.Add(-time.Hour),
+				LastReconcileTime: now.Add(-time.Minute),
+				LastSuccessTime:   now.Add(-time.Hour),
+				Started:           now.Add(-2 * time.Hour),
+				Synced:            true,
+			},
+			expectedStatus: HealthHealthy,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			status := tc.health.Status()
+			if status != tc.expectedStatus {
+				t.Fatalf("expected %#q, got %#q", tc.expectedStatus, status)
+			}
+
+			since := tc.health.UnhealthySince(now)
+			if since != tc.expectedUnhealthySince {
+				t.Fatalf("expected %s, got %s", tc.expectedUnhealthySince, since)
+			}
+		})
+	}
+}
@@ -0,0 +1,212 @@
+package workloadinformer
+
+import (
+	"context"
+	"reflect"
+	"sync"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/watch"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/cache"
+)
+
+// Handler reconciles the nodes of a workload cluster.
+type Handler interface {
+	// Reconcile is called for every node of the workload cluster matching the
+	// selector whenever it changes and once per resync period. The node is nil
+	// in case it got deleted.
+	Reconcile(ctx context.Context, name string, node *corev1.Node) error
+}
+
+// Cluster describes the workload cluster a node informer is run for.
+type Cluster struct {
+	Handler    Handler
+	K8sClient  kubernetes.Interface
+	RESTConfig *rest.Config
+	// Revision identifies the configuration the handler applies to the nodes.
+	// All nodes are reconciled again once it changes.
+	Revision string
+	Selector labels.Selector
+}
+
+// equalConnection returns true in case the given clusters can share a node
+// informer, which is when they talk to the same workload cluster API with the
+// same credentials and watch the same nodes.
+func equalConnection(a, b Cluster) bool {
+	if a.Selector.String() != b.Selector.String() {
+		return false
+	}
+
+	// rest.Config equality based on setup function here https://github.com/giantswarm/tenantcluster/blob/3531fb3d3698c0a69ab51f42c95207cb80761529/pkg/tenantcluster/tenantcluster.go#L72-L82
+	if a.RESTConfig == nil || b.RESTConfig == nil {
+		return a.RESTConfig == b.RESTConfig
+	}
+	if a.RESTConfig.Host != b.RESTConfig.Host {
+		return false
+	}
+	if !reflect.DeepEqual(a.RESTConfig.TLSClientConfig, b.RESTConfig.TLSClientConfig) {
+		return false
+	}
+
+	return true
+}
+
+// clusterInformer is the node informer of a single workload cluster.
+type clusterInformer struct {
+	enqueue  func(item)
+	key      types.NamespacedName
+	informer cache.SharedIndexInformer
+	stopped  chan struct{}
+
+	mutex   sync.Mutex
+	cluster Cluster
+	health  Health
+}
+
+func newClusterInformer(key types.NamespacedName, cluster Cluster, resyncPeriod time.Duration, enqueue func(item)) *clusterInformer {
+	c := &clusterInformer{
+		enqueue: enqueue,
+		key:     key,
+		stopped: make(chan struct{}),
+		cluster: cluster,
+		health: Health{
+			Started: time.Now(),
+		},
+	}
+
+	selector := cluster.Selector.String()
+	lw := &cache.ListWatch{
+		ListFunc: func(options metav1.ListOptions) (runtime.Object, error) {
+			options.LabelSelector = selector
+			list, err := cluster.K8sClient.CoreV1().Nodes().List(context.Background(), options)
+			c.recordRequest(err)
+			return list, err
+		},
+		WatchFunc: func(options metav1.ListOptions) (watch.Interface, error) {
+			options.LabelSelector = selector
+			w, err := cluster.K8sClient.CoreV1().Nodes().Watch(context.Background(), options)
+			c.recordRequest(err)
+			return w, err
+		},
+	}
+
+	c.informer = cache.NewSharedIndexInformer(lw, &corev1.Node{}, resyncPeriod, cache.Indexers{})
+	c.informer.AddEventHandler(cache.ResourceEventHandlerFuncs{
+		AddFunc: func(obj interface{}) {
+			c.enqueueObject(obj, enqueue)
+		},
+		UpdateFunc: func(_, obj interface{}) {
+			c.enqueueObject(obj, enqueue)
+		},
+		DeleteFunc: func(obj interface{}) {
+			c.enqueueObject(obj, enqueue)
+		},
+	})
+
+	return c
+}
+
+func (c *clusterInformer) enqueueObject(obj interface{}, enqueue func(item)) {
+	name, err := cache.DeletionHandlingMetaNamespaceKeyFunc(obj)
+	if err != nil {
+		return
+	}
+
+	enqueue(item{cluster: c.key, node: name})
+}
+
+// requeue enqueues all nodes known to the informer.
+func (c *clusterInformer) requeue() {
+	for _, obj := range c.informer.GetStore().List() {
+		c.enqueueObject(obj, c.enqueue)
+	}
+}
+
+func (c *clusterInformer) run() {
+	go c.informer.Run(c.stopped)
+
+	if cache.WaitForCacheSync(c.stopped, c.informer.HasSynced) {
+		c.mutex.Lock()
+		c.health.Synced = true
+		c.mutex.Unlock()
+	}
+}
+
+func (c *clusterInformer) stop() {
+	close(c.stopped)
+}
+
+// getCluster returns the cluster, which is replaced when the handler of a
+// running informer changes.
+func (c *clusterInformer) getCluster() Cluster {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	return c.cluster
+}
+
+func (c *clusterInformer) setCluster(cluster Cluster) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	c.cluster = cluster
+}
+
+func (c *clusterInformer) getHealth() Health {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	return c.health
+}
+
+func (c *clusterInformer) recordRequest(err error) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	if err != nil {
+		c.health.LastError = err
+		c.health.LastErrorTime = time.Now()
+	} else {
+		c.health.LastSuccessTime = time.Now()
+	}
+}
+
+// recordReconcile records the result of a handler call. Errors returned by
+// the handler are retried and only panics are recorded.
+func (c *clusterInformer) recordReconcile(panicErr error) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	if panicErr != nil {
+		c.health.LastPanic = panicErr
+		c.health.LastPanicTime = time.Now()
+	} else {
+		c.health.LastReconcileTime = time.Now()
+	}
+}
+
+// getNode returns the node with the given name from the informer cache, or
+// nil in case the node does not exist.
+func (c *clusterInformer) getNode(name string) (*corev1.Node, error) {
+	obj, exists, err := c.informer.GetIndexer().GetByKey(name)
+	if err != nil {
+		return nil, err
+	}
+	if !exists {
+		return nil, nil
+	}
+
+	node, ok := obj.(*corev1.Node)
+	if !ok {
+		return nil, nil
+	}
+
+	return node, nil
+}
@@ -0,0 +1,255 @@
+package workloadinformer
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/giantswarm/microerror"
+	"github.com/giantswarm/micrologger"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/util/workqueue"
+)
+
+// item is a node of a workload cluster queued for reconciliation.
+type item struct {
+	cluster types.NamespacedName
+	node    string
+}
+
+type Config struct {
+	Logger micrologger.Logger
+
+	// Name is used to identify the work queue of the manager.
+	Name string
+	// RestartAfter is the duration after which the node informer of a workload
+	// cluster which cannot talk to the workload cluster API is restarted with
+	// the next call to Ensure.
+	RestartAfter time.Duration
+	// ResyncPeriod is the period after which all nodes of all workload clusters
+	// are reconciled again.
+	ResyncPeriod time.Duration
+	// Workers is the number of nodes reconciled in parallel across all
+	// workload clusters.
+	Workers int
+}
+
+// Manager runs one lightweight node informer per workload cluster. All
+// informers feed a single work queue, which is drained by a fixed number of
+// workers. Rate limiting and backoff of failed reconciliations are therefore
+// shared across all workload clusters.
+type Manager struct {
+	logger micrologger.Logger
+
+	queue        workqueue.RateLimitingInterface
+	restartAfter time.Duration
+	resyncPeriod time.Duration
+	workers      int
+
+	bootOnce  sync.Once
+	mutex     sync.Mutex
+	informers map[types.NamespacedName]*clusterInformer
+}
+
+func New(config Config) (*Manager, error) {
+	if config.Logger == nil {
+		return nil, microerror.Maskf(invalidConfigError, "%T.Logger must not be empty", config)
+	}
+
+	if config.Name == "" {
+		return nil, microerror.Maskf(invalidConfigError, "%T.Name must not be empty", config)
+	}
+	if config.RestartAfter <= 0 {
+		return nil, microerror.Maskf(invalidConfigError, "%T.RestartAfter must be greater than 0", config)
+	}
+	if config.ResyncPeriod <= 0 {
+		return nil, microerror.Maskf(invalidConfigError, "%T.ResyncPeriod must be greater than 0", config)
+	}
+	if config.Workers <= 0 {
+		return nil, microerror.Maskf(invalidConfigError, "%T.Workers must be greater than 0", config)
+	}
+
+	m := &Manager{
+		logger: config.Logger,
+
+		queue:        workqueue.NewNamedRateLimitingQueue(workqueue.DefaultControllerRateLimiter(), config.Name),
+		restartAfter: config.RestartAfter,
+		resyncPeriod: config.ResyncPeriod,
+		workers:      config.Workers,
+
+		informers: map[types.NamespacedName]*clusterInformer{},
+	}
+
+	return m, nil
+}
+
+// Boot starts the workers of the manager. It does not block.
+func (m *Manager) Boot() {
+	m.bootOnce.Do(func() {
+		for i := 0; i < m.workers; i++ {
+			go func() {
+				for m.processNextItem() {
+				}
+			}()
+		}
+	})
+}
+
+// Stop stops the node informers of all workload clusters and the workers.
+func (m *Manager) Stop() {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	for key, informer := range m.informers {
+		informer.stop()
+		delete(m.informers, key)
+	}
+
+	m.queue.ShutDown()
+}
+
+// Ensure ensures a node informer is running for the given workload cluster. A
+// running informer is kept as long as it talks to the same workload cluster
+// API and did not fail to do so for longer than the configured restart
+// duration. Otherwise it is replaced by a new informer.
+func (m *Manager) Ensure(ctx context.Context, key types.NamespacedName, cluster Cluster) error {
+	if cluster.Handler == nil {
+		return microerror.Maskf(invalidConfigError, "%T.Handler must not be empty", cluster)
+	}
+	if cluster.K8sClient == nil {
+		return microerror.Maskf(invalidConfigError, "%T.K8sClient must not be empty", cluster)
+	}
+	if cluster.Selector == nil {
+		return microerror.Maskf(invalidConfigError, "%T.Selector must not be empty", cluster)
+	}
+
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	if current, ok := m.informers[key]; ok {
+		restart := false
+		if !equalConnection(current.getCluster(), cluster) {
+			m.logger.Debugf(ctx, "workload cluster connection changed")
+			restart = true
+		} else if since := current.getHealth().UnhealthySince(time.Now()); since > m.restartAfter {
+			m.logger.Debugf(ctx, "node informer has been unhealthy for %s", since.Round(time.Second))
+			restart = true
+		}
+
+		if !restart {
+			revisionChanged := current.getCluster().Revision != cluster.Revision
+			current.setCluster(cluster)
+			if revisionChanged {
+				m.logger.Debugf(ctx, "requeueing nodes for changed revision")
+				current.requeue()
+			}
+			return nil
+		}
+
+		m.logger.Debugf(ctx, "stopping node informer")
+		current.stop()
+		delete(m.informers, key)
+	}
+
+	m.logger.Debugf(ctx, "starting node informer")
+
+	informer := newClusterInformer(key, cluster, m.resyncPeriod, m.enqueue)
+	go informer.run()
+	m.informers[key] = informer
+
+	m.logger.Debugf(ctx, "started node informer")
+
+	return nil
+}
+
+// Remove stops the node informer of the given workload cluster.
+func (m *Manager) Remove(ctx context.Context, key types.NamespacedName) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	if informer, ok := m.informers[key]; ok {
+		informer.stop()
+		delete(m.informers, key)
+		m.logger.Debugf(ctx, "stopped node informer")
+	} else {
+		m.logger.Debugf(ctx, "node informer not found")
+	}
+}
+
+// Health returns the health of the node informer of the given workload
+// cluster. False is returned in case no informer is running for it.
+func (m *Manager) Health(key types.NamespacedName) (Health, bool) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	informer, ok := m.informers[key]
+	if !ok {
+		return Health{}, false
+	}
+
+	return informer.getHealth(), true
+}
+
+func (m *Manager) enqueue(i item) {
+	m.queue.Add(i)
+}
+
+func (m *Manager) processNextItem() bool {
+	obj, shutdown := m.queue.Get()
+	if shutdown {
+		return false
+	}
+	defer m.queue.Done(obj)
+
+	i, ok := obj.(item)
+	if !ok {
+		m.queue.Forget(obj)
+		return true
+	}
+
+	m.mutex.Lock()
+	informer, ok := m.informers[i.cluster]
+	m.mutex.Unlock()
+	if !ok {
+		// The workload cluster got removed in the meantime.
+		m.queue.Forget(obj)
+		return true
+	}
+
+	err := m.reconcile(informer, i.node)
+	if err != nil {
+		m.logger.Errorf(context.Background(), err, "failed to reconcile node %#q of workload cluster %#q", i.node, i.cluster.String())
+		m.queue.AddRateLimited(obj)
+		return true
+	}
+
+	m.queue.Forget(obj)
+
+	return true
+}
+
+// reconcile calls the handler of the given workload cluster for the given
+// node. Panics of the handler are recovered, so that a single workload cluster
+// cannot take down the whole operator.
+func (m *Manager) reconcile(informer *clusterInformer, name string) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = microerror.Maskf(panicError, "%v", r)
+			informer.recordReconcile(err)
+		}
+	}()
+
+	node, err := informer.getNode(name)
+	if err != nil {
+		return microerror.Mask(err)
+	}
+
+	err = informer.getCluster().Handler.Reconcile(context.Background(), name, node)
+	if err != nil {
+		return microerror.Mask(err)
+	}
+
+	informer.recordReconcile(nil)
+
+	return nil
+}
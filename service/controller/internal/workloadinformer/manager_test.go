@@ -0,0 +1,222 @@
+package workloadinformer
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/giantswarm/micrologger/microloggertest"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes/fake"
+	"k8s.io/client-go/rest"
+	k8stesting "k8s.io/client-go/testing"
+)
+
+type testEvent struct {
+	name    string
+	deleted bool
+}
+
+// testHandler records the reconciled nodes and panics for the first given
+// number of reconciliations.
+type testHandler struct {
+	mutex  sync.Mutex
+	panics int
+	events chan testEvent
+}
+
+func (h *testHandler) Reconcile(ctx context.Context, name string, node *corev1.Node) error {
+	h.mutex.Lock()
+	if h.panics > 0 {
+		h.panics--
+		h.mutex.Unlock()
+		panic("test panic")
+	}
+	h.mutex.Unlock()
+
+	h.events <- testEvent{name: name, deleted: node == nil}
+
+	return nil
+}
+
+func testNode(name string, role string) *corev1.Node {
+	return &corev1.Node{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: name,
+			Labels: map[string]string{
+				"role": role,
+			},
+		},
+	}
+}
+
+func testManager(t *testing.T) *Manager {
+	c := Config{
+		Logger: microloggertest.New(),
+
+		Name:         "test",
+		RestartAfter: time.Minute,
+		ResyncPeriod: time.Hour,
+		Workers:      2,
+	}
+
+	m, err := New(c)
+	if err != nil {
+		t.Fatalf("expected nil, got %#v", err)
+	}
+	m.Boot()
+
+	return m
+}
+
+func testCluster(handler Handler, k8sClient *fake.Clientset, host string) Cluster {
+	return Cluster{
+		Handler:    handler,
+		K8sClient:  k8sClient,
+		RESTConfig: &rest.Config{Host: host},
+		Selector:   labels.SelectorFromSet(map[string]string{"role": "worker"}),
+	}
+}
+
+func waitForEvent(t *testing.T, events chan testEvent) testEvent {
+	select {
+	case e := <-events:
+		return e
+	case <-time.After(5 * time.Second):
+		t.Fatalf("expected event, got none")
+	}
+
+	return testEvent{}
+}
+
+func waitForHealth(t *testing.T, m *Manager, key types.NamespacedName, status string) {
+	for i := 0; i < 500; i++ {
+		health, ok := m.Health(key)
+		if ok && health.Status() == status {
+			return
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	t.Fatalf("expected health %#q", status)
+}
+
+func Test_Manager_reconcile(t *testing.T) {
+	m := testManager(t)
+	defer m.Stop()
+
+	key := types.NamespacedName{Namespace: "default", Name: "al9qy"}
+	handler := &testHandler{panics: 1, events: make(chan testEvent, 10)}
+	k8sClient := fake.NewSimpleClientset(testNode("worker-a", "worker"), testNode("master-a", "master"))
+
+	err := m.Ensure(context.TODO(), key, testCluster(handler, k8sClient, "https://api.al9qy"))
+	if err != nil {
+		t.Fatalf("expected nil, got %#v", err)
+	}
+
+	// The first reconciliation panics and is retried.
+	e := waitForEvent(t, handler.events)
+	if e.name != "worker-a" || e.deleted {
+		t.Fatalf("expected existing worker-a, got %#v", e)
+	}
+
+	waitForHealth(t, m, key, HealthHealthy)
+
+	err = k8sClient.CoreV1().Nodes().Delete(context.TODO(), "worker-a", metav1.DeleteOptions{})
+	if err != nil {
+		t.Fatalf("expected nil, got %#v", err)
+	}
+
+	e = waitForEvent(t, handler.events)
+	if e.name != "worker-a" || !e.deleted {
+		t.Fatalf("expected deleted worker-a, got %#v", e)
+	}
+
+	select {
+	case e := <-handler.events:
+		t.Fatalf("expected no event, got %#v", e)
+	case <-time.After(100 * time.Millisecond):
+	}
+}
+
+func Test_Manager_Ensure(t *testing.T) {
+	m := testManager(t)
+	defer m.Stop()
+
+	key := types.NamespacedName{Namespace: "default", Name: "al9qy"}
+	handler := &testHandler{events: make(chan testEvent, 10)}
+	k8sClient := fake.NewSimpleClientset(testNode("worker-a", "worker"))
+
+	err := m.Ensure(context.TODO(), key, testCluster(handler, k8sClient, "https://api.al9qy"))
+	if err != nil {
+		t.Fatalf("expected nil, got %#v", err)
+	}
+	waitForEvent(t, handler.events)
+
+	// The same connection keeps the running informer, so the existing node is
+	// not reconciled again.
+	err = m.Ensure(context.TODO(), key, testCluster(handler, k8sClient, "https://api.al9qy"))
+	if err != nil {
+		t.Fatalf("expected nil, got %#v", err)
+	}
+	select {
+	case e := <-handler.events:
+		t.Fatalf("expected no event, got %#v", e)
+	case <-time.After(100 * time.Millisecond):
+	}
+
+	// A changed revision keeps the running informer, but reconciles the known
+	// nodes again.
+	cluster := testCluster(handler, k8sClient, "https://api.al9qy")
+	cluster.Revision = "changed"
+	err = m.Ensure(context.TODO(), key, cluster)
+	if err != nil {
+		t.Fatalf("expected nil, got %#v", err)
+	}
+	waitForEvent(t, handler.events)
+
+	// A changed connection replaces the informer, which lists the nodes again.
+	err = m.Ensure(context.TODO(), key, testCluster(handler, k8sClient, "https://api.al9qy.example.com"))
+	if err != nil {
+		t.Fatalf("expected nil, got %#v", err)
+	}
+	waitForEvent(t, handler.events)
+
+	m.Remove(context.TODO(), key)
+	if _, ok := m.Health(key); ok {
+		t.Fatalf("expected no informer after removal")
+	}
+}
+
+func Test_Manager_unhealthy(t *testing.T) {
+	m := testManager(t)
+	defer m.Stop()
+
+	key := types.NamespacedName{Namespace: "default", Name: "al9qy"}
+	handler := &testHandler{events: make(chan testEvent, 10)}
+	k8sClient := fake.NewSimpleClientset()
+	k8sClient.PrependReactor("list", "nodes", func(action k8stesting.Action) (bool, runtime.Object, error) {
+		return true, nil, errors.New("connection refused")
+	})
+
+	err := m.Ensure(context.TODO(), key, testCluster(handler, k8sClient, "https://api.al9qy"))
+	if err != nil {
+		t.Fatalf("expected nil, got %#v", err)
+	}
+
+	waitForHealth(t, m, key, HealthUnhealthy)
+
+	health, _ := m.Health(key)
+	if health.Synced {
+		t.Fatalf("expected informer not to be synced")
+	}
+	if health.UnhealthySince(health.Started.Add(time.Minute)) != time.Minute {
+		t.Fatalf("expected informer to be unhealthy since it started")
+	}
+}
@@ -2,6 +2,60 @@ package key
 
 import "github.com/giantswarm/microerror"
 
+var invalidCertificateError = &microerror.Error{
+	Kind: "invalidCertificateError",
+}
+
+// IsInvalidCertificate asserts invalidCertificateError.
+func IsInvalidCertificate(err error) bool {
+	return microerror.Cause(err) == invalidCertificateError
+}
+
+var invalidDataVolumeError = &microerror.Error{
+	Kind: "invalidDataVolumeError",
+}
+
+// IsInvalidDataVolume asserts invalidDataVolumeError.
+func IsInvalidDataVolume(err error) bool {
+	return microerror.Cause(err) == invalidDataVolumeError
+}
+
+var invalidDiskSizeError = &microerror.Error{
+	Kind: "invalidDiskSizeError",
+}
+
+// IsInvalidDiskSize asserts invalidDiskSizeError.
+func IsInvalidDiskSize(err error) bool {
+	return microerror.Cause(err) == invalidDiskSizeError
+}
+
+var invalidWorkerServiceError = &microerror.Error{
+	Kind: "invalidWorkerServiceError",
+}
+
+// IsInvalidWorkerService asserts invalidWorkerServiceError.
+func IsInvalidWorkerService(err error) bool {
+	return microerror.Cause(err) == invalidWorkerServiceError
+}
+
+var invalidNetworkPolicyError = &microerror.Error{
+	Kind: "invalidNetworkPolicyError",
+}
+
+// IsInvalidNetworkPolicy asserts invalidNetworkPolicyError.
+func IsInvalidNetworkPolicy(err error) bool {
+	return microerror.Cause(err) == invalidNetworkPolicyError
+}
+
+var invalidNodeLabelsAndTaintsError = &microerror.Error{
+	Kind: "invalidNodeLabelsAndTaintsError",
+}
+
+// IsInvalidNodeLabelsAndTaints asserts invalidNodeLabelsAndTaintsError.
+func IsInvalidNodeLabelsAndTaints(err error) bool {
+	return microerror.Cause(err) == invalidNodeLabelsAndTaintsError
+}
+
 var invalidMemoryConfigurationError = &microerror.Error{
 	Kind: "invalidMemoryConfigurationError",
 }
@@ -2,6 +2,11 @@ package key
 
 import (
 	"context"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/hex"
+	"encoding/json"
+	"encoding/pem"
 	"fmt"
 	"net"
 	"path/filepath"
@@ -12,15 +17,19 @@ import (
 
 	"github.com/giantswarm/apiextensions/v3/pkg/apis/provider/v1alpha1"
 	releasev1alpha1 "github.com/giantswarm/apiextensions/v3/pkg/apis/release/v1alpha1"
+	"github.com/giantswarm/apiextensions/v3/pkg/clientset/versioned"
 	"github.com/giantswarm/k8sclient/v5/pkg/k8sclient"
 	k8scloudconfig "github.com/giantswarm/k8scloudconfig/v10/pkg/template"
 	"github.com/giantswarm/microerror"
 	"github.com/giantswarm/micrologger"
 	workloadcluster "github.com/giantswarm/tenantcluster/v4/pkg/tenantcluster"
 	corev1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/api/resource"
 	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/apimachinery/pkg/util/validation"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	"github.com/giantswarm/kvm-operator/v4/pkg/label"
@@ -30,11 +39,20 @@ const (
 	MasterID = "master"
 	WorkerID = "worker"
 	EtcdPort = 443
-	// livenessPortBase is a baseline for computing the port for liveness probes.
+	// livenessPortBase is a baseline for computing the port for liveness
+	// probes of clusters created before ports were allocated explicitly. It is
+	// also the lower bound of the liveness port range.
 	livenessPortBase = 23000
+	// livenessPortMax is the upper bound of the liveness port range.
+	livenessPortMax = 46999
 	// shutdownDeferrerPortBase is a baseline for computing the port for
-	// shutdown-deferrer.
+	// shutdown-deferrer of clusters created before ports were allocated
+	// explicitly. It is also the lower bound of the shutdown-deferrer port
+	// range.
 	shutdownDeferrerPortBase = 47000
+	// shutdownDeferrerPortMax is the upper bound of the shutdown-deferrer port
+	// range.
+	shutdownDeferrerPortMax = 65535
 	// HealthEndpoint is http path for liveness probe.
 	HealthEndpoint = "/healthz"
 	// ProbeHost host for liveness probe.
@@ -80,36 +98,158 @@ const (
 	// workers can be configured at runtime by the user.
 	DefaultDockerDiskSize = "50G"
 	// DefaultKubeletDiskSize defines the space used to partition the kubelet FS
-	// within k8s-kvm. Note we use this only for masters, since the value for the
-	// workers can be configured at runtime by the user.
+	// within k8s-kvm unless configured differently via AnnotationNodeDiskSizes.
 	DefaultKubeletDiskSize = "5G"
 	// DefaultOSDiskSize defines the space used to partition the root FS within
-	// k8s-kvm.
+	// k8s-kvm unless configured differently via AnnotationNodeDiskSizes.
 	DefaultOSDiskSize = "5G"
+	// MinOSDiskSizeGB is the minimum size of the root FS in GB a node can be
+	// configured with, since the OS image does not fit on smaller disks.
+	MinOSDiskSizeGB = 5
+
+	// PersistentDiskDocker is the name of the persistent disk backing the
+	// docker FS of workers.
+	PersistentDiskDocker = "dockerfs"
+	// PersistentDiskKubelet is the name of the persistent disk backing the
+	// kubelet FS of workers.
+	PersistentDiskKubelet = "kubeletfs"
 
 	DefaultImagePullProgressDeadline = "1m"
 )
 
 const (
+	// AnnotationAllocatedHostPorts is set on KVMConfigs by the portstatus
+	// resource and holds the allocated liveness and shutdown-deferrer ports as
+	// JSON object keyed by HostPortLiveness and HostPortShutdownDeferrer.
+	AnnotationAllocatedHostPorts = "kvm-operator.giantswarm.io/allocated-host-ports"
+	// AnnotationAllocatedNodePorts is set on KVMConfigs by the nodeportstatus
+	// resource and holds the node ports allocated to port mappings without node port as
+	// JSON object keyed by port mapping name.
+	AnnotationAllocatedNodePorts = "kvm-operator.giantswarm.io/allocated-node-ports"
+	// AnnotationCertificateExpiry is set on KVMConfigs by the certstatus
+	// resource and holds the expiry of the cluster certificates as JSON object
+	// keyed by certificate name.
+	AnnotationCertificateExpiry = "kvm-operator.giantswarm.io/certificate-expiry"
+
 	AnnotationAPIEndpoint            = "kvm-operator.giantswarm.io/api-endpoint"
+	AnnotationAuditConfigMap         = "kvm-operator.giantswarm.io/audit-config-map"
 	AnnotationComponentVersionPrefix = "kvm-operator.giantswarm.io/component-version"
+	AnnotationEgressConfigSecret     = "kvm-operator.giantswarm.io/egress-config-secret"
+	AnnotationEncryptionKeyRotation  = "kvm-operator.giantswarm.io/encryption-key-rotation"
+	AnnotationEncryptionKeys         = "kvm-operator.giantswarm.io/encryption-keys"
 	AnnotationEtcdDomain             = "giantswarm.io/etcd-domain"
-	AnnotationService                = "endpoint.kvm.giantswarm.io/service"
-	AnnotationPodDrained             = "endpoint.kvm.giantswarm.io/drained"
-	AnnotationPrometheusCluster      = "giantswarm.io/prometheus-cluster"
-	AnnotationVersionBundle          = "kvm-operator.giantswarm.io/version-bundle"
-
-	LabelApp           = "app"
-	LabelCluster       = "giantswarm.io/cluster"
-	LabelCustomer      = "customer"
-	LabelManagedBy     = "giantswarm.io/managed-by"
-	LabelMountTag      = "mount-tag"
-	LabelOrganization  = "giantswarm.io/organization"
+	AnnotationEtcdStorageClass       = "kvm-operator.giantswarm.io/etcd-storage-class"
+	AnnotationEtcdVolumeSize         = "kvm-operator.giantswarm.io/etcd-volume-size"
+	AnnotationIgnitionChecksum       = "kvm-operator.giantswarm.io/ignition-checksum"
+	// AnnotationIngressClass overrides the installation wide ingress class of
+	// the API and etcd ingresses.
+	AnnotationIngressClass = "kvm-operator.giantswarm.io/ingress-class"
+	// AnnotationIngressPassthroughAnnotations overrides the installation wide
+	// template of the annotations enabling TLS passthrough on the API and etcd
+	// ingresses.
+	AnnotationIngressPassthroughAnnotations = "kvm-operator.giantswarm.io/ingress-passthrough-annotations"
+	// AnnotationDiskSizes is set on deployments and lists the disk sizes their
+	// VM has been configured with.
+	AnnotationDiskSizes = "kvm-operator.giantswarm.io/disk-sizes"
+	// AnnotationDataVolumes is set on worker deployments having data volumes
+	// and holds the data volumes their VM has been configured with as JSON
+	// list.
+	AnnotationDataVolumes = "kvm-operator.giantswarm.io/data-volumes"
+	// AnnotationNetworkPolicyAllowList allows additional traffic to the VM pods
+	// of a cluster as JSON list of NetworkPolicy ingress rules, e.g.
+	// [{"from":[{"ipBlock":{"cidr":"10.0.0.0/8"}}],"ports":[{"port":22}]}].
+	AnnotationNetworkPolicyAllowList = "kvm-operator.giantswarm.io/network-policy-allow-list"
+	// AnnotationManagedNodeLabels and AnnotationManagedNodeTaints are set on
+	// workload cluster nodes and list the labels and taints applied from
+	// AnnotationNodeLabelsAndTaints, so that they can be removed again.
+	AnnotationManagedNodeLabels = "kvm-operator.giantswarm.io/managed-labels"
+	AnnotationManagedNodeTaints = "kvm-operator.giantswarm.io/managed-taints"
+	// AnnotationNodeDiskSizes configures the kubelet and root disk sizes of
+	// nodes as JSON object keyed by node ID, where "*" applies to all nodes,
+	// e.g. {"*":{"kubeletVolumeSizeGB":10},"a1b2c":{"rootVolumeSizeGB":8}}.
+	AnnotationNodeDiskSizes = "kvm-operator.giantswarm.io/node-disk-sizes"
+	// AnnotationNodeLabelsAndTaints configures the labels and taints of
+	// workload cluster nodes as JSON object keyed by node ID or role, where "*"
+	// applies to all nodes, e.g.
+	// {"worker":{"labels":{"team":"a"},"taints":[{"key":"dedicated","value":"a","effect":"NoSchedule"}]}}.
+	AnnotationNodeLabelsAndTaints = "kvm-operator.giantswarm.io/node-labels-and-taints"
+	AnnotationOIDCConfigMap       = "kvm-operator.giantswarm.io/oidc-config-map"
+	// AnnotationPersistentDisks is set on worker deployments whose docker and
+	// kubelet disks are backed by persistent volume claims.
+	AnnotationPersistentDisks = "kvm-operator.giantswarm.io/persistent-disks"
+	// AnnotationPersistentWorkerDisks selects the workers whose docker and
+	// kubelet disks survive VM restarts. Its value is either "*" for all
+	// workers or a comma separated list of worker node IDs.
+	AnnotationPersistentWorkerDisks             = "kvm-operator.giantswarm.io/persistent-worker-disks"
+	AnnotationPersistentWorkerDisksStorageClass = "kvm-operator.giantswarm.io/persistent-worker-disks-storage-class"
+	AnnotationService                           = "endpoint.kvm.giantswarm.io/service"
+	AnnotationPodDrained                        = "endpoint.kvm.giantswarm.io/drained"
+	AnnotationPrometheusCluster                 = "giantswarm.io/prometheus-cluster"
+	AnnotationVersionBundle                     = "kvm-operator.giantswarm.io/version-bundle"
+	// AnnotationWorkerDataVolumes configures the data volumes of workers as
+	// JSON object of lists keyed by node ID, where "*" applies to all workers,
+	// e.g. {"*":[{"mountTag":"data","hostPath":"/data","size":"100Gi"}]}.
+	AnnotationWorkerDataVolumes = "kvm-operator.giantswarm.io/worker-data-volumes"
+	// AnnotationWorkerService configures the worker ingress service as JSON
+	// object, e.g. {"type":"LoadBalancer","loadBalancerSourceRanges":["10.0.0.0/8"]}.
+	AnnotationWorkerService = "kvm-operator.giantswarm.io/worker-service"
+
+	// HostPortLiveness and HostPortShutdownDeferrer are the kinds of ports
+	// allocated per cluster by the portstatus resource.
+	HostPortLiveness         = "liveness"
+	HostPortShutdownDeferrer = "shutdownDeferrer"
+	// PortStatusResourceName is the name of the operatorkit resource persisting
+	// the allocated ports in the AnnotationAllocatedHostPorts annotation of the
+	// KVMConfig.
+	PortStatusResourceName = "portstatus"
+	// NodePortStatusResourceName is the name of the operatorkit resource
+	// persisting the node ports allocated to port mappings without node port
+	// in the AnnotationAllocatedNodePorts annotation of the KVMConfig.
+	NodePortStatusResourceName = "nodeportstatus"
+
+	// NodeControllerResourceName is the name of the operatorkit resource
+	// persisting the health of the node informer of a workload cluster in the
+	// status of the KVMConfig. Every health has its own condition, whose type
+	// is the health prefixed with NodeInformerCondition.
+	NodeControllerResourceName = "nodecontroller"
+	NodeInformerCondition      = "NodeInformer"
+
+	LabelApp          = "app"
+	LabelCluster      = "giantswarm.io/cluster"
+	LabelCustomer     = "customer"
+	LabelManagedBy    = "giantswarm.io/managed-by"
+	LabelMountTag     = "mount-tag"
+	LabelOrganization = "giantswarm.io/organization"
+	// LabelPersistentDisk is set on the persistent volume claims backing
+	// worker disks. Its value is the disk name, e.g. "dockerfs".
+	LabelPersistentDisk = "kvm-operator.giantswarm.io/persistent-disk"
+	// LabelDataVolume is set on the persistent volume claims backing worker
+	// data volumes. Its value is the mount tag of the data volume.
+	LabelDataVolume    = "kvm-operator.giantswarm.io/data-volume"
 	LabelVersionBundle = "giantswarm.io/version-bundle"
 
 	LegacyLabelCluster = "cluster"
 )
 
+const (
+	// EncryptionKeysSecretKeyPrimary is the key of the encryption keys secret
+	// holding the key API servers use to encrypt secrets.
+	EncryptionKeysSecretKeyPrimary = "primary"
+	// EncryptionKeysSecretKeySecondary is the key of the encryption keys secret
+	// holding the key API servers only use to decrypt secrets. During a key
+	// rotation it holds the new key until all masters are able to decrypt with
+	// it, and the old key until all secrets are encrypted with the new one.
+	EncryptionKeysSecretKeySecondary = "secondary"
+	// EncryptionKeysSecretKeyRotation is the key of the encryption keys secret
+	// holding the value of the AnnotationEncryptionKeyRotation annotation
+	// which caused the current keys to be generated.
+	EncryptionKeysSecretKeyRotation = "rotation"
+	// EncryptionKeysSecretKeyPhase is the key of the encryption keys secret
+	// holding the phase of the current key rotation. It is written together
+	// with the keys, so that the phase always matches the keys in use.
+	EncryptionKeysSecretKeyPhase = "phase"
+)
+
 const (
 	KubernetesNetworkSetupDocker = "0.2.0"
 	kubernetesAPIHealthzVersion  = "0.1.1"
@@ -134,6 +274,35 @@ const (
 	WorkloadClusterNodeReady corev1.PodConditionType = "kvm-operator.giantswarm.io/workload-cluster-node-ready"
 )
 
+const (
+	// Pod conditions mirroring the node conditions of the workload cluster node
+	// running in the VM of a pod.
+	WorkloadClusterNodeDiskPressure       corev1.PodConditionType = "kvm-operator.giantswarm.io/workload-cluster-node-disk-pressure"
+	WorkloadClusterNodeMemoryPressure     corev1.PodConditionType = "kvm-operator.giantswarm.io/workload-cluster-node-memory-pressure"
+	WorkloadClusterNodeNetworkUnavailable corev1.PodConditionType = "kvm-operator.giantswarm.io/workload-cluster-node-network-unavailable"
+	WorkloadClusterNodePIDPressure        corev1.PodConditionType = "kvm-operator.giantswarm.io/workload-cluster-node-pid-pressure"
+	// WorkloadClusterNodeRegistered is false when no workload cluster node
+	// registered for the VM of a pod within the orphan pod grace period.
+	WorkloadClusterNodeRegistered corev1.PodConditionType = "kvm-operator.giantswarm.io/workload-cluster-node-registered"
+	// WorkloadClusterNodeUnschedulable is true when the workload cluster node
+	// running in the VM of a pod is cordoned.
+	WorkloadClusterNodeUnschedulable corev1.PodConditionType = "kvm-operator.giantswarm.io/workload-cluster-node-unschedulable"
+
+	// AnnotationWorkloadClusterKubeletVersion is set on VM pods and mirrors the
+	// kubelet version of the workload cluster node running in the VM.
+	AnnotationWorkloadClusterKubeletVersion = "kvm-operator.giantswarm.io/workload-cluster-kubelet-version"
+)
+
+// WorkloadClusterNodeConditions maps the node conditions of workload cluster
+// nodes to the pod conditions they are mirrored to on the pods of their VMs.
+var WorkloadClusterNodeConditions = map[corev1.NodeConditionType]corev1.PodConditionType{
+	corev1.NodeReady:              WorkloadClusterNodeReady,
+	corev1.NodeMemoryPressure:     WorkloadClusterNodeMemoryPressure,
+	corev1.NodeDiskPressure:       WorkloadClusterNodeDiskPressure,
+	corev1.NodePIDPressure:        WorkloadClusterNodePIDPressure,
+	corev1.NodeNetworkUnavailable: WorkloadClusterNodeNetworkUnavailable,
+}
+
 func AllNodes(cr v1alpha1.KVMConfig) []v1alpha1.ClusterNode {
 	var results []v1alpha1.ClusterNode
 
@@ -156,6 +325,14 @@ func AllocatedNodeIndexes(cr v1alpha1.KVMConfig) []int {
 	return results
 }
 
+// AuditConfigMapName returns the name of the ConfigMap holding the cluster
+// specific API server audit configuration. The ConfigMap is expected in the
+// namespace of the given KVMConfig. An empty string is returned in case the
+// cluster uses the installation wide audit configuration.
+func AuditConfigMapName(cr v1alpha1.KVMConfig) string {
+	return cr.GetAnnotations()[AnnotationAuditConfigMap]
+}
+
 func BaseDomain(customObject v1alpha1.KVMConfig) string {
 	return strings.TrimPrefix(customObject.Spec.Cluster.Kubernetes.API.Domain, "api.")
 }
@@ -268,6 +445,54 @@ func DockerVolumeSizeFromNode(node v1alpha1.KVMConfigSpecKVMNode) string {
 	return DefaultDockerDiskSize
 }
 
+// EncryptionKeyRotation returns the value of the annotation requesting an
+// encryption key rotation. A new rotation is started whenever the value
+// changes.
+func EncryptionKeyRotation(cr v1alpha1.KVMConfig) string {
+	return cr.GetAnnotations()[AnnotationEncryptionKeyRotation]
+}
+
+// EncryptionKeys returns the API server encryption keys held by the given
+// encryption keys secret, the key used for encryption first.
+func EncryptionKeys(secret *corev1.Secret) []string {
+	var keys []string
+
+	for _, k := range []string{EncryptionKeysSecretKeyPrimary, EncryptionKeysSecretKeySecondary} {
+		if len(secret.Data[k]) > 0 {
+			keys = append(keys, string(secret.Data[k]))
+		}
+	}
+
+	return keys
+}
+
+// EncryptionKeysFingerprint returns a hex encoded SHA256 sum identifying the
+// given ordered list of encryption keys without exposing them.
+func EncryptionKeysFingerprint(keys []string) string {
+	if len(keys) == 0 {
+		return ""
+	}
+
+	sum := sha256.Sum256([]byte(strings.Join(keys, ",")))
+
+	return hex.EncodeToString(sum[:])
+}
+
+// EgressConfigSecretName returns the name of the Secret holding the cluster
+// specific proxy and registry configuration. The Secret is expected in the
+// namespace of the given KVMConfig. An empty string is returned in case the
+// cluster uses the installation wide proxy and registry configuration.
+func EgressConfigSecretName(cr v1alpha1.KVMConfig) string {
+	return cr.GetAnnotations()[AnnotationEgressConfigSecret]
+}
+
+// EncryptionKeysSecretName returns the name of the secret holding the API
+// server encryption keys of a cluster once they have been rotated. The secret
+// is located in the cluster namespace.
+func EncryptionKeysSecretName(customObject v1alpha1.KVMConfig) string {
+	return fmt.Sprintf("%s-encryption-keys", ClusterID(customObject))
+}
+
 func EtcdPVCName(clusterID string, vmNumber string) string {
 	return fmt.Sprintf("%s-%s-%s", "pvc-master-etcd", clusterID, vmNumber)
 }
@@ -276,6 +501,21 @@ func LocalWorkerPVCName(clusterID string, vmNumber, mountTag string) string {
 	return fmt.Sprintf("%s-%s-%s-%s", "local-pvc-worker", clusterID, vmNumber, mountTag)
 }
 
+// PersistentDiskPVCName returns the name of the persistent volume claim
+// backing the given disk of a worker. Other than for host volumes the node ID
+// is used instead of the VM number, since the VM number of a node changes when
+// other nodes are removed.
+func PersistentDiskPVCName(clusterID string, nodeID string, disk string) string {
+	return fmt.Sprintf("%s-%s-%s-%s", "pvc-worker", disk, clusterID, nodeID)
+}
+
+// WorkerDataVolumePVCName returns the name of the persistent volume claim
+// backing the given data volume of a worker. Like for persistent disks the
+// node ID is used instead of the VM number.
+func WorkerDataVolumePVCName(clusterID string, nodeID string, mountTag string) string {
+	return fmt.Sprintf("%s-%s-%s-%s", "pvc-worker-data", clusterID, nodeID, mountTag)
+}
+
 // FindNodeCondition returns the condition of the given type from the node. The second return value indicates if the condition was found.
 func FindNodeCondition(node corev1.Node, conditionType corev1.NodeConditionType) (corev1.NodeCondition, bool) {
 	for _, condition := range node.Status.Conditions {
@@ -296,10 +536,92 @@ func FindPodCondition(pod corev1.Pod, conditionType corev1.PodConditionType) (co
 	return corev1.PodCondition{}, false
 }
 
+// HostPortRange returns the inclusive range ports of the given kind are
+// allocated from.
+func HostPortRange(name string) (int, int) {
+	switch name {
+	case HostPortLiveness:
+		return livenessPortBase, livenessPortMax
+	case HostPortShutdownDeferrer:
+		return shutdownDeferrerPortBase, shutdownDeferrerPortMax
+	}
+
+	return 0, 0
+}
+
+// AllocatedHostPorts returns the liveness and shutdown-deferrer ports persisted
+// in the AnnotationAllocatedHostPorts annotation of the given KVMConfig, keyed
+// by HostPortLiveness and HostPortShutdownDeferrer. Ports outside of their
+// range are omitted.
+func AllocatedHostPorts(customObject v1alpha1.KVMConfig) map[string]int {
+	var persisted map[string]int
+	StatusAnnotation(customObject, AnnotationAllocatedHostPorts, &persisted)
+
+	ports := map[string]int{}
+	for name, port := range persisted {
+		min, max := HostPortRange(name)
+		if port < min || port > max {
+			continue
+		}
+
+		ports[name] = port
+	}
+
+	return ports
+}
+
+// AllocatedNodePorts returns the node ports persisted in the
+// AnnotationAllocatedNodePorts annotation of the given KVMConfig, keyed by port
+// mapping name.
+func AllocatedNodePorts(customObject v1alpha1.KVMConfig) map[string]int32 {
+	allocated := map[string]int32{}
+	StatusAnnotation(customObject, AnnotationAllocatedNodePorts, &allocated)
+
+	return allocated
+}
+
+// LegacyHostPort returns the port of the given kind as derived from the VNI of
+// the given cluster before ports were allocated explicitly.
+func LegacyHostPort(customObject v1alpha1.KVMConfig, name string) int {
+	min, _ := HostPortRange(name)
+	return min + customObject.Spec.KVM.Network.Flannel.VNI
+}
+
 func HealthListenAddress(customObject v1alpha1.KVMConfig) string {
 	return "http://" + ProbeHost + ":" + strconv.Itoa(int(LivenessPort(customObject)))
 }
 
+// IgnitionAnnotations returns the annotations identifying the cluster specific
+// settings rendered into the ignition of a node. They are set on the config
+// maps and copied onto the deployments mounting them.
+func IgnitionAnnotations() []string {
+	return []string{
+		AnnotationEncryptionKeys,
+		AnnotationIgnitionChecksum,
+	}
+}
+
+// IngressClass returns the ingress class of the API and etcd ingresses of the
+// given KVMConfig, falling back to the given installation wide class.
+func IngressClass(customObject v1alpha1.KVMConfig, installation string) string {
+	if v := customObject.GetAnnotations()[AnnotationIngressClass]; v != "" {
+		return v
+	}
+
+	return installation
+}
+
+// IngressPassthroughAnnotations returns the template of the TLS passthrough
+// annotations of the API and etcd ingresses of the given KVMConfig, falling
+// back to the given installation wide template.
+func IngressPassthroughAnnotations(customObject v1alpha1.KVMConfig, installation string) string {
+	if v := customObject.GetAnnotations()[AnnotationIngressPassthroughAnnotations]; v != "" {
+		return v
+	}
+
+	return installation
+}
+
 func IscsiInitiatorName(customObject v1alpha1.KVMConfig, nodeIndex int, nodeRole string) string {
 	return fmt.Sprintf("iqn.2016-04.com.coreos.iscsi:giantswarm-%s-%s-%d", ClusterID(customObject), nodeRole, nodeIndex)
 }
@@ -334,19 +656,357 @@ func IsPodDrained(pod corev1.Pod) (bool, error) {
 	return b, nil
 }
 
-func KubeletVolumeSizeFromNode(node v1alpha1.KVMConfigSpecKVMNode) string {
-	// TODO: https://github.com/giantswarm/giantswarm/issues/4105#issuecomment-421772917
-	// TODO: for now we use same value as for DockerVolumeSizeFromNode, when we have kubelet size in spec we should use that.
+// KubeletVolumeSize returns the size of the kubelet FS of the given node as
+// configured via AnnotationNodeDiskSizes, defaulting to
+// DefaultKubeletDiskSize.
+func KubeletVolumeSize(customObject v1alpha1.KVMConfig, nodeID string) (string, error) {
+	size, err := ConfiguredKubeletVolumeSize(customObject, nodeID)
+	if err != nil {
+		return "", microerror.Mask(err)
+	}
 
-	if node.DockerVolumeSizeGB != 0 {
-		return fmt.Sprintf("%dG", node.DockerVolumeSizeGB)
+	if size != "" {
+		return size, nil
 	}
 
-	if node.Disk.Value != 0 {
-		return fmt.Sprintf("%sG", strconv.FormatFloat(node.Disk.Value, 'f', 0, 64))
+	return DefaultKubeletDiskSize, nil
+}
+
+// ConfiguredKubeletVolumeSize returns the size of the kubelet FS of the given
+// node as configured via AnnotationNodeDiskSizes or an empty string in case it
+// is not configured.
+func ConfiguredKubeletVolumeSize(customObject v1alpha1.KVMConfig, nodeID string) (string, error) {
+	sizes, err := nodeDiskSizes(customObject, nodeID)
+	if err != nil {
+		return "", microerror.Mask(err)
+	}
+
+	if sizes.KubeletVolumeSizeGB != 0 {
+		return fmt.Sprintf("%dG", sizes.KubeletVolumeSizeGB), nil
 	}
 
-	return DefaultKubeletDiskSize
+	return "", nil
+}
+
+// NodeDiskSizes is the disk configuration of a node given via
+// AnnotationNodeDiskSizes. Zero values mean the defaults are used.
+type NodeDiskSizes struct {
+	KubeletVolumeSizeGB int `json:"kubeletVolumeSizeGB,omitempty"`
+	RootVolumeSizeGB    int `json:"rootVolumeSizeGB,omitempty"`
+}
+
+// nodeDiskSizes returns the disk sizes configured for the given node. Sizes
+// configured for the node itself take precedence over sizes configured for
+// all nodes.
+func nodeDiskSizes(customObject v1alpha1.KVMConfig, nodeID string) (NodeDiskSizes, error) {
+	v := customObject.GetAnnotations()[AnnotationNodeDiskSizes]
+	if v == "" {
+		return NodeDiskSizes{}, nil
+	}
+
+	var all map[string]NodeDiskSizes
+	err := json.Unmarshal([]byte(v), &all)
+	if err != nil {
+		return NodeDiskSizes{}, microerror.Maskf(invalidDiskSizeError, "annotation %#q must be a JSON object: %s", AnnotationNodeDiskSizes, err)
+	}
+
+	for id, sizes := range all {
+		if sizes.KubeletVolumeSizeGB < 0 {
+			return NodeDiskSizes{}, microerror.Maskf(invalidDiskSizeError, "kubelet volume size of %#q must not be negative", id)
+		}
+		if sizes.RootVolumeSizeGB != 0 && sizes.RootVolumeSizeGB < MinOSDiskSizeGB {
+			return NodeDiskSizes{}, microerror.Maskf(invalidDiskSizeError, "root volume size of %#q must be at least %dG", id, MinOSDiskSizeGB)
+		}
+	}
+
+	sizes := all["*"]
+	if node, ok := all[nodeID]; ok {
+		if node.KubeletVolumeSizeGB != 0 {
+			sizes.KubeletVolumeSizeGB = node.KubeletVolumeSizeGB
+		}
+		if node.RootVolumeSizeGB != 0 {
+			sizes.RootVolumeSizeGB = node.RootVolumeSizeGB
+		}
+	}
+
+	return sizes, nil
+}
+
+// RootVolumeSize returns the size of the root FS of the given node as
+// configured via AnnotationNodeDiskSizes, defaulting to DefaultOSDiskSize.
+func RootVolumeSize(customObject v1alpha1.KVMConfig, nodeID string) (string, error) {
+	sizes, err := nodeDiskSizes(customObject, nodeID)
+	if err != nil {
+		return "", microerror.Mask(err)
+	}
+
+	if sizes.RootVolumeSizeGB != 0 {
+		return fmt.Sprintf("%dG", sizes.RootVolumeSizeGB), nil
+	}
+
+	return DefaultOSDiskSize, nil
+}
+
+// HasPersistentDisks returns true in case the docker and kubelet disks of the
+// given worker are backed by persistent volume claims.
+func HasPersistentDisks(customObject v1alpha1.KVMConfig, nodeID string) bool {
+	v := customObject.GetAnnotations()[AnnotationPersistentWorkerDisks]
+
+	for _, id := range strings.Split(v, ",") {
+		id = strings.TrimSpace(id)
+		if id == "*" || (id != "" && id == nodeID) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// HasAnyPersistentDisks returns true in case any worker of the given cluster
+// has persistent disks.
+func HasAnyPersistentDisks(customObject v1alpha1.KVMConfig) bool {
+	for _, worker := range customObject.Spec.Cluster.Workers {
+		if HasPersistentDisks(customObject, worker.ID) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// PersistentDisksStorageClass returns the storage class of the persistent
+// volume claims backing worker disks. An empty string means the default
+// storage class.
+func PersistentDisksStorageClass(customObject v1alpha1.KVMConfig) string {
+	return customObject.GetAnnotations()[AnnotationPersistentWorkerDisksStorageClass]
+}
+
+// WorkerDataVolume is a dynamically provisioned data volume attached to a
+// worker. Other than host volumes it does not require a
+// pre-created persistent volume, but is claimed with the given size from the
+// given storage class. An empty storage class means the default storage class.
+type WorkerDataVolume struct {
+	HostPath     string `json:"hostPath"`
+	MountTag     string `json:"mountTag"`
+	Size         string `json:"size"`
+	StorageClass string `json:"storageClass,omitempty"`
+}
+
+// WorkerServiceConfig is the configuration of the worker ingress service given
+// via AnnotationWorkerService.
+type WorkerServiceConfig struct {
+	Annotations              map[string]string  `json:"annotations,omitempty"`
+	LoadBalancerSourceRanges []string           `json:"loadBalancerSourceRanges,omitempty"`
+	Type                     corev1.ServiceType `json:"type,omitempty"`
+}
+
+// WorkerService returns the validated worker ingress service configuration of
+// the given KVMConfig. The service type defaults to NodePort.
+func WorkerService(customObject v1alpha1.KVMConfig) (WorkerServiceConfig, error) {
+	config := WorkerServiceConfig{
+		Type: corev1.ServiceTypeNodePort,
+	}
+
+	v := customObject.GetAnnotations()[AnnotationWorkerService]
+	if v == "" {
+		return config, nil
+	}
+
+	err := json.Unmarshal([]byte(v), &config)
+	if err != nil {
+		return WorkerServiceConfig{}, microerror.Maskf(invalidWorkerServiceError, "annotation %#q must be a JSON object: %s", AnnotationWorkerService, err)
+	}
+
+	switch config.Type {
+	case "":
+		config.Type = corev1.ServiceTypeNodePort
+	case corev1.ServiceTypeClusterIP, corev1.ServiceTypeLoadBalancer, corev1.ServiceTypeNodePort:
+	default:
+		return WorkerServiceConfig{}, microerror.Maskf(invalidWorkerServiceError, "service type %#q is not supported", config.Type)
+	}
+
+	if config.Type != corev1.ServiceTypeLoadBalancer && (len(config.Annotations) > 0 || len(config.LoadBalancerSourceRanges) > 0) {
+		return WorkerServiceConfig{}, microerror.Maskf(invalidWorkerServiceError, "annotations and load balancer source ranges require service type %#q", corev1.ServiceTypeLoadBalancer)
+	}
+
+	for _, r := range config.LoadBalancerSourceRanges {
+		_, _, err := net.ParseCIDR(r)
+		if err != nil {
+			return WorkerServiceConfig{}, microerror.Maskf(invalidWorkerServiceError, "load balancer source range %#q must be a CIDR", r)
+		}
+	}
+
+	return config, nil
+}
+
+// WorkerServiceUsesNodePorts returns true in case the worker service of the
+// given KVMConfig allocates node ports on the management cluster. Invalid
+// configurations are reported when computing the service of the affected
+// cluster, its port mappings are still considered to use node ports.
+func WorkerServiceUsesNodePorts(customObject v1alpha1.KVMConfig) bool {
+	config, err := WorkerService(customObject)
+	if err != nil {
+		return true
+	}
+
+	return config.Type != corev1.ServiceTypeClusterIP
+}
+
+// reservedWorkerVolumeNames are the names of the volumes of worker VM pods,
+// which data volumes must not use as mount tag.
+var reservedWorkerVolumeNames = []string{
+	"dev-kvm",
+	"dev-net-tun",
+	"ignition",
+	"images",
+	"rootfs",
+	PersistentDiskDocker,
+	PersistentDiskKubelet,
+}
+
+// WorkerDataVolumes returns the data volumes of the given worker as
+// configured via AnnotationWorkerDataVolumes. Data volumes configured for the
+// node itself replace data volumes with the same mount tag configured for all
+// workers.
+func WorkerDataVolumes(customObject v1alpha1.KVMConfig, nodeID string) ([]WorkerDataVolume, error) {
+	v := customObject.GetAnnotations()[AnnotationWorkerDataVolumes]
+	if v == "" {
+		return nil, nil
+	}
+
+	var all map[string][]WorkerDataVolume
+	err := json.Unmarshal([]byte(v), &all)
+	if err != nil {
+		return nil, microerror.Maskf(invalidDataVolumeError, "annotation %#q must be a JSON object of lists: %s", AnnotationWorkerDataVolumes, err)
+	}
+
+	for id, dataVolumes := range all {
+		err = validateWorkerDataVolumes(id, dataVolumes)
+		if err != nil {
+			return nil, microerror.Mask(err)
+		}
+	}
+
+	dataVolumes := append([]WorkerDataVolume{}, all["*"]...)
+	for _, dataVolume := range all[nodeID] {
+		replaced := false
+		for i := range dataVolumes {
+			if dataVolumes[i].MountTag == dataVolume.MountTag {
+				dataVolumes[i] = dataVolume
+				replaced = true
+			}
+		}
+		if !replaced {
+			dataVolumes = append(dataVolumes, dataVolume)
+		}
+	}
+
+	if len(dataVolumes) == 0 {
+		return nil, nil
+	}
+
+	// Data volumes and host volumes are both VM pod volumes named by their
+	// mount tag.
+	for i, worker := range customObject.Spec.Cluster.Workers {
+		if worker.ID != nodeID || i >= len(customObject.Spec.KVM.Workers) {
+			continue
+		}
+		for _, hostVolume := range customObject.Spec.KVM.Workers[i].HostVolumes {
+			for _, dataVolume := range dataVolumes {
+				if dataVolume.MountTag == hostVolume.MountTag {
+					return nil, microerror.Maskf(invalidDataVolumeError, "mount tag %#q of %#q is already used by a host volume", dataVolume.MountTag, nodeID)
+				}
+			}
+		}
+	}
+
+	return dataVolumes, nil
+}
+
+func validateWorkerDataVolumes(nodeID string, dataVolumes []WorkerDataVolume) error {
+	mountTags := map[string]bool{}
+	for _, dataVolume := range dataVolumes {
+		// Mount tags are used as 9p tags, which are limited to 31 characters,
+		// and as names of the VM pod volumes.
+		if dataVolume.MountTag == "" || len(dataVolume.MountTag) > 31 {
+			return microerror.Maskf(invalidDataVolumeError, "mount tag %#q of %#q must have between 1 and 31 characters", dataVolume.MountTag, nodeID)
+		}
+		if errs := validation.IsDNS1123Label(dataVolume.MountTag); len(errs) != 0 {
+			return microerror.Maskf(invalidDataVolumeError, "mount tag %#q of %#q must be a DNS-1123 label: %s", dataVolume.MountTag, nodeID, strings.Join(errs, ", "))
+		}
+		for _, name := range reservedWorkerVolumeNames {
+			if dataVolume.MountTag == name {
+				return microerror.Maskf(invalidDataVolumeError, "mount tag %#q of %#q is reserved for a volume of the VM pod", dataVolume.MountTag, nodeID)
+			}
+		}
+		if mountTags[dataVolume.MountTag] {
+			return microerror.Maskf(invalidDataVolumeError, "mount tag %#q of %#q must be unique", dataVolume.MountTag, nodeID)
+		}
+		mountTags[dataVolume.MountTag] = true
+
+		if !filepath.IsAbs(dataVolume.HostPath) {
+			return microerror.Maskf(invalidDataVolumeError, "host path %#q of mount tag %#q must be absolute", dataVolume.HostPath, dataVolume.MountTag)
+		}
+
+		size, err := resource.ParseQuantity(dataVolume.Size)
+		if err != nil || size.Sign() <= 0 {
+			return microerror.Maskf(invalidDataVolumeError, "size %#q of mount tag %#q must be a positive quantity", dataVolume.Size, dataVolume.MountTag)
+		}
+	}
+
+	return nil
+}
+
+// HasAnyWorkerDataVolumes returns true in case any worker of the given
+// cluster has data volumes.
+func HasAnyWorkerDataVolumes(customObject v1alpha1.KVMConfig) (bool, error) {
+	for _, worker := range customObject.Spec.Cluster.Workers {
+		dataVolumes, err := WorkerDataVolumes(customObject, worker.ID)
+		if err != nil {
+			return false, microerror.Mask(err)
+		}
+		if len(dataVolumes) > 0 {
+			return true, nil
+		}
+	}
+
+	return false, nil
+}
+
+// WorkerDataVolumesToHostVolumes returns the host volume representation of the
+// given data volumes, which is how they are passed to the VM.
+func WorkerDataVolumesToHostVolumes(dataVolumes []WorkerDataVolume) []v1alpha1.KVMConfigSpecKVMNodeHostVolumes {
+	var hostVolumes []v1alpha1.KVMConfigSpecKVMNodeHostVolumes
+
+	for _, dataVolume := range dataVolumes {
+		hostVolumes = append(hostVolumes, v1alpha1.KVMConfigSpecKVMNodeHostVolumes{
+			HostPath: dataVolume.HostPath,
+			MountTag: dataVolume.MountTag,
+		})
+	}
+
+	return hostVolumes
+}
+
+// WorkerDataVolumesToVolumes returns the volumes of the persistent volume
+// claims backing the given data volumes of the given worker.
+func WorkerDataVolumesToVolumes(customObject v1alpha1.KVMConfig, nodeID string, dataVolumes []WorkerDataVolume) []corev1.Volume {
+	var volumes []corev1.Volume
+
+	for _, dataVolume := range dataVolumes {
+		v := corev1.Volume{
+			Name: dataVolume.MountTag,
+			VolumeSource: corev1.VolumeSource{
+				PersistentVolumeClaim: &corev1.PersistentVolumeClaimVolumeSource{
+					ClaimName: WorkerDataVolumePVCName(ClusterID(customObject), nodeID, dataVolume.MountTag),
+				},
+			},
+		}
+
+		volumes = append(volumes, v)
+	}
+
+	return volumes
 }
 
 func HostVolumesToEnvVar(hostVolumes []v1alpha1.KVMConfigSpecKVMNodeHostVolumes) corev1.EnvVar {
@@ -427,8 +1087,16 @@ func AnyPodContainerRunning(pod corev1.Pod) bool {
 	return false
 }
 
+// LivenessPort returns the port the liveness probes of the given cluster are
+// served on. Clusters without allocated port fall back to the port derived
+// from their VNI.
 func LivenessPort(customObject v1alpha1.KVMConfig) int32 {
-	return int32(livenessPortBase + customObject.Spec.KVM.Network.Flannel.VNI)
+	port, ok := AllocatedHostPorts(customObject)[HostPortLiveness]
+	if !ok {
+		port = LegacyHostPort(customObject, HostPortLiveness)
+	}
+
+	return int32(port)
 }
 
 func MasterCount(customObject v1alpha1.KVMConfig) int {
@@ -500,6 +1168,48 @@ func MemoryQuantityWorker(n v1alpha1.KVMConfigSpecKVMNode) (resource.Quantity, e
 	return q, nil
 }
 
+// NetworkPolicyAllowList returns the additional NetworkPolicy ingress rules
+// defined in the AnnotationNetworkPolicyAllowList annotation of the given
+// KVMConfig. Every rule has to name the peers it allows traffic from, so that
+// the allow-list cannot silently lift the isolation of the cluster.
+func NetworkPolicyAllowList(customObject v1alpha1.KVMConfig) ([]networkingv1.NetworkPolicyIngressRule, error) {
+	v := customObject.GetAnnotations()[AnnotationNetworkPolicyAllowList]
+	if v == "" {
+		return nil, nil
+	}
+
+	var rules []networkingv1.NetworkPolicyIngressRule
+	err := json.Unmarshal([]byte(v), &rules)
+	if err != nil {
+		return nil, microerror.Maskf(invalidNetworkPolicyError, "annotation %#q must be a JSON list: %s", AnnotationNetworkPolicyAllowList, err)
+	}
+
+	for i, rule := range rules {
+		if len(rule.From) == 0 {
+			return nil, microerror.Maskf(invalidNetworkPolicyError, "rule %d must allow traffic from at least one peer", i)
+		}
+
+		for _, peer := range rule.From {
+			if peer.IPBlock == nil {
+				continue
+			}
+
+			_, _, err := net.ParseCIDR(peer.IPBlock.CIDR)
+			if err != nil {
+				return nil, microerror.Maskf(invalidNetworkPolicyError, "rule %d IP block %#q must be a CIDR", i, peer.IPBlock.CIDR)
+			}
+			for _, e := range peer.IPBlock.Except {
+				_, _, err := net.ParseCIDR(e)
+				if err != nil {
+					return nil, microerror.Maskf(invalidNetworkPolicyError, "rule %d IP block exception %#q must be a CIDR", i, e)
+				}
+			}
+		}
+	}
+
+	return rules, nil
+}
+
 func NetworkDNSBlock(servers []net.IP) string {
 	var dnsBlockParts []string
 
@@ -540,6 +1250,19 @@ func NodeIndex(cr v1alpha1.KVMConfig, nodeID string) (int, bool) {
 	return idx, present
 }
 
+// NodeInformerStatus returns the health of the node informer of the given
+// cluster persisted in its status. An empty string is returned in case it is
+// not known yet.
+func NodeInformerStatus(cr v1alpha1.KVMConfig) string {
+	for _, c := range ResourceStatusConditions(cr, NodeControllerResourceName) {
+		if c.Status == string(corev1.ConditionTrue) && strings.HasPrefix(c.Type, NodeInformerCondition) {
+			return strings.TrimPrefix(c.Type, NodeInformerCondition)
+		}
+	}
+
+	return ""
+}
+
 // NodeInternalIP examines the Status Addresses of a Node
 // and returns its InternalIP..
 func NodeInternalIP(node corev1.Node) (string, error) {
@@ -551,6 +1274,102 @@ func NodeInternalIP(node corev1.Node) (string, error) {
 	return "", microerror.Maskf(missingNodeInternalIP, "node %s does not have an InternalIP address in its status", node.Name)
 }
 
+// NodeLabelsAndTaints are the labels and taints of a workload cluster node
+// given via AnnotationNodeLabelsAndTaints.
+type NodeLabelsAndTaints struct {
+	Labels map[string]string `json:"labels,omitempty"`
+	Taints []corev1.Taint    `json:"taints,omitempty"`
+}
+
+// reservedNodeLabels are used by the operator to select workload cluster nodes
+// and cannot be configured via AnnotationNodeLabelsAndTaints.
+var reservedNodeLabels = []string{
+	"role",
+	label.OperatorVersion,
+}
+
+// NodeLabelsAndTaintsFor returns the labels and taints of the given node as
+// configured via AnnotationNodeLabelsAndTaints. Labels and taints configured
+// for the node ID take precedence over the ones configured for its role,
+// which take precedence over the ones configured for all nodes. Taints are
+// identified by key and effect.
+func NodeLabelsAndTaintsFor(customObject v1alpha1.KVMConfig, role string, nodeID string) (NodeLabelsAndTaints, error) {
+	v := customObject.GetAnnotations()[AnnotationNodeLabelsAndTaints]
+	if v == "" {
+		return NodeLabelsAndTaints{}, nil
+	}
+
+	var all map[string]NodeLabelsAndTaints
+	err := json.Unmarshal([]byte(v), &all)
+	if err != nil {
+		return NodeLabelsAndTaints{}, microerror.Maskf(invalidNodeLabelsAndTaintsError, "annotation %#q must be a JSON object: %s", AnnotationNodeLabelsAndTaints, err)
+	}
+
+	for id, config := range all {
+		for k, v := range config.Labels {
+			for _, reserved := range reservedNodeLabels {
+				if k == reserved {
+					return NodeLabelsAndTaints{}, microerror.Maskf(invalidNodeLabelsAndTaintsError, "label %#q of %#q is reserved", k, id)
+				}
+			}
+			if errs := validation.IsQualifiedName(k); len(errs) > 0 {
+				return NodeLabelsAndTaints{}, microerror.Maskf(invalidNodeLabelsAndTaintsError, "label key %#q of %#q is invalid: %s", k, id, strings.Join(errs, ", "))
+			}
+			if errs := validation.IsValidLabelValue(v); len(errs) > 0 {
+				return NodeLabelsAndTaints{}, microerror.Maskf(invalidNodeLabelsAndTaintsError, "label value %#q of %#q is invalid: %s", v, id, strings.Join(errs, ", "))
+			}
+		}
+
+		for _, t := range config.Taints {
+			if errs := validation.IsQualifiedName(t.Key); len(errs) > 0 {
+				return NodeLabelsAndTaints{}, microerror.Maskf(invalidNodeLabelsAndTaintsError, "taint key %#q of %#q is invalid: %s", t.Key, id, strings.Join(errs, ", "))
+			}
+			if t.Value != "" {
+				if errs := validation.IsValidLabelValue(t.Value); len(errs) > 0 {
+					return NodeLabelsAndTaints{}, microerror.Maskf(invalidNodeLabelsAndTaintsError, "taint value %#q of %#q is invalid: %s", t.Value, id, strings.Join(errs, ", "))
+				}
+			}
+			switch t.Effect {
+			case corev1.TaintEffectNoSchedule, corev1.TaintEffectPreferNoSchedule, corev1.TaintEffectNoExecute:
+			default:
+				return NodeLabelsAndTaints{}, microerror.Maskf(invalidNodeLabelsAndTaintsError, "taint effect %#q of %#q must be one of %#q, %#q or %#q", t.Effect, id, corev1.TaintEffectNoSchedule, corev1.TaintEffectPreferNoSchedule, corev1.TaintEffectNoExecute)
+			}
+		}
+	}
+
+	result := NodeLabelsAndTaints{}
+	for _, id := range []string{"*", role, nodeID} {
+		config, ok := all[id]
+		if !ok || id == "" {
+			continue
+		}
+
+		for k, v := range config.Labels {
+			if result.Labels == nil {
+				result.Labels = map[string]string{}
+			}
+			result.Labels[k] = v
+		}
+
+		for _, t := range config.Taints {
+			t.TimeAdded = nil
+
+			replaced := false
+			for i, r := range result.Taints {
+				if r.MatchTaint(&t) {
+					result.Taints[i] = t
+					replaced = true
+				}
+			}
+			if !replaced {
+				result.Taints = append(result.Taints, t)
+			}
+		}
+	}
+
+	return result, nil
+}
+
 func NodePodObjectKey(cluster v1alpha1.KVMConfig, node corev1.Node) client.ObjectKey {
 	return client.ObjectKey{
 		Name:      node.Name,
@@ -558,10 +1377,34 @@ func NodePodObjectKey(cluster v1alpha1.KVMConfig, node corev1.Node) client.Objec
 	}
 }
 
+// OIDCConfigMapName returns the name of the ConfigMap holding the cluster
+// specific OIDC configuration. The ConfigMap is expected in the namespace of the
+// given KVMConfig. An empty string is returned in case the cluster uses the
+// installation wide OIDC configuration.
+func OIDCConfigMapName(cr v1alpha1.KVMConfig) string {
+	return cr.GetAnnotations()[AnnotationOIDCConfigMap]
+}
+
 func OperatorVersion(cr v1alpha1.KVMConfig) string {
 	return cr.GetLabels()[label.OperatorVersion]
 }
 
+// ParseCertificate returns the first certificate of the given PEM encoded
+// data.
+func ParseCertificate(data []byte) (*x509.Certificate, error) {
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, microerror.Maskf(invalidCertificateError, "PEM data must not be empty")
+	}
+
+	certificate, err := x509.ParseCertificate(block.Bytes)
+	if err != nil {
+		return nil, microerror.Maskf(invalidCertificateError, "%s", err)
+	}
+
+	return certificate, nil
+}
+
 // PodIsReady examines the Status Conditions of a Pod
 // and returns true if the PodReady Condition is true.
 func PodIsReady(pod corev1.Pod) bool {
@@ -609,6 +1452,173 @@ func PortMappings(customObject v1alpha1.KVMConfig) []corev1.ServicePort {
 	return ports
 }
 
+// ResourceStatusConditions returns the conditions tracked by the operatorkit
+// resource with the given name in the status of the given KVMConfig.
+func ResourceStatusConditions(cr v1alpha1.KVMConfig, resourceName string) []v1alpha1.StatusClusterResourceCondition {
+	for _, r := range cr.Status.Cluster.Resources {
+		if r.Name == resourceName {
+			return r.Conditions
+		}
+	}
+
+	return nil
+}
+
+// UpdateResourceStatus records the given conditions of the operatorkit
+// resource with the given name in the status of the given KVMConfig. The
+// conditions replace all conditions previously recorded by the resource. The
+// last transition time of conditions whose status did not change is kept. It
+// returns true in case the status has been updated.
+func UpdateResourceStatus(ctx context.Context, g8sClient versioned.Interface, cr v1alpha1.KVMConfig, resourceName string, conditions []v1alpha1.StatusClusterResourceCondition) (bool, error) {
+	current := ResourceStatusConditions(cr, resourceName)
+
+	modified := len(current) != len(conditions)
+	for i := range conditions {
+		conditions[i].LastTransitionTime = v1.Now()
+		for _, c := range current {
+			if c.Type == conditions[i].Type && c.Status == conditions[i].Status {
+				conditions[i].LastTransitionTime = c.LastTransitionTime
+			}
+		}
+
+		if !modified && (current[i].Type != conditions[i].Type || current[i].Status != conditions[i].Status) {
+			modified = true
+		}
+	}
+
+	if !modified {
+		return false, nil
+	}
+
+	newObj, err := g8sClient.ProviderV1alpha1().KVMConfigs(cr.GetNamespace()).Get(ctx, cr.GetName(), v1.GetOptions{})
+	if apierrors.IsNotFound(err) {
+		return false, nil
+	} else if err != nil {
+		return false, microerror.Mask(err)
+	}
+
+	newObj.Status.Cluster.Resources = withResourceStatusConditions(newObj.Status.Cluster.Resources, resourceName, conditions)
+
+	_, err = g8sClient.ProviderV1alpha1().KVMConfigs(newObj.GetNamespace()).UpdateStatus(ctx, newObj, v1.UpdateOptions{})
+	if err != nil {
+		return false, microerror.Mask(err)
+	}
+
+	return true, nil
+}
+
+// StatusAnnotation decodes the JSON value of the given annotation of the given
+// KVMConfig into v. Such annotations hold state operatorkit resources persist
+// which does not fit into status conditions, e.g. allocated ports. Missing or
+// malformed annotations leave v untouched.
+func StatusAnnotation(cr v1alpha1.KVMConfig, annotation string, v interface{}) {
+	s, ok := cr.GetAnnotations()[annotation]
+	if !ok {
+		return
+	}
+
+	_ = json.Unmarshal([]byte(s), v)
+}
+
+// UpdateStatusAnnotation records v encoded as JSON in the given annotation of
+// the given KVMConfig. Empty values remove the annotation. It returns true in
+// case the KVMConfig has been updated.
+func UpdateStatusAnnotation(ctx context.Context, g8sClient versioned.Interface, cr v1alpha1.KVMConfig, annotation string, v interface{}) (bool, error) {
+	b, err := json.Marshal(v)
+	if err != nil {
+		return false, microerror.Mask(err)
+	}
+
+	desired := string(b)
+	if desired == "null" || desired == "{}" || desired == "[]" {
+		desired = ""
+	}
+
+	if cr.GetAnnotations()[annotation] == desired {
+		return false, nil
+	}
+
+	newObj, err := g8sClient.ProviderV1alpha1().KVMConfigs(cr.GetNamespace()).Get(ctx, cr.GetName(), v1.GetOptions{})
+	if apierrors.IsNotFound(err) {
+		return false, nil
+	} else if err != nil {
+		return false, microerror.Mask(err)
+	}
+
+	if newObj.GetAnnotations()[annotation] == desired {
+		return false, nil
+	}
+
+	annotations := newObj.GetAnnotations()
+	if desired == "" {
+		delete(annotations, annotation)
+	} else {
+		if annotations == nil {
+			annotations = map[string]string{}
+		}
+		annotations[annotation] = desired
+	}
+	newObj.SetAnnotations(annotations)
+
+	_, err = g8sClient.ProviderV1alpha1().KVMConfigs(newObj.GetNamespace()).Update(ctx, newObj, v1.UpdateOptions{})
+	if err != nil {
+		return false, microerror.Mask(err)
+	}
+
+	return true, nil
+}
+
+// withResourceStatusConditions returns a copy of the given resource statuses
+// in which the conditions of the operatorkit resource with the given name are
+// replaced by the given conditions.
+func withResourceStatusConditions(resources []v1alpha1.StatusClusterResource, resourceName string, conditions []v1alpha1.StatusClusterResourceCondition) []v1alpha1.StatusClusterResource {
+	var newResources []v1alpha1.StatusClusterResource
+	var found bool
+
+	for _, r := range resources {
+		if r.Name == resourceName {
+			r = v1alpha1.StatusClusterResource{
+				Conditions: conditions,
+				Name:       resourceName,
+			}
+			found = true
+		}
+		newResources = append(newResources, r)
+	}
+
+	if !found {
+		newResources = append(newResources, v1alpha1.StatusClusterResource{
+			Conditions: conditions,
+			Name:       resourceName,
+		})
+	}
+
+	return newResources
+}
+
+// CoreComponents are the release components whose versions are annotated on
+// the pod templates of master and worker deployments, prefixed with
+// AnnotationComponentVersionPrefix.
+var CoreComponents = []string{"calico", "containerlinux", "etcd", "kubernetes"}
+
+// ReleaseComponentVersion returns the version of the given component within
+// the given release. An empty string is returned in case the component is not
+// part of the release.
+func ReleaseComponentVersion(release *releasev1alpha1.Release, component string) string {
+	for _, releaseComponent := range release.Spec.Components {
+		if releaseComponent.Name == component {
+			return releaseComponent.Version
+		}
+	}
+
+	return ""
+}
+
+// ReleaseName returns the name of the Release CR of the given cluster.
+func ReleaseName(cr v1alpha1.KVMConfig) string {
+	return fmt.Sprintf("v%s", ReleaseVersion(cr))
+}
+
 func ReleaseVersion(cr v1alpha1.KVMConfig) string {
 	return cr.GetLabels()[label.ReleaseVersion]
 }
@@ -617,8 +1627,16 @@ func ServiceAccountName(customObject v1alpha1.KVMConfig) string {
 	return ClusterID(customObject)
 }
 
+// ShutdownDeferrerListenPort returns the port the shutdown-deferrer of the
+// given cluster listens on. Clusters without allocated port fall back to the
+// port derived from their VNI.
 func ShutdownDeferrerListenPort(customObject v1alpha1.KVMConfig) int {
-	return int(shutdownDeferrerPortBase + customObject.Spec.KVM.Network.Flannel.VNI)
+	port, ok := AllocatedHostPorts(customObject)[HostPortShutdownDeferrer]
+	if !ok {
+		port = LegacyHostPort(customObject, HostPortShutdownDeferrer)
+	}
+
+	return port
 }
 
 func ShutdownDeferrerListenAddress(customObject v1alpha1.KVMConfig) string {
@@ -629,10 +1647,24 @@ func ShutdownDeferrerPollPath(customObject v1alpha1.KVMConfig) string {
 	return fmt.Sprintf("%s/v1/defer/", ShutdownDeferrerListenAddress(customObject))
 }
 
+// EtcdStorageClass returns the storage class of the persistent volumes
+// backing etcd as configured for the given cluster. An empty string is
+// returned in case the installation default should be used.
+func EtcdStorageClass(customObject v1alpha1.KVMConfig) string {
+	return customObject.GetAnnotations()[AnnotationEtcdStorageClass]
+}
+
 func EtcdStorageType(customObject v1alpha1.KVMConfig) string {
 	return customObject.Spec.KVM.K8sKVM.StorageType
 }
 
+// EtcdVolumeSize returns the size of the persistent volumes backing etcd as
+// configured for the given cluster, e.g. "30Gi". An empty string is returned
+// in case the installation default should be used.
+func EtcdVolumeSize(customObject v1alpha1.KVMConfig) string {
+	return customObject.GetAnnotations()[AnnotationEtcdVolumeSize]
+}
+
 func HasHostVolumes(customObject v1alpha1.KVMConfig) bool {
 	for _, worker := range customObject.Spec.KVM.Workers {
 		if len(worker.HostVolumes) > 0 {
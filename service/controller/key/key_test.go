@@ -1,11 +1,16 @@
 package key
 
 import (
+	"context"
 	"net"
+	"reflect"
 	"testing"
+	"time"
 
 	"github.com/giantswarm/apiextensions/v3/pkg/apis/provider/v1alpha1"
+	"github.com/giantswarm/apiextensions/v3/pkg/clientset/versioned/fake"
 	corev1 "k8s.io/api/core/v1"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/util/intstr"
 )
 
@@ -186,3 +191,649 @@ func Test_PortMappings_CompatibilityMode(t *testing.T) {
 		}
 	}
 }
+
+func Test_WorkerDataVolumes(t *testing.T) {
+	testCases := []struct {
+		name                string
+		annotation          string
+		nodeID              string
+		hostVolumeMountTags []string
+		expected            []WorkerDataVolume
+		errorMatcher        func(error) bool
+	}{
+		{
+			name:   "case 0: no data volumes",
+			nodeID: "a1b2c",
+		},
+		{
+			name:       "case 1: data volumes of all workers",
+			annotation: `{"*":[{"mountTag":"data","hostPath":"/data","size":"100Gi","storageClass":"ceph-rbd"},{"mountTag":"scratch","hostPath":"/scratch","size":"10Gi"}]}`,
+			nodeID:     "a1b2c",
+			expected: []WorkerDataVolume{
+				{MountTag: "data", HostPath: "/data", Size: "100Gi", StorageClass: "ceph-rbd"},
+				{MountTag: "scratch", HostPath: "/scratch", Size: "10Gi"},
+			},
+		},
+		{
+			name:       "case 2: data volumes of the node replace data volumes of all workers",
+			annotation: `{"*":[{"mountTag":"data","hostPath":"/data","size":"100Gi"}],"a1b2c":[{"mountTag":"data","hostPath":"/data","size":"200Gi"},{"mountTag":"scratch","hostPath":"/scratch","size":"10Gi"}]}`,
+			nodeID:     "a1b2c",
+			expected: []WorkerDataVolume{
+				{MountTag: "data", HostPath: "/data", Size: "200Gi"},
+				{MountTag: "scratch", HostPath: "/scratch", Size: "10Gi"},
+			},
+		},
+		{
+			name:       "case 3: data volumes of other nodes are ignored",
+			annotation: `{"d3e4f":[{"mountTag":"data","hostPath":"/data","size":"100Gi"}]}`,
+			nodeID:     "a1b2c",
+		},
+		{
+			name:         "case 4: invalid JSON",
+			annotation:   `[{"mountTag":"data","hostPath":"/data","size":"1Gi"}]`,
+			nodeID:       "a1b2c",
+			errorMatcher: IsInvalidDataVolume,
+		},
+		{
+			name:         "case 5: duplicate mount tag",
+			annotation:   `{"*":[{"mountTag":"data","hostPath":"/data","size":"1Gi"},{"mountTag":"data","hostPath":"/other","size":"1Gi"}]}`,
+			nodeID:       "a1b2c",
+			errorMatcher: IsInvalidDataVolume,
+		},
+		{
+			name:         "case 6: relative host path",
+			annotation:   `{"*":[{"mountTag":"data","hostPath":"data","size":"1Gi"}]}`,
+			nodeID:       "a1b2c",
+			errorMatcher: IsInvalidDataVolume,
+		},
+		{
+			name:         "case 7: invalid size of other node",
+			annotation:   `{"d3e4f":[{"mountTag":"data","hostPath":"/data","size":"0"}]}`,
+			nodeID:       "a1b2c",
+			errorMatcher: IsInvalidDataVolume,
+		},
+		{
+			name:         "case 8: mount tag which is no DNS-1123 label",
+			annotation:   `{"*":[{"mountTag":"Data_1","hostPath":"/data","size":"1Gi"}]}`,
+			nodeID:       "a1b2c",
+			errorMatcher: IsInvalidDataVolume,
+		},
+		{
+			name:         "case 9: mount tag colliding with a VM pod volume",
+			annotation:   `{"*":[{"mountTag":"dockerfs","hostPath":"/data","size":"1Gi"}]}`,
+			nodeID:       "a1b2c",
+			errorMatcher: IsInvalidDataVolume,
+		},
+		{
+			name:         "case 10: mount tag colliding with a VM pod volume of other node",
+			annotation:   `{"d3e4f":[{"mountTag":"rootfs","hostPath":"/data","size":"1Gi"}]}`,
+			nodeID:       "a1b2c",
+			errorMatcher: IsInvalidDataVolume,
+		},
+		{
+			name:                "case 11: mount tag colliding with a host volume",
+			annotation:          `{"*":[{"mountTag":"data","hostPath":"/data","size":"1Gi"}]}`,
+			nodeID:              "a1b2c",
+			hostVolumeMountTags: []string{"data"},
+			errorMatcher:        IsInvalidDataVolume,
+		},
+		{
+			name:                "case 12: mount tag next to a host volume",
+			annotation:          `{"*":[{"mountTag":"data","hostPath":"/data","size":"1Gi"}]}`,
+			nodeID:              "a1b2c",
+			hostVolumeMountTags: []string{"local"},
+			expected: []WorkerDataVolume{
+				{MountTag: "data", HostPath: "/data", Size: "1Gi"},
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			customObject := v1alpha1.KVMConfig{}
+			if tc.annotation != "" {
+				customObject.Annotations = map[string]string{
+					AnnotationWorkerDataVolumes: tc.annotation,
+				}
+			}
+			if tc.hostVolumeMountTags != nil {
+				var hostVolumes []v1alpha1.KVMConfigSpecKVMNodeHostVolumes
+				for _, mountTag := range tc.hostVolumeMountTags {
+					hostVolumes = append(hostVolumes, v1alpha1.KVMConfigSpecKVMNodeHostVolumes{
+						HostPath: "/" + mountTag,
+						MountTag: mountTag,
+					})
+				}
+				customObject.Spec.Cluster.Workers = []v1alpha1.ClusterNode{
+					{ID: tc.nodeID},
+				}
+				customObject.Spec.KVM.Workers = []v1alpha1.KVMConfigSpecKVMNode{
+					{HostVolumes: hostVolumes},
+				}
+			}
+
+			dataVolumes, err := WorkerDataVolumes(customObject, tc.nodeID)
+
+			switch {
+			case err == nil && tc.errorMatcher == nil:
+				// correct; carry on
+			case err != nil && tc.errorMatcher == nil:
+				t.Fatalf("error == %#v, want nil", err)
+			case err == nil && tc.errorMatcher != nil:
+				t.Fatalf("error == nil, want non-nil")
+			case !tc.errorMatcher(err):
+				t.Fatalf("error == %#v, want matching", err)
+			case tc.errorMatcher(err):
+				return
+			}
+
+			if !reflect.DeepEqual(dataVolumes, tc.expected) {
+				t.Fatalf("expected %#v got %#v", tc.expected, dataVolumes)
+			}
+		})
+	}
+}
+
+func Test_HasPersistentDisks(t *testing.T) {
+	testCases := []struct {
+		name       string
+		annotation string
+		nodeID     string
+		expected   bool
+	}{
+		{
+			name:     "case 0: no annotation",
+			nodeID:   "a",
+			expected: false,
+		},
+		{
+			name:       "case 1: all workers",
+			annotation: "*",
+			nodeID:     "a",
+			expected:   true,
+		},
+		{
+			name:       "case 2: listed worker",
+			annotation: "a, b",
+			nodeID:     "b",
+			expected:   true,
+		},
+		{
+			name:       "case 3: unlisted worker",
+			annotation: "a,b",
+			nodeID:     "c",
+			expected:   false,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			customObject := v1alpha1.KVMConfig{}
+			customObject.Annotations = map[string]string{
+				AnnotationPersistentWorkerDisks: tc.annotation,
+			}
+
+			if HasPersistentDisks(customObject, tc.nodeID) != tc.expected {
+				t.Fatalf("expected %t got %t", tc.expected, !tc.expected)
+			}
+		})
+	}
+}
+
+func Test_NodeDiskSizes(t *testing.T) {
+	testCases := []struct {
+		name            string
+		annotation      string
+		nodeID          string
+		expectedKubelet string
+		expectedRoot    string
+		errorMatcher    func(error) bool
+	}{
+		{
+			name:            "case 0: defaults without annotation",
+			nodeID:          "a",
+			expectedKubelet: DefaultKubeletDiskSize,
+			expectedRoot:    DefaultOSDiskSize,
+		},
+		{
+			name:            "case 1: sizes for all nodes",
+			annotation:      `{"*":{"kubeletVolumeSizeGB":20,"rootVolumeSizeGB":10}}`,
+			nodeID:          "a",
+			expectedKubelet: "20G",
+			expectedRoot:    "10G",
+		},
+		{
+			name:            "case 2: node specific sizes override sizes for all nodes",
+			annotation:      `{"*":{"kubeletVolumeSizeGB":20,"rootVolumeSizeGB":10},"a":{"kubeletVolumeSizeGB":30}}`,
+			nodeID:          "a",
+			expectedKubelet: "30G",
+			expectedRoot:    "10G",
+		},
+		{
+			name:            "case 3: sizes of other nodes are ignored",
+			annotation:      `{"b":{"kubeletVolumeSizeGB":30}}`,
+			nodeID:          "a",
+			expectedKubelet: DefaultKubeletDiskSize,
+			expectedRoot:    DefaultOSDiskSize,
+		},
+		{
+			name:         "case 4: negative kubelet size",
+			annotation:   `{"a":{"kubeletVolumeSizeGB":-1}}`,
+			nodeID:       "a",
+			errorMatcher: IsInvalidDiskSize,
+		},
+		{
+			name:         "case 5: root size below minimum",
+			annotation:   `{"b":{"rootVolumeSizeGB":2}}`,
+			nodeID:       "a",
+			errorMatcher: IsInvalidDiskSize,
+		},
+		{
+			name:         "case 6: malformed annotation",
+			annotation:   `[`,
+			nodeID:       "a",
+			errorMatcher: IsInvalidDiskSize,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			cr := v1alpha1.KVMConfig{}
+			if tc.annotation != "" {
+				cr.Annotations = map[string]string{
+					AnnotationNodeDiskSizes: tc.annotation,
+				}
+			}
+
+			kubelet, err := KubeletVolumeSize(cr, tc.nodeID)
+			if tc.errorMatcher != nil {
+				if !tc.errorMatcher(err) {
+					t.Fatalf("expected matching error got %#v", err)
+				}
+				return
+			} else if err != nil {
+				t.Fatalf("expected %#v got %#v", nil, err)
+			}
+
+			root, err := RootVolumeSize(cr, tc.nodeID)
+			if err != nil {
+				t.Fatalf("expected %#v got %#v", nil, err)
+			}
+
+			if kubelet != tc.expectedKubelet {
+				t.Fatalf("expected kubelet size %#q got %#q", tc.expectedKubelet, kubelet)
+			}
+			if root != tc.expectedRoot {
+				t.Fatalf("expected root size %#q got %#q", tc.expectedRoot, root)
+			}
+		})
+	}
+}
+
+func Test_NodeLabelsAndTaintsFor(t *testing.T) {
+	testCases := []struct {
+		name         string
+		annotation   string
+		role         string
+		nodeID       string
+		expected     NodeLabelsAndTaints
+		errorMatcher func(error) bool
+	}{
+		{
+			name:     "case 0: nothing without annotation",
+			role:     WorkerID,
+			nodeID:   "a",
+			expected: NodeLabelsAndTaints{},
+		},
+		{
+			name:       "case 1: node ID overrides role overrides all nodes",
+			annotation: `{"*":{"labels":{"team":"a","tier":"x"},"taints":[{"key":"dedicated","value":"a","effect":"NoSchedule"}]},"worker":{"labels":{"tier":"y"}},"a":{"labels":{"team":"b"},"taints":[{"key":"dedicated","value":"b","effect":"NoSchedule"}]}}`,
+			role:       WorkerID,
+			nodeID:     "a",
+			expected: NodeLabelsAndTaints{
+				Labels: map[string]string{
+					"team": "b",
+					"tier": "y",
+				},
+				Taints: []corev1.Taint{
+					{Key: "dedicated", Value: "b", Effect: corev1.TaintEffectNoSchedule},
+				},
+			},
+		},
+		{
+			name:       "case 2: configuration of other roles and nodes is ignored",
+			annotation: `{"master":{"labels":{"tier":"y"}},"b":{"taints":[{"key":"dedicated","effect":"NoExecute"}]}}`,
+			role:       WorkerID,
+			nodeID:     "a",
+			expected:   NodeLabelsAndTaints{},
+		},
+		{
+			name:       "case 3: taints with different effects are kept",
+			annotation: `{"*":{"taints":[{"key":"dedicated","effect":"NoSchedule"}]},"a":{"taints":[{"key":"dedicated","effect":"NoExecute"}]}}`,
+			role:       WorkerID,
+			nodeID:     "a",
+			expected: NodeLabelsAndTaints{
+				Taints: []corev1.Taint{
+					{Key: "dedicated", Effect: corev1.TaintEffectNoSchedule},
+					{Key: "dedicated", Effect: corev1.TaintEffectNoExecute},
+				},
+			},
+		},
+		{
+			name:         "case 4: reserved label",
+			annotation:   `{"*":{"labels":{"role":"worker"}}}`,
+			role:         WorkerID,
+			nodeID:       "a",
+			errorMatcher: IsInvalidNodeLabelsAndTaints,
+		},
+		{
+			name:         "case 5: invalid label value",
+			annotation:   `{"b":{"labels":{"team":"a b"}}}`,
+			role:         WorkerID,
+			nodeID:       "a",
+			errorMatcher: IsInvalidNodeLabelsAndTaints,
+		},
+		{
+			name:         "case 6: invalid taint effect",
+			annotation:   `{"*":{"taints":[{"key":"dedicated","effect":"Never"}]}}`,
+			role:         WorkerID,
+			nodeID:       "a",
+			errorMatcher: IsInvalidNodeLabelsAndTaints,
+		},
+		{
+			name:         "case 7: malformed annotation",
+			annotation:   `[`,
+			role:         WorkerID,
+			nodeID:       "a",
+			errorMatcher: IsInvalidNodeLabelsAndTaints,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			cr := v1alpha1.KVMConfig{}
+			if tc.annotation != "" {
+				cr.Annotations = map[string]string{
+					AnnotationNodeLabelsAndTaints: tc.annotation,
+				}
+			}
+
+			result, err := NodeLabelsAndTaintsFor(cr, tc.role, tc.nodeID)
+			if tc.errorMatcher != nil {
+				if !tc.errorMatcher(err) {
+					t.Fatalf("expected matching error got %#v", err)
+				}
+				return
+			} else if err != nil {
+				t.Fatalf("expected %#v got %#v", nil, err)
+			}
+
+			if !reflect.DeepEqual(result, tc.expected) {
+				t.Fatalf("expected %#v got %#v", tc.expected, result)
+			}
+		})
+	}
+}
+
+func Test_NetworkPolicyAllowList(t *testing.T) {
+	testCases := []struct {
+		name          string
+		annotation    string
+		expectedRules int
+		errorMatcher  func(error) bool
+	}{
+		{
+			name: "case 0: no allow-list without annotation",
+		},
+		{
+			name:          "case 1: allow-list with IP block and namespace",
+			annotation:    `[{"from":[{"ipBlock":{"cidr":"10.0.0.0/8","except":["10.1.0.0/16"]}}],"ports":[{"port":22}]},{"from":[{"namespaceSelector":{"matchLabels":{"name":"monitoring"}}}]}]`,
+			expectedRules: 2,
+		},
+		{
+			name:         "case 2: rule without peers",
+			annotation:   `[{"ports":[{"port":22}]}]`,
+			errorMatcher: IsInvalidNetworkPolicy,
+		},
+		{
+			name:         "case 3: invalid IP block",
+			annotation:   `[{"from":[{"ipBlock":{"cidr":"10.0.0.0"}}]}]`,
+			errorMatcher: IsInvalidNetworkPolicy,
+		},
+		{
+			name:         "case 4: invalid IP block exception",
+			annotation:   `[{"from":[{"ipBlock":{"cidr":"10.0.0.0/8","except":["10.1.0.0"]}}]}]`,
+			errorMatcher: IsInvalidNetworkPolicy,
+		},
+		{
+			name:         "case 5: malformed annotation",
+			annotation:   `{`,
+			errorMatcher: IsInvalidNetworkPolicy,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			cr := v1alpha1.KVMConfig{}
+			if tc.annotation != "" {
+				cr.Annotations = map[string]string{
+					AnnotationNetworkPolicyAllowList: tc.annotation,
+				}
+			}
+
+			rules, err := NetworkPolicyAllowList(cr)
+			if tc.errorMatcher != nil {
+				if !tc.errorMatcher(err) {
+					t.Fatalf("expected matching error got %#v", err)
+				}
+				return
+			} else if err != nil {
+				t.Fatalf("expected %#v got %#v", nil, err)
+			}
+
+			if len(rules) != tc.expectedRules {
+				t.Fatalf("expected %d rules got %d", tc.expectedRules, len(rules))
+			}
+		})
+	}
+}
+
+func Test_WorkerService(t *testing.T) {
+	testCases := []struct {
+		name         string
+		annotation   string
+		expected     WorkerServiceConfig
+		errorMatcher func(error) bool
+	}{
+		{
+			name: "case 0: NodePort without annotation",
+			expected: WorkerServiceConfig{
+				Type: corev1.ServiceTypeNodePort,
+			},
+		},
+		{
+			name:       "case 1: ClusterIP",
+			annotation: `{"type":"ClusterIP"}`,
+			expected: WorkerServiceConfig{
+				Type: corev1.ServiceTypeClusterIP,
+			},
+		},
+		{
+			name:       "case 2: LoadBalancer with options",
+			annotation: `{"type":"LoadBalancer","annotations":{"metallb.universe.tf/address-pool":"ingress"},"loadBalancerSourceRanges":["10.0.0.0/8"]}`,
+			expected: WorkerServiceConfig{
+				Annotations: map[string]string{
+					"metallb.universe.tf/address-pool": "ingress",
+				},
+				LoadBalancerSourceRanges: []string{"10.0.0.0/8"},
+				Type:                     corev1.ServiceTypeLoadBalancer,
+			},
+		},
+		{
+			name:         "case 3: unsupported type",
+			annotation:   `{"type":"ExternalName"}`,
+			errorMatcher: IsInvalidWorkerService,
+		},
+		{
+			name:         "case 4: source ranges without LoadBalancer",
+			annotation:   `{"loadBalancerSourceRanges":["10.0.0.0/8"]}`,
+			errorMatcher: IsInvalidWorkerService,
+		},
+		{
+			name:         "case 5: invalid source range",
+			annotation:   `{"type":"LoadBalancer","loadBalancerSourceRanges":["10.0.0.0"]}`,
+			errorMatcher: IsInvalidWorkerService,
+		},
+		{
+			name:         "case 6: malformed annotation",
+			annotation:   `{`,
+			errorMatcher: IsInvalidWorkerService,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			cr := v1alpha1.KVMConfig{}
+			if tc.annotation != "" {
+				cr.Annotations = map[string]string{
+					AnnotationWorkerService: tc.annotation,
+				}
+			}
+
+			config, err := WorkerService(cr)
+			if tc.errorMatcher != nil {
+				if !tc.errorMatcher(err) {
+					t.Fatalf("expected matching error got %#v", err)
+				}
+				return
+			} else if err != nil {
+				t.Fatalf("expected %#v got %#v", nil, err)
+			}
+
+			if !reflect.DeepEqual(config, tc.expected) {
+				t.Fatalf("expected %#v got %#v", tc.expected, config)
+			}
+		})
+	}
+}
+
+func Test_UpdateResourceStatus(t *testing.T) {
+	transition := v1.NewTime(time.Date(2021, 6, 1, 12, 0, 0, 0, time.UTC))
+
+	cr := &v1alpha1.KVMConfig{
+		ObjectMeta: v1.ObjectMeta{
+			Name:      "al9qy",
+			Namespace: "default",
+		},
+		Status: v1alpha1.KVMConfigStatus{
+			Cluster: v1alpha1.StatusCluster{
+				Resources: []v1alpha1.StatusClusterResource{
+					{
+						Name: "test",
+						Conditions: []v1alpha1.StatusClusterResourceCondition{
+							{LastTransitionTime: transition, Status: "True", Type: "A"},
+							{LastTransitionTime: transition, Status: "False", Type: "B"},
+						},
+					},
+				},
+			},
+		},
+	}
+
+	testCases := []struct {
+		name            string
+		conditions      []v1alpha1.StatusClusterResourceCondition
+		expectedUpdated bool
+	}{
+		{
+			name: "case 0: unchanged conditions are not updated",
+			conditions: []v1alpha1.StatusClusterResourceCondition{
+				{Status: "True", Type: "A"},
+				{Status: "False", Type: "B"},
+			},
+			expectedUpdated: false,
+		},
+		{
+			name: "case 1: changed conditions are updated",
+			conditions: []v1alpha1.StatusClusterResourceCondition{
+				{Status: "True", Type: "A"},
+				{Status: "True", Type: "B"},
+			},
+			expectedUpdated: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			g8sClient := fake.NewSimpleClientset(cr)
+
+			updated, err := UpdateResourceStatus(context.Background(), g8sClient, *cr, "test", tc.conditions)
+			if err != nil {
+				t.Fatalf("expected nil, got %#v", err)
+			}
+			if updated != tc.expectedUpdated {
+				t.Fatalf("expected updated %t, got %t", tc.expectedUpdated, updated)
+			}
+
+			newObj, err := g8sClient.ProviderV1alpha1().KVMConfigs(cr.Namespace).Get(context.Background(), cr.Name, v1.GetOptions{})
+			if err != nil {
+				t.Fatalf("expected nil, got %#v", err)
+			}
+
+			conditions := ResourceStatusConditions(*newObj, "test")
+			if !conditions[0].LastTransitionTime.Equal(&transition) {
+				t.Fatalf("expected last transition time of unchanged condition to be kept, got %s", conditions[0].LastTransitionTime)
+			}
+			if tc.expectedUpdated && conditions[1].LastTransitionTime.Equal(&transition) {
+				t.Fatalf("expected last transition time of changed condition to be updated")
+			}
+		})
+	}
+}
+
+func Test_UpdateStatusAnnotation(t *testing.T) {
+	annotation := "kvm-operator.giantswarm.io/test"
+
+	cr := &v1alpha1.KVMConfig{
+		ObjectMeta: v1.ObjectMeta{
+			Name:      "al9qy",
+			Namespace: "default",
+		},
+	}
+	g8sClient := fake.NewSimpleClientset(cr)
+
+	updated, err := UpdateStatusAnnotation(context.Background(), g8sClient, *cr, annotation, map[string]int32{"http": 30100})
+	if err != nil {
+		t.Fatalf("expected nil, got %#v", err)
+	}
+	if !updated {
+		t.Fatalf("expected annotation to be updated")
+	}
+
+	newObj, err := g8sClient.ProviderV1alpha1().KVMConfigs(cr.Namespace).Get(context.Background(), cr.Name, v1.GetOptions{})
+	if err != nil {
+		t.Fatalf("expected nil, got %#v", err)
+	}
+
+	var ports map[string]int32
+	StatusAnnotation(*newObj, annotation, &ports)
+	if ports["http"] != 30100 {
+		t.Fatalf("expected node port %d, got %#v", 30100, ports)
+	}
+
+	updated, err = UpdateStatusAnnotation(context.Background(), g8sClient, *newObj, annotation, map[string]int32{})
+	if err != nil {
+		t.Fatalf("expected nil, got %#v", err)
+	}
+	if !updated {
+		t.Fatalf("expected annotation to be removed")
+	}
+
+	newObj, err = g8sClient.ProviderV1alpha1().KVMConfigs(cr.Namespace).Get(context.Background(), cr.Name, v1.GetOptions{})
+	if err != nil {
+		t.Fatalf("expected nil, got %#v", err)
+	}
+	if _, ok := newObj.GetAnnotations()[annotation]; ok {
+		t.Fatalf("expected annotation to be removed, got %#v", newObj.GetAnnotations())
+	}
+}
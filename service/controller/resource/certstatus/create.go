@@ -0,0 +1,187 @@
+package certstatus
+
+import (
+	"context"
+	"time"
+
+	"github.com/giantswarm/apiextensions/v3/pkg/apis/provider/v1alpha1"
+	"github.com/giantswarm/certs/v3/pkg/certs"
+	"github.com/giantswarm/microerror"
+	corev1 "k8s.io/api/core/v1"
+
+	"github.com/giantswarm/kvm-operator/v4/service/controller/cloudconfig"
+	"github.com/giantswarm/kvm-operator/v4/service/controller/key"
+)
+
+const (
+	// expiryWarningThreshold is the time before the expiry of a certificate
+	// from which on it is considered expiring.
+	expiryWarningThreshold = 30 * 24 * time.Hour
+
+	// conditionCertificateExpired is true while any certificate of the
+	// cluster is expired.
+	conditionCertificateExpired = "CertificateExpired"
+	// conditionCertificateExpiring is true while any certificate of the
+	// cluster expires within expiryWarningThreshold.
+	conditionCertificateExpiring = "CertificateExpiring"
+
+	eventReasonCertificateExpired  = "CertificateExpired"
+	eventReasonCertificateExpiring = "CertificateExpiring"
+	eventReasonCertificatesValid   = "CertificatesValid"
+)
+
+const (
+	certificateStateOK       = "ok"
+	certificateStateExpiring = "expiring"
+	certificateStateExpired  = "expired"
+)
+
+// EnsureCreated records the expiry of every certificate in RFC 3339 format in
+// the AnnotationCertificateExpiry annotation of the KVMConfig. Whether any
+// certificate expired or is about to expire is recorded as condition of the
+// certstatus resource status. Events are only emitted when the state derived
+// from these conditions transitions, not on every reconciliation.
+func (r *Resource) EnsureCreated(ctx context.Context, obj interface{}) error {
+	cr, err := key.ToCustomObject(obj)
+	if err != nil {
+		return microerror.Mask(err)
+	}
+
+	expiries := map[string]string{}
+	expiredCerts := map[string]string{}
+	expiringCerts := map[string]string{}
+	var expired, expiring bool
+	for _, c := range clusterCerts() {
+		tls, err := r.certsSearcher.SearchTLS(ctx, key.ClusterID(cr), c)
+		if err != nil {
+			return microerror.Mask(err)
+		}
+
+		certificate, err := key.ParseCertificate(tls.Crt)
+		if err != nil {
+			return microerror.Mask(err)
+		}
+
+		expiry := certificate.NotAfter.UTC()
+
+		switch {
+		case time.Now().After(expiry):
+			expired = true
+			expiredCerts[string(c)] = expiry.Format(time.RFC3339)
+		case time.Until(expiry) < expiryWarningThreshold:
+			expiring = true
+			expiringCerts[string(c)] = expiry.Format(time.RFC3339)
+		}
+
+		expiries[string(c)] = expiry.Format(time.RFC3339)
+	}
+
+	{
+		current := certificateState(conditionTrue(cr, conditionCertificateExpired), conditionTrue(cr, conditionCertificateExpiring))
+		desired := certificateState(expired, expiring)
+
+		if current != desired {
+			switch desired {
+			case certificateStateExpired:
+				for c, expiry := range expiredCerts {
+					r.eventRecorder.Eventf(&cr, corev1.EventTypeWarning, eventReasonCertificateExpired, "certificate %#q expired at %s", c, expiry)
+				}
+			case certificateStateExpiring:
+				for c, expiry := range expiringCerts {
+					r.eventRecorder.Eventf(&cr, corev1.EventTypeWarning, eventReasonCertificateExpiring, "certificate %#q expires at %s", c, expiry)
+				}
+			case certificateStateOK:
+				r.eventRecorder.Eventf(&cr, corev1.EventTypeNormal, eventReasonCertificatesValid, "certificates are valid again")
+			}
+		}
+	}
+
+	{
+		updated, err := key.UpdateStatusAnnotation(ctx, r.g8sClient, cr, key.AnnotationCertificateExpiry, expiries)
+		if err != nil {
+			return microerror.Mask(err)
+		}
+
+		if updated {
+			r.logger.Debugf(ctx, "updated certificate expiry")
+		}
+	}
+
+	{
+		conditions := []v1alpha1.StatusClusterResourceCondition{
+			{
+				Status: conditionStatus(expired),
+				Type:   conditionCertificateExpired,
+			},
+			{
+				Status: conditionStatus(expiring),
+				Type:   conditionCertificateExpiring,
+			},
+		}
+
+		updated, err := key.UpdateResourceStatus(ctx, r.g8sClient, cr, Name, conditions)
+		if err != nil {
+			return microerror.Mask(err)
+		}
+
+		if updated {
+			r.logger.Debugf(ctx, "updated status with certificate expiry")
+		}
+	}
+
+	return nil
+}
+
+// clusterCerts returns the certificates rendered into the ignition of masters
+// and workers.
+func clusterCerts() []certs.Cert {
+	var list []certs.Cert
+
+	for _, c := range append(cloudconfig.MasterCerts(), cloudconfig.WorkerCerts()...) {
+		var found bool
+		for _, l := range list {
+			if l == c {
+				found = true
+				break
+			}
+		}
+		if !found {
+			list = append(list, c)
+		}
+	}
+
+	return list
+}
+
+// certificateState returns the state of the cluster certificates. Expired
+// certificates take precedence over expiring ones.
+func certificateState(expired, expiring bool) string {
+	if expired {
+		return certificateStateExpired
+	}
+	if expiring {
+		return certificateStateExpiring
+	}
+
+	return certificateStateOK
+}
+
+// conditionTrue returns whether the given condition of the certstatus
+// resource status is true. Missing conditions are considered false.
+func conditionTrue(cr v1alpha1.KVMConfig, conditionType string) bool {
+	for _, c := range key.ResourceStatusConditions(cr, Name) {
+		if c.Type == conditionType {
+			return c.Status == string(corev1.ConditionTrue)
+		}
+	}
+
+	return false
+}
+
+func conditionStatus(b bool) string {
+	if b {
+		return string(corev1.ConditionTrue)
+	}
+
+	return string(corev1.ConditionFalse)
+}
@@ -0,0 +1,238 @@
+package certstatus
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/giantswarm/apiextensions/v3/pkg/apis/provider/v1alpha1"
+	"github.com/giantswarm/apiextensions/v3/pkg/clientset/versioned/fake"
+	"github.com/giantswarm/certs/v3/pkg/certs"
+	"github.com/giantswarm/certs/v3/pkg/certstest"
+	"github.com/giantswarm/micrologger/microloggertest"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
+
+	"github.com/giantswarm/kvm-operator/v4/service/controller/key"
+)
+
+func Test_EnsureCreated(t *testing.T) {
+	testCases := []struct {
+		name               string
+		notAfter           time.Time
+		currentConditions  map[string]string
+		expectedConditions map[string]string
+		expectedReason     string
+		expectedEvents     int
+	}{
+		{
+			name:     "case 0: valid certificates are recorded without events",
+			notAfter: time.Now().Add(365 * 24 * time.Hour),
+			expectedConditions: map[string]string{
+				conditionCertificateExpired:  "False",
+				conditionCertificateExpiring: "False",
+			},
+		},
+		{
+			name:     "case 1: certificates about to expire emit warning events",
+			notAfter: time.Now().Add(24 * time.Hour),
+			expectedConditions: map[string]string{
+				conditionCertificateExpired:  "False",
+				conditionCertificateExpiring: "True",
+			},
+			expectedReason: eventReasonCertificateExpiring,
+			expectedEvents: len(clusterCerts()),
+		},
+		{
+			name:     "case 2: expired certificates emit warning events",
+			notAfter: time.Now().Add(-time.Hour),
+			expectedConditions: map[string]string{
+				conditionCertificateExpired:  "True",
+				conditionCertificateExpiring: "False",
+			},
+			expectedReason: eventReasonCertificateExpired,
+			expectedEvents: len(clusterCerts()),
+		},
+		{
+			name:     "case 3: certificates still about to expire emit no events",
+			notAfter: time.Now().Add(24 * time.Hour),
+			currentConditions: map[string]string{
+				conditionCertificateExpired:  "False",
+				conditionCertificateExpiring: "True",
+			},
+			expectedConditions: map[string]string{
+				conditionCertificateExpired:  "False",
+				conditionCertificateExpiring: "True",
+			},
+		},
+		{
+			name:     "case 4: certificates still expired emit no events",
+			notAfter: time.Now().Add(-time.Hour),
+			currentConditions: map[string]string{
+				conditionCertificateExpired:  "True",
+				conditionCertificateExpiring: "False",
+			},
+			expectedConditions: map[string]string{
+				conditionCertificateExpired:  "True",
+				conditionCertificateExpiring: "False",
+			},
+		},
+		{
+			name:     "case 5: expiring certificates which expired emit warning events",
+			notAfter: time.Now().Add(-time.Hour),
+			currentConditions: map[string]string{
+				conditionCertificateExpired:  "False",
+				conditionCertificateExpiring: "True",
+			},
+			expectedConditions: map[string]string{
+				conditionCertificateExpired:  "True",
+				conditionCertificateExpiring: "False",
+			},
+			expectedReason: eventReasonCertificateExpired,
+			expectedEvents: len(clusterCerts()),
+		},
+		{
+			name:     "case 6: renewed certificates emit a single normal event",
+			notAfter: time.Now().Add(365 * 24 * time.Hour),
+			currentConditions: map[string]string{
+				conditionCertificateExpired:  "True",
+				conditionCertificateExpiring: "False",
+			},
+			expectedConditions: map[string]string{
+				conditionCertificateExpired:  "False",
+				conditionCertificateExpiring: "False",
+			},
+			expectedReason: eventReasonCertificatesValid,
+			expectedEvents: 1,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			cr := &v1alpha1.KVMConfig{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "al9qy",
+					Namespace: "default",
+				},
+				Spec: v1alpha1.KVMConfigSpec{
+					Cluster: v1alpha1.Cluster{
+						ID: "al9qy",
+					},
+				},
+			}
+			if tc.currentConditions != nil {
+				var conditions []v1alpha1.StatusClusterResourceCondition
+				for conditionType, status := range tc.currentConditions {
+					conditions = append(conditions, v1alpha1.StatusClusterResourceCondition{
+						Status: status,
+						Type:   conditionType,
+					})
+				}
+				cr.Status.Cluster.Resources = []v1alpha1.StatusClusterResource{
+					{
+						Conditions: conditions,
+						Name:       Name,
+					},
+				}
+			}
+
+			recorder := record.NewFakeRecorder(10)
+
+			var r *Resource
+			{
+				c := Config{
+					CertsSearcher: certstest.NewSearcher(certstest.Config{
+						TLS: certs.TLS{
+							Crt: testCertificate(t, tc.notAfter),
+						},
+					}),
+					EventRecorder: recorder,
+					G8sClient:     fake.NewSimpleClientset(cr),
+					Logger:        microloggertest.New(),
+				}
+
+				var err error
+				r, err = New(c)
+				if err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			err := r.EnsureCreated(context.Background(), cr)
+			if err != nil {
+				t.Fatalf("error == %#v, want nil", err)
+			}
+
+			kvmConfig, err := r.g8sClient.ProviderV1alpha1().KVMConfigs(cr.GetNamespace()).Get(context.Background(), cr.GetName(), metav1.GetOptions{})
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			var expiries map[string]string
+			key.StatusAnnotation(*kvmConfig, key.AnnotationCertificateExpiry, &expiries)
+			if len(expiries) != len(clusterCerts()) {
+				t.Fatalf("expected %d expiries got %d", len(clusterCerts()), len(expiries))
+			}
+			for c, expiry := range expiries {
+				if expiry != tc.notAfter.UTC().Format(time.RFC3339) {
+					t.Fatalf("expected certificate %#q expiry %s got %s", c, tc.notAfter.UTC().Format(time.RFC3339), expiry)
+				}
+			}
+
+			conditions := key.ResourceStatusConditions(*kvmConfig, Name)
+			if len(conditions) != len(tc.expectedConditions) {
+				t.Fatalf("expected %d conditions got %d", len(tc.expectedConditions), len(conditions))
+			}
+			for _, c := range conditions {
+				if c.Status != tc.expectedConditions[c.Type] {
+					t.Fatalf("expected condition %#q status %#q got %#q", c.Type, tc.expectedConditions[c.Type], c.Status)
+				}
+			}
+
+			var events []string
+			close(recorder.Events)
+			for e := range recorder.Events {
+				events = append(events, e)
+			}
+			if len(events) != tc.expectedEvents {
+				t.Fatalf("expected %d events got %#v", tc.expectedEvents, events)
+			}
+			for _, e := range events {
+				if !strings.Contains(e, tc.expectedReason) {
+					t.Fatalf("expected event with reason %#q got %#q", tc.expectedReason, e)
+				}
+			}
+		})
+	}
+}
+
+func testCertificate(t *testing.T, notAfter time.Time) []byte {
+	privateKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject: pkix.Name{
+			CommonName: "api.al9qy.k8s.example.com",
+		},
+		NotBefore: notAfter.Add(-2 * 365 * 24 * time.Hour),
+		NotAfter:  notAfter,
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &privateKey.PublicKey, privateKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})
+}
@@ -0,0 +1,48 @@
+package configmap
+
+import (
+	"context"
+
+	"github.com/giantswarm/apiextensions/v3/pkg/apis/provider/v1alpha1"
+	"github.com/giantswarm/microerror"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/giantswarm/kvm-operator/v4/service/controller/cloudconfig"
+	"github.com/giantswarm/kvm-operator/v4/service/controller/key"
+)
+
+const (
+	auditKeyEndpoint = "endpoint"
+	auditKeyPolicy   = "policy"
+)
+
+// getClusterAuditConfig looks up the API server audit configuration referenced
+// by the given KVMConfig. In case the KVMConfig does not reference any
+// ConfigMap nil is returned, which means the installation wide audit
+// configuration is used. Validation happens when rendering the master
+// templates, since only then the installation wide configuration is merged.
+func (r *Resource) getClusterAuditConfig(ctx context.Context, cr v1alpha1.KVMConfig) (*cloudconfig.AuditConfig, error) {
+	name := key.AuditConfigMapName(cr)
+	if name == "" {
+		return nil, nil
+	}
+
+	r.logger.Debugf(ctx, "finding cluster specific audit config map %#q", name)
+
+	configMap, err := r.k8sClient.CoreV1().ConfigMaps(cr.GetNamespace()).Get(ctx, name, metav1.GetOptions{})
+	if apierrors.IsNotFound(err) {
+		return nil, microerror.Maskf(notFoundError, "audit config map %#q not found in namespace %#q", name, cr.GetNamespace())
+	} else if err != nil {
+		return nil, microerror.Mask(err)
+	}
+
+	audit := &cloudconfig.AuditConfig{
+		Endpoint: configMap.Data[auditKeyEndpoint],
+		Policy:   configMap.Data[auditKeyPolicy],
+	}
+
+	r.logger.Debugf(ctx, "found cluster specific audit config map %#q", name)
+
+	return audit, nil
+}
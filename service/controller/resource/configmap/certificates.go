@@ -0,0 +1,39 @@
+package configmap
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/giantswarm/apiextensions/v3/pkg/apis/provider/v1alpha1"
+	"github.com/giantswarm/certs/v3/pkg/certs"
+	"github.com/giantswarm/microerror"
+
+	"github.com/giantswarm/kvm-operator/v4/service/controller/key"
+)
+
+// getCertificateIdentities returns the serial number and expiry of each of the
+// given certificates of the cluster. They are part of the ignition settings, so
+// that nodes get rolled once their certificates have been rotated.
+func (r *Resource) getCertificateIdentities(ctx context.Context, cr v1alpha1.KVMConfig, list []certs.Cert) ([]string, error) {
+	var identities []string
+
+	for _, c := range list {
+		tls, err := r.certsSearcher.SearchTLS(ctx, key.ClusterID(cr), c)
+		if err != nil {
+			return nil, microerror.Mask(err)
+		}
+
+		if len(tls.Crt) == 0 {
+			continue
+		}
+
+		certificate, err := key.ParseCertificate(tls.Crt)
+		if err != nil {
+			return nil, microerror.Mask(err)
+		}
+
+		identities = append(identities, fmt.Sprintf("%s:%s:%d", c, certificate.SerialNumber.Text(16), certificate.NotAfter.Unix()))
+	}
+
+	return identities, nil
+}
@@ -0,0 +1,88 @@
+package configmap
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"reflect"
+
+	"github.com/giantswarm/microerror"
+
+	"github.com/giantswarm/kvm-operator/v4/service/controller/cloudconfig"
+	"github.com/giantswarm/kvm-operator/v4/service/controller/key"
+)
+
+// ignitionSettings holds the cluster specific settings rendered into the
+// ignition of a node. Its checksum is annotated on the config maps so that the
+// deployment resource is able to detect ignition drift and roll the affected
+// nodes. Installation wide settings are not considered here, since changing
+// them requires an operator release which rolls all nodes anyway.
+type ignitionSettings struct {
+	Audit *cloudconfig.AuditConfig `json:"audit,omitempty"`
+	// Certificates identifies the certificates rendered into the ignition by
+	// their serial number and expiry.
+	Certificates []string                  `json:"certificates,omitempty"`
+	Egress       *cloudconfig.EgressConfig `json:"egress,omitempty"`
+	// EncryptionKeys is the fingerprint of the rotated API server encryption
+	// keys, so that the keys themselves do not end up in the checksum input.
+	EncryptionKeys string                  `json:"encryptionKeys,omitempty"`
+	OIDC           *cloudconfig.OIDCConfig `json:"oidc,omitempty"`
+}
+
+func masterIgnitionSettings(data cloudconfig.IgnitionTemplateData, certificates []string) ignitionSettings {
+	return ignitionSettings{
+		Audit:          data.Audit,
+		Certificates:   certificates,
+		Egress:         data.Egress,
+		EncryptionKeys: key.EncryptionKeysFingerprint(data.EncryptionKeys),
+		OIDC:           data.OIDC,
+	}
+}
+
+func workerIgnitionSettings(data cloudconfig.IgnitionTemplateData, certificates []string) ignitionSettings {
+	return ignitionSettings{
+		Certificates: certificates,
+		Egress:       data.Egress,
+	}
+}
+
+// annotations returns the annotations of the config maps rendered with the
+// settings. Next to the checksum the encryption keys fingerprint is exposed,
+// so that the progress of key rotations can be tracked on the deployments.
+func (s ignitionSettings) annotations() (map[string]string, error) {
+	checksum, err := s.checksum()
+	if err != nil {
+		return nil, microerror.Mask(err)
+	}
+
+	if checksum == "" {
+		return nil, nil
+	}
+
+	annotations := map[string]string{
+		key.AnnotationIgnitionChecksum: checksum,
+	}
+	if s.EncryptionKeys != "" {
+		annotations[key.AnnotationEncryptionKeys] = s.EncryptionKeys
+	}
+
+	return annotations, nil
+}
+
+// checksum returns the hex encoded SHA256 sum of the JSON representation of
+// the settings. An empty string is returned for empty settings so that nodes
+// without cluster specific settings do not get annotated at all.
+func (s ignitionSettings) checksum() (string, error) {
+	if reflect.DeepEqual(s, ignitionSettings{}) {
+		return "", nil
+	}
+
+	b, err := json.Marshal(s)
+	if err != nil {
+		return "", microerror.Mask(err)
+	}
+
+	sum := sha256.Sum256(b)
+
+	return hex.EncodeToString(sum[:]), nil
+}
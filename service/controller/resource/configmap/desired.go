@@ -61,7 +61,32 @@ func (r *Resource) newConfigMaps(ctx context.Context, customResource v1alpha1.KV
 	defaultVersions := key.DefaultVersions()
 	versions.KubernetesAPIHealthz = defaultVersions.KubernetesAPIHealthz
 	versions.KubernetesNetworkSetupDocker = defaultVersions.KubernetesNetworkSetupDocker
-	images := k8scloudconfig.BuildImages(r.registryDomain, versions)
+
+	audit, err := r.getClusterAuditConfig(ctx, customResource)
+	if err != nil {
+		return nil, microerror.Mask(err)
+	}
+
+	egress, err := r.getClusterEgressConfig(ctx, customResource)
+	if err != nil {
+		return nil, microerror.Mask(err)
+	}
+
+	registryDomain := r.registryDomain
+	if egress != nil && egress.RegistryDomain != "" {
+		registryDomain = egress.RegistryDomain
+	}
+	images := k8scloudconfig.BuildImages(registryDomain, versions)
+
+	encryptionKeys, err := r.getEncryptionKeys(ctx, customResource)
+	if err != nil {
+		return nil, microerror.Mask(err)
+	}
+
+	oidc, err := r.getClusterOIDCConfig(ctx, customResource)
+	if err != nil {
+		return nil, microerror.Mask(err)
+	}
 
 	data := cloudconfig.IgnitionTemplateData{
 		CustomObject:  customResource,
@@ -69,6 +94,29 @@ func (r *Resource) newConfigMaps(ctx context.Context, customResource v1alpha1.KV
 		ClusterKeys:   keys,
 		Images:        images,
 		Versions:      versions,
+
+		Audit:          audit,
+		Egress:         egress,
+		EncryptionKeys: encryptionKeys,
+		OIDC:           oidc,
+	}
+
+	masterCertificates, err := r.getCertificateIdentities(ctx, customResource, cloudconfig.MasterCerts())
+	if err != nil {
+		return nil, microerror.Mask(err)
+	}
+	workerCertificates, err := r.getCertificateIdentities(ctx, customResource, cloudconfig.WorkerCerts())
+	if err != nil {
+		return nil, microerror.Mask(err)
+	}
+
+	masterAnnotations, err := masterIgnitionSettings(data, masterCertificates).annotations()
+	if err != nil {
+		return nil, microerror.Mask(err)
+	}
+	workerAnnotations, err := workerIgnitionSettings(data, workerCertificates).annotations()
+	if err != nil {
+		return nil, microerror.Mask(err)
 	}
 
 	for _, node := range customResource.Spec.Cluster.Masters {
@@ -82,7 +130,7 @@ func (r *Resource) newConfigMaps(ctx context.Context, customResource v1alpha1.KV
 			return nil, microerror.Mask(err)
 		}
 
-		configMap, err := r.newConfigMap(customResource, template, node, key.MasterID)
+		configMap, err := r.newConfigMap(customResource, template, masterAnnotations, node, key.MasterID)
 		if err != nil {
 			return nil, microerror.Mask(err)
 		}
@@ -101,7 +149,7 @@ func (r *Resource) newConfigMaps(ctx context.Context, customResource v1alpha1.KV
 			return nil, microerror.Mask(err)
 		}
 
-		configMap, err := r.newConfigMap(customResource, template, node, key.WorkerID)
+		configMap, err := r.newConfigMap(customResource, template, workerAnnotations, node, key.WorkerID)
 		if err != nil {
 			return nil, microerror.Mask(err)
 		}
@@ -115,14 +163,16 @@ func (r *Resource) newConfigMaps(ctx context.Context, customResource v1alpha1.KV
 // newConfigMap creates a new Kubernetes configmap using the provided
 // information. customResource is used for name and label creation. params
 // serves as structure being injected into the template execution to interpolate
-// variables. prefix can be either "master" or "worker" and is used to prefix
-// the configmap name.
-func (r *Resource) newConfigMap(customResource v1alpha1.KVMConfig, template string, node v1alpha1.ClusterNode, prefix string) (*corev1.ConfigMap, error) {
+// variables. annotations identify the cluster specific settings rendered into
+// the template. prefix can be either "master" or "worker" and is used to
+// prefix the configmap name.
+func (r *Resource) newConfigMap(customResource v1alpha1.KVMConfig, template string, annotations map[string]string, node v1alpha1.ClusterNode, prefix string) (*corev1.ConfigMap, error) {
 	var newConfigMap *corev1.ConfigMap
 	{
 		newConfigMap = &corev1.ConfigMap{
 			ObjectMeta: metav1.ObjectMeta{
-				Name: key.ConfigMapName(customResource, node, prefix),
+				Name:        key.ConfigMapName(customResource, node, prefix),
+				Annotations: annotations,
 				Labels: map[string]string{
 					// TODO: Delete two legacy labels from next release
 					// issues: https://github.com/giantswarm/giantswarm/issues/7771
@@ -0,0 +1,92 @@
+package configmap
+
+import (
+	"context"
+	"net/url"
+	"strings"
+
+	"github.com/giantswarm/apiextensions/v3/pkg/apis/provider/v1alpha1"
+	"github.com/giantswarm/microerror"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/giantswarm/kvm-operator/v4/service/controller/cloudconfig"
+	"github.com/giantswarm/kvm-operator/v4/service/controller/key"
+)
+
+const (
+	egressKeyDockerhubToken  = "dockerhubToken"
+	egressKeyHTTPProxy       = "httpProxy"
+	egressKeyHTTPSProxy      = "httpsProxy"
+	egressKeyNoProxy         = "noProxy"
+	egressKeyRegistryDomain  = "registryDomain"
+	egressKeyRegistryMirrors = "registryMirrors"
+)
+
+// getClusterEgressConfig looks up the proxy and registry configuration
+// referenced by the given KVMConfig. In case the KVMConfig does not reference
+// any Secret nil is returned, which means the installation wide configuration
+// is used. noProxy and registryMirrors are comma separated lists.
+func (r *Resource) getClusterEgressConfig(ctx context.Context, cr v1alpha1.KVMConfig) (*cloudconfig.EgressConfig, error) {
+	name := key.EgressConfigSecretName(cr)
+	if name == "" {
+		return nil, nil
+	}
+
+	r.logger.Debugf(ctx, "finding cluster specific egress config secret %#q", name)
+
+	secret, err := r.k8sClient.CoreV1().Secrets(cr.GetNamespace()).Get(ctx, name, metav1.GetOptions{})
+	if apierrors.IsNotFound(err) {
+		return nil, microerror.Maskf(notFoundError, "egress config secret %#q not found in namespace %#q", name, cr.GetNamespace())
+	} else if err != nil {
+		return nil, microerror.Mask(err)
+	}
+
+	egress := &cloudconfig.EgressConfig{
+		DockerhubToken:  string(secret.Data[egressKeyDockerhubToken]),
+		RegistryDomain:  string(secret.Data[egressKeyRegistryDomain]),
+		RegistryMirrors: splitList(string(secret.Data[egressKeyRegistryMirrors])),
+	}
+
+	{
+		proxy := cloudconfig.ProxyConfig{
+			HTTP:    string(secret.Data[egressKeyHTTPProxy]),
+			HTTPS:   string(secret.Data[egressKeyHTTPSProxy]),
+			NoProxy: splitList(string(secret.Data[egressKeyNoProxy])),
+		}
+
+		for k, v := range map[string]string{egressKeyHTTPProxy: proxy.HTTP, egressKeyHTTPSProxy: proxy.HTTPS} {
+			if v == "" {
+				continue
+			}
+			u, err := url.Parse(v)
+			if err != nil || u.Host == "" {
+				return nil, microerror.Maskf(invalidConfigError, "egress config secret %#q must define a URL for %#q", name, k)
+			}
+		}
+
+		if proxy.HTTP != "" || proxy.HTTPS != "" || len(proxy.NoProxy) > 0 {
+			egress.Proxy = &proxy
+		}
+	}
+
+	if strings.Contains(egress.RegistryDomain, "/") {
+		return nil, microerror.Maskf(invalidConfigError, "egress config secret %#q must define a domain for %#q", name, egressKeyRegistryDomain)
+	}
+
+	r.logger.Debugf(ctx, "found cluster specific egress config secret %#q", name)
+
+	return egress, nil
+}
+
+func splitList(s string) []string {
+	var list []string
+	for _, item := range strings.Split(s, ",") {
+		item = strings.TrimSpace(item)
+		if item != "" {
+			list = append(list, item)
+		}
+	}
+
+	return list
+}
@@ -0,0 +1,26 @@
+package configmap
+
+import (
+	"context"
+
+	"github.com/giantswarm/apiextensions/v3/pkg/apis/provider/v1alpha1"
+	"github.com/giantswarm/microerror"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/giantswarm/kvm-operator/v4/service/controller/key"
+)
+
+// getEncryptionKeys looks up the API server encryption keys of a cluster whose
+// keys have been rotated. In case the keys have never been rotated nil is
+// returned, which means the key managed by randomkeys is used.
+func (r *Resource) getEncryptionKeys(ctx context.Context, cr v1alpha1.KVMConfig) ([]string, error) {
+	secret, err := r.k8sClient.CoreV1().Secrets(key.ClusterNamespace(cr)).Get(ctx, key.EncryptionKeysSecretName(cr), metav1.GetOptions{})
+	if apierrors.IsNotFound(err) {
+		return nil, nil
+	} else if err != nil {
+		return nil, microerror.Mask(err)
+	}
+
+	return key.EncryptionKeys(secret), nil
+}
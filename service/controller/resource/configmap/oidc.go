@@ -0,0 +1,63 @@
+package configmap
+
+import (
+	"context"
+
+	"github.com/giantswarm/apiextensions/v3/pkg/apis/provider/v1alpha1"
+	"github.com/giantswarm/microerror"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/giantswarm/kvm-operator/v4/service/controller/cloudconfig"
+	"github.com/giantswarm/kvm-operator/v4/service/controller/key"
+)
+
+const (
+	oidcKeyCAPEM          = "caPEM"
+	oidcKeyClientID       = "clientID"
+	oidcKeyGroupsClaim    = "groupsClaim"
+	oidcKeyGroupsPrefix   = "groupsPrefix"
+	oidcKeyIssuerURL      = "issuerURL"
+	oidcKeyUsernameClaim  = "usernameClaim"
+	oidcKeyUsernamePrefix = "usernamePrefix"
+)
+
+// getClusterOIDCConfig looks up the OIDC configuration referenced by the given
+// KVMConfig. In case the KVMConfig does not reference any ConfigMap nil is
+// returned, which means the installation wide OIDC configuration is used.
+func (r *Resource) getClusterOIDCConfig(ctx context.Context, cr v1alpha1.KVMConfig) (*cloudconfig.OIDCConfig, error) {
+	name := key.OIDCConfigMapName(cr)
+	if name == "" {
+		return nil, nil
+	}
+
+	r.logger.Debugf(ctx, "finding cluster specific OIDC config map %#q", name)
+
+	configMap, err := r.k8sClient.CoreV1().ConfigMaps(cr.GetNamespace()).Get(ctx, name, metav1.GetOptions{})
+	if apierrors.IsNotFound(err) {
+		return nil, microerror.Maskf(notFoundError, "OIDC config map %#q not found in namespace %#q", name, cr.GetNamespace())
+	} else if err != nil {
+		return nil, microerror.Mask(err)
+	}
+
+	oidc := &cloudconfig.OIDCConfig{
+		CAPEM:          configMap.Data[oidcKeyCAPEM],
+		ClientID:       configMap.Data[oidcKeyClientID],
+		GroupsClaim:    configMap.Data[oidcKeyGroupsClaim],
+		GroupsPrefix:   configMap.Data[oidcKeyGroupsPrefix],
+		IssuerURL:      configMap.Data[oidcKeyIssuerURL],
+		UsernameClaim:  configMap.Data[oidcKeyUsernameClaim],
+		UsernamePrefix: configMap.Data[oidcKeyUsernamePrefix],
+	}
+
+	if oidc.IssuerURL == "" {
+		return nil, microerror.Maskf(invalidConfigError, "OIDC config map %#q must define %#q", name, oidcKeyIssuerURL)
+	}
+	if oidc.ClientID == "" {
+		return nil, microerror.Maskf(invalidConfigError, "OIDC config map %#q must define %#q", name, oidcKeyClientID)
+	}
+
+	r.logger.Debugf(ctx, "found cluster specific OIDC config map %#q", name)
+
+	return oidc, nil
+}
@@ -12,6 +12,7 @@ import (
 	"k8s.io/client-go/kubernetes"
 
 	"github.com/giantswarm/kvm-operator/v4/service/controller/cloudconfig"
+	"github.com/giantswarm/kvm-operator/v4/service/controller/key"
 )
 
 const (
@@ -111,6 +112,11 @@ func equals(a, b *corev1.ConfigMap) bool {
 	if !reflect.DeepEqual(a.Labels, b.Labels) {
 		return false
 	}
+	for _, annotation := range key.IgnitionAnnotations() {
+		if a.Annotations[annotation] != b.Annotations[annotation] {
+			return false
+		}
+	}
 
 	return true
 }
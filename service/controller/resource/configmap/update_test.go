@@ -17,6 +17,7 @@ import (
 
 	"github.com/giantswarm/kvm-operator/v4/pkg/label"
 	"github.com/giantswarm/kvm-operator/v4/service/controller/cloudconfig/cloudconfigtest"
+	"github.com/giantswarm/kvm-operator/v4/service/controller/key"
 )
 
 func Test_Resource_CloudConfig_newUpdateChange(t *testing.T) {
@@ -173,6 +174,64 @@ func Test_Resource_CloudConfig_newUpdateChange(t *testing.T) {
 			},
 			ExpectedMessageContextConfigMapNames: nil,
 		},
+
+		// Test 3, in case the ignition checksum of a config map changed the update
+		// state should contain the modified item even if the data is equal.
+		{
+			Ctx: context.TODO(),
+			Obj: &v1alpha1.KVMConfig{
+				ObjectMeta: metav1.ObjectMeta{
+					Labels: map[string]string{
+						label.ReleaseVersion: "1.0.0",
+					},
+				},
+				Spec: v1alpha1.KVMConfigSpec{
+					Cluster: v1alpha1.Cluster{
+						ID: "al9qy",
+					},
+				},
+			},
+			CurrentState: []*corev1.ConfigMap{
+				{
+					ObjectMeta: metav1.ObjectMeta{
+						Name: "config-map-1",
+						Annotations: map[string]string{
+							key.AnnotationIgnitionChecksum: "old",
+						},
+					},
+					Data: map[string]string{
+						"key1": "val1",
+					},
+				},
+			},
+			DesiredState: []*corev1.ConfigMap{
+				{
+					ObjectMeta: metav1.ObjectMeta{
+						Name: "config-map-1",
+						Annotations: map[string]string{
+							key.AnnotationIgnitionChecksum: "new",
+						},
+					},
+					Data: map[string]string{
+						"key1": "val1",
+					},
+				},
+			},
+			ExpectedConfigMapsToUpdate: []*corev1.ConfigMap{
+				{
+					ObjectMeta: metav1.ObjectMeta{
+						Name: "config-map-1",
+						Annotations: map[string]string{
+							key.AnnotationIgnitionChecksum: "new",
+						},
+					},
+					Data: map[string]string{
+						"key1": "val1",
+					},
+				},
+			},
+			ExpectedMessageContextConfigMapNames: nil,
+		},
 	}
 
 	var err error
@@ -2,15 +2,15 @@ package deployment
 
 import (
 	"context"
-	"fmt"
 
 	"github.com/giantswarm/microerror"
 	v1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
 	apismetav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
+	"github.com/giantswarm/apiextensions/v3/pkg/apis/provider/v1alpha1"
 	releasev1alpha1 "github.com/giantswarm/apiextensions/v3/pkg/apis/release/v1alpha1"
 
-	"github.com/giantswarm/kvm-operator/v4/pkg/label"
 	"github.com/giantswarm/kvm-operator/v4/service/controller/key"
 )
 
@@ -24,9 +24,7 @@ func (r *Resource) GetDesiredState(ctx context.Context, obj interface{}) (interf
 
 	var release *releasev1alpha1.Release
 	{
-		releaseVersion := customResource.Labels[label.ReleaseVersion]
-		releaseName := fmt.Sprintf("v%s", releaseVersion)
-		release, err = r.g8sClient.ReleaseV1alpha1().Releases().Get(ctx, releaseName, apismetav1.GetOptions{})
+		release, err = r.g8sClient.ReleaseV1alpha1().Releases().Get(ctx, key.ReleaseName(customResource), apismetav1.GetOptions{})
 		if err != nil {
 			return nil, microerror.Mask(err)
 		}
@@ -50,7 +48,54 @@ func (r *Resource) GetDesiredState(ctx context.Context, obj interface{}) (interf
 		deployments = append(deployments, workerDeployments...)
 	}
 
+	{
+		err = r.addIgnitionAnnotations(ctx, customResource, deployments)
+		if err != nil {
+			return nil, microerror.Mask(err)
+		}
+	}
+
 	r.logger.Debugf(ctx, "computed the %d new deployments", len(deployments))
 
 	return deployments, nil
 }
+
+// addIgnitionAnnotations copies the ignition annotations of the config map
+// mounted into each deployment onto the deployment and its pod template. This
+// causes the deployment to be rolled once the cluster specific settings
+// rendered into its ignition change.
+func (r *Resource) addIgnitionAnnotations(ctx context.Context, customResource v1alpha1.KVMConfig, deployments []*v1.Deployment) error {
+	var configMaps []corev1.ConfigMap
+	{
+		list, err := r.k8sClient.CoreV1().ConfigMaps(key.ClusterNamespace(customResource)).List(ctx, apismetav1.ListOptions{})
+		if err != nil {
+			return microerror.Mask(err)
+		}
+		configMaps = list.Items
+	}
+
+	annotations := map[string]map[string]string{}
+	for _, c := range configMaps {
+		annotations[c.GetName()] = c.GetAnnotations()
+	}
+
+	for _, d := range deployments {
+		for _, v := range d.Spec.Template.Spec.Volumes {
+			if v.ConfigMap == nil {
+				continue
+			}
+
+			for _, a := range key.IgnitionAnnotations() {
+				value := annotations[v.ConfigMap.Name][a]
+				if value == "" {
+					continue
+				}
+
+				d.Annotations[a] = value
+				d.Spec.Template.Annotations[a] = value
+			}
+		}
+	}
+
+	return nil
+}
@@ -551,3 +551,231 @@ func testGetK8sKVMResourcesPrefix(deployments []*v1.Deployment, prefix string) [
 
 	return rs
 }
+
+func Test_Resource_Deployment_GetDesiredState_persistentDisks(t *testing.T) {
+	obj := &v1alpha1.KVMConfig{
+		ObjectMeta: metav1.ObjectMeta{
+			Annotations: map[string]string{
+				key.AnnotationPersistentWorkerDisks: "b",
+			},
+			Labels: map[string]string{
+				label.ReleaseVersion: "1.0.0",
+			},
+		},
+		Spec: v1alpha1.KVMConfigSpec{
+			Cluster: v1alpha1.Cluster{
+				ID: "al9qy",
+				Masters: []v1alpha1.ClusterNode{
+					{ID: "m"},
+				},
+				Workers: []v1alpha1.ClusterNode{
+					{ID: "a"},
+					{ID: "b"},
+				},
+			},
+			KVM: v1alpha1.KVMConfigSpecKVM{
+				K8sKVM: v1alpha1.KVMConfigSpecKVMK8sKVM{
+					StorageType: "hostPath",
+				},
+				Masters: []v1alpha1.KVMConfigSpecKVMNode{
+					{CPUs: 1, Memory: "1G"},
+				},
+				Workers: []v1alpha1.KVMConfigSpecKVMNode{
+					{CPUs: 4, Memory: "8G"},
+					{CPUs: 4, Memory: "8G"},
+				},
+			},
+		},
+	}
+
+	newResource, err := buildResource()
+	if err != nil {
+		t.Fatalf("expected %#v got %#v", nil, err)
+	}
+
+	result, err := newResource.GetDesiredState(context.TODO(), obj)
+	if err != nil {
+		t.Fatalf("expected %#v got %#v", nil, err)
+	}
+
+	deployments, ok := result.([]*v1.Deployment)
+	if !ok {
+		t.Fatalf("expected %T got %T", []*v1.Deployment{}, result)
+	}
+
+	expectedDevices := map[string]int{
+		key.DeploymentName(key.MasterID, "m"): 0,
+		key.DeploymentName(key.WorkerID, "a"): 0,
+		key.DeploymentName(key.WorkerID, "b"): 2,
+	}
+
+	for _, d := range deployments {
+		var devices int
+		for _, c := range d.Spec.Template.Spec.Containers {
+			if c.Name == key.K8SKVMContainerName {
+				devices = len(c.VolumeDevices)
+			}
+		}
+		if devices != expectedDevices[d.Name] {
+			t.Fatalf("expected deployment %#q to have %d volume devices got %d", d.Name, expectedDevices[d.Name], devices)
+		}
+
+		persistent := d.Annotations[key.AnnotationPersistentDisks] == "true"
+		if persistent != (expectedDevices[d.Name] > 0) {
+			t.Fatalf("expected deployment %#q persistent disks annotation %t got %t", d.Name, expectedDevices[d.Name] > 0, persistent)
+		}
+	}
+}
+
+func Test_Resource_Deployment_GetDesiredState_dataVolumes(t *testing.T) {
+	obj := &v1alpha1.KVMConfig{
+		ObjectMeta: metav1.ObjectMeta{
+			Annotations: map[string]string{
+				key.AnnotationWorkerDataVolumes: `{"b":[{"mountTag":"data","hostPath":"/data","size":"100Gi"}]}`,
+			},
+			Labels: map[string]string{
+				label.ReleaseVersion: "1.0.0",
+			},
+		},
+		Spec: v1alpha1.KVMConfigSpec{
+			Cluster: v1alpha1.Cluster{
+				ID: "al9qy",
+				Masters: []v1alpha1.ClusterNode{
+					{ID: "m"},
+				},
+				Workers: []v1alpha1.ClusterNode{
+					{ID: "a"},
+					{ID: "b"},
+				},
+			},
+			KVM: v1alpha1.KVMConfigSpecKVM{
+				K8sKVM: v1alpha1.KVMConfigSpecKVMK8sKVM{
+					StorageType: "hostPath",
+				},
+				Masters: []v1alpha1.KVMConfigSpecKVMNode{
+					{CPUs: 1, Memory: "1G"},
+				},
+				Workers: []v1alpha1.KVMConfigSpecKVMNode{
+					{CPUs: 4, Memory: "8G"},
+					{CPUs: 4, Memory: "8G"},
+				},
+			},
+		},
+	}
+
+	newResource, err := buildResource()
+	if err != nil {
+		t.Fatalf("expected %#v got %#v", nil, err)
+	}
+
+	result, err := newResource.GetDesiredState(context.TODO(), obj)
+	if err != nil {
+		t.Fatalf("expected %#v got %#v", nil, err)
+	}
+
+	deployments, ok := result.([]*v1.Deployment)
+	if !ok {
+		t.Fatalf("expected %T got %T", []*v1.Deployment{}, result)
+	}
+
+	expectedAnnotations := map[string]string{
+		key.DeploymentName(key.MasterID, "m"): "",
+		key.DeploymentName(key.WorkerID, "a"): "",
+		key.DeploymentName(key.WorkerID, "b"): `[{"hostPath":"/data","mountTag":"data","size":"100Gi"}]`,
+	}
+
+	for _, d := range deployments {
+		if d.Annotations[key.AnnotationDataVolumes] != expectedAnnotations[d.Name] {
+			t.Fatalf("expected deployment %#q data volumes annotation %#q got %#q", d.Name, expectedAnnotations[d.Name], d.Annotations[key.AnnotationDataVolumes])
+		}
+	}
+}
+
+func Test_Resource_Deployment_GetDesiredState_masterDiskSizes(t *testing.T) {
+	testCases := []struct {
+		name               string
+		annotation         string
+		expectedKubelet    string
+		expectedAnnotation string
+	}{
+		{
+			name:               "case 0: master disks keep k8s-kvm defaults",
+			expectedAnnotation: "root=5G",
+		},
+		{
+			name:               "case 1: configured kubelet disk size",
+			annotation:         `{"*":{"kubeletVolumeSizeGB":20}}`,
+			expectedKubelet:    "20G",
+			expectedAnnotation: "root=5G,kubelet=20G",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			obj := &v1alpha1.KVMConfig{
+				ObjectMeta: metav1.ObjectMeta{
+					Annotations: map[string]string{},
+					Labels: map[string]string{
+						label.ReleaseVersion: "1.0.0",
+					},
+				},
+				Spec: v1alpha1.KVMConfigSpec{
+					Cluster: v1alpha1.Cluster{
+						ID: "al9qy",
+						Masters: []v1alpha1.ClusterNode{
+							{ID: "m"},
+						},
+					},
+					KVM: v1alpha1.KVMConfigSpecKVM{
+						K8sKVM: v1alpha1.KVMConfigSpecKVMK8sKVM{
+							StorageType: "hostPath",
+						},
+						Masters: []v1alpha1.KVMConfigSpecKVMNode{
+							{CPUs: 1, Memory: "1G"},
+						},
+					},
+				},
+			}
+			if tc.annotation != "" {
+				obj.Annotations[key.AnnotationNodeDiskSizes] = tc.annotation
+			}
+
+			newResource, err := buildResource()
+			if err != nil {
+				t.Fatalf("expected %#v got %#v", nil, err)
+			}
+
+			result, err := newResource.GetDesiredState(context.TODO(), obj)
+			if err != nil {
+				t.Fatalf("expected %#v got %#v", nil, err)
+			}
+
+			deployments, ok := result.([]*v1.Deployment)
+			if !ok {
+				t.Fatalf("expected %T got %T", []*v1.Deployment{}, result)
+			}
+			if len(deployments) != 1 {
+				t.Fatalf("expected %d deployments got %d", 1, len(deployments))
+			}
+
+			env := map[string]string{}
+			for _, c := range deployments[0].Spec.Template.Spec.Containers {
+				if c.Name == key.K8SKVMContainerName {
+					for _, e := range c.Env {
+						env[e.Name] = e.Value
+					}
+				}
+			}
+
+			if v, ok := env["CONTAINERVMM_GUEST_DOCKER_DISK_SIZE"]; ok {
+				t.Fatalf("expected no docker disk size got %#q", v)
+			}
+			if env["CONTAINERVMM_GUEST_KUBELET_DISK_SIZE"] != tc.expectedKubelet {
+				t.Fatalf("expected kubelet disk size %#q got %#q", tc.expectedKubelet, env["CONTAINERVMM_GUEST_KUBELET_DISK_SIZE"])
+			}
+			if deployments[0].Annotations[key.AnnotationDiskSizes] != tc.expectedAnnotation {
+				t.Fatalf("expected disk sizes %#q got %#q", tc.expectedAnnotation, deployments[0].Annotations[key.AnnotationDiskSizes])
+			}
+		})
+	}
+}
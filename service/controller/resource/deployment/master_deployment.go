@@ -41,6 +41,23 @@ func newMasterDeployments(customResource v1alpha1.KVMConfig, release *releasev1a
 			return nil, microerror.Maskf(invalidConfigError, "error creating memory quantity: %s", err)
 		}
 
+		rootDiskSize, err := key.RootVolumeSize(customResource, masterNode.ID)
+		if err != nil {
+			return nil, microerror.Maskf(invalidConfigError, "error determining root disk size: %s", err)
+		}
+
+		// The docker and kubelet disks of masters keep the defaults of k8s-kvm
+		// unless the kubelet disk size is configured explicitly.
+		kubeletDiskSize, err := key.ConfiguredKubeletVolumeSize(customResource, masterNode.ID)
+		if err != nil {
+			return nil, microerror.Maskf(invalidConfigError, "error determining kubelet disk size: %s", err)
+		}
+
+		diskSizes := fmt.Sprintf("root=%s", rootDiskSize)
+		if kubeletDiskSize != "" {
+			diskSizes = fmt.Sprintf("%s,kubelet=%s", diskSizes, kubeletDiskSize)
+		}
+
 		storageType := key.EtcdStorageType(customResource)
 
 		// During migration, some TPOs do not have storage type set.
@@ -80,6 +97,7 @@ func newMasterDeployments(customResource v1alpha1.KVMConfig, release *releasev1a
 			ObjectMeta: metav1.ObjectMeta{
 				Name: key.DeploymentName(key.MasterID, masterNode.ID),
 				Annotations: map[string]string{
+					key.AnnotationDiskSizes:            diskSizes,
 					key.ReleaseVersionAnnotation:       key.ReleaseVersion(customResource),
 					key.VersionBundleVersionAnnotation: key.OperatorVersion(customResource),
 				},
@@ -209,7 +227,7 @@ func newMasterDeployments(customResource v1alpha1.KVMConfig, release *releasev1a
 									},
 									{
 										Name:  "CONTAINERVMM_GUEST_ROOT_DISK_SIZE",
-										Value: key.DefaultOSDiskSize,
+										Value: rootDiskSize,
 									},
 									{
 										Name:  "CONTAINERVMM_FLATCAR_CHANNEL",
@@ -275,9 +293,30 @@ func newMasterDeployments(customResource v1alpha1.KVMConfig, release *releasev1a
 			},
 		}
 		addCoreComponentsAnnotations(deployment, release)
+		addKubeletDiskSize(deployment, kubeletDiskSize)
 
 		deployments = append(deployments, deployment)
 	}
 
 	return deployments, nil
 }
+
+// addKubeletDiskSize passes the given kubelet disk size into the VM of the
+// given deployment. Nothing is passed for an empty size, so that k8s-kvm uses
+// its default.
+func addKubeletDiskSize(deployment *v1.Deployment, size string) {
+	if size == "" {
+		return
+	}
+
+	for i, container := range deployment.Spec.Template.Spec.Containers {
+		if container.Name == key.K8SKVMContainerName {
+			container.Env = append(container.Env, corev1.EnvVar{
+				Name:  "CONTAINERVMM_GUEST_KUBELET_DISK_SIZE",
+				Value: size,
+			})
+
+			deployment.Spec.Template.Spec.Containers[i] = container
+		}
+	}
+}
@@ -7,12 +7,10 @@ import (
 	"github.com/giantswarm/kvm-operator/v4/service/controller/key"
 )
 
-var coreComponents = []string{"calico", "containerlinux", "etcd", "kubernetes"}
-
 // addCoreComponentsAnnotations adds an annotation for each core component to the pod template spec
 func addCoreComponentsAnnotations(deployment *v1.Deployment, release *releasev1alpha1.Release) {
-	for _, component := range coreComponents {
-		version := componentVersion(release, component)
+	for _, component := range key.CoreComponents {
+		version := key.ReleaseComponentVersion(release, component)
 		if version == "" {
 			// component is not present in the release
 			continue
@@ -21,13 +19,3 @@ func addCoreComponentsAnnotations(deployment *v1.Deployment, release *releasev1a
 		deployment.Spec.Template.ObjectMeta.Annotations[annotationName] = version
 	}
 }
-
-func componentVersion(release *releasev1alpha1.Release, component string) string {
-	for _, releaseComponent := range release.Spec.Components {
-		if releaseComponent.Name == component {
-			return releaseComponent.Version
-		}
-	}
-
-	return ""
-}
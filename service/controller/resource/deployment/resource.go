@@ -129,6 +129,30 @@ func isDeploymentModified(a, b *v1.Deployment) bool {
 		return true
 	}
 
+	// Other than the version annotations, the ignition checksum is only set
+	// for nodes having cluster specific ignition settings. It being empty on
+	// both deployments is therefore no modification, while adding, changing or
+	// removing it is.
+	if a.GetAnnotations()[key.AnnotationIgnitionChecksum] != b.GetAnnotations()[key.AnnotationIgnitionChecksum] {
+		return true
+	}
+
+	// Disk sizes are only applied when k8s-kvm creates the VM disks.
+	if a.GetAnnotations()[key.AnnotationDiskSizes] != b.GetAnnotations()[key.AnnotationDiskSizes] {
+		return true
+	}
+
+	// Data volumes are only passed to the VM when k8s-kvm starts it.
+	if a.GetAnnotations()[key.AnnotationDataVolumes] != b.GetAnnotations()[key.AnnotationDataVolumes] {
+		return true
+	}
+
+	// Switching between persistent disks and disks within the rootfs empty dir
+	// requires the VM pod to be recreated.
+	if a.GetAnnotations()[key.AnnotationPersistentDisks] != b.GetAnnotations()[key.AnnotationPersistentDisks] {
+		return true
+	}
+
 	return false
 }
 
@@ -1680,3 +1680,100 @@ func Test_Resource_Deployment_updateDeployments(t *testing.T) {
 		})
 	}
 }
+
+func Test_isDeploymentModified(t *testing.T) {
+	newDeployment := func(annotations map[string]string) *v1.Deployment {
+		d := &v1.Deployment{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: "deployment-1",
+				Annotations: map[string]string{
+					key.ReleaseVersionAnnotation:       "13.0.0",
+					key.VersionBundleVersionAnnotation: "1.2.0",
+				},
+			},
+		}
+		for k, v := range annotations {
+			d.Annotations[k] = v
+		}
+
+		return d
+	}
+
+	withChecksum := func(checksum string) map[string]string {
+		return map[string]string{key.AnnotationIgnitionChecksum: checksum}
+	}
+	withDataVolumes := func(dataVolumes string) map[string]string {
+		return map[string]string{key.AnnotationDataVolumes: dataVolumes}
+	}
+
+	testCases := []struct {
+		name     string
+		desired  *v1.Deployment
+		current  *v1.Deployment
+		expected bool
+	}{
+		{
+			name:     "case 0: no ignition checksum",
+			desired:  newDeployment(nil),
+			current:  newDeployment(nil),
+			expected: false,
+		},
+		{
+			name:     "case 1: equal ignition checksum",
+			desired:  newDeployment(withChecksum("abc")),
+			current:  newDeployment(withChecksum("abc")),
+			expected: false,
+		},
+		{
+			name:     "case 2: ignition checksum added",
+			desired:  newDeployment(withChecksum("abc")),
+			current:  newDeployment(nil),
+			expected: true,
+		},
+		{
+			name:     "case 3: ignition checksum removed",
+			desired:  newDeployment(nil),
+			current:  newDeployment(withChecksum("abc")),
+			expected: true,
+		},
+		{
+			name:     "case 4: ignition checksum changed",
+			desired:  newDeployment(withChecksum("def")),
+			current:  newDeployment(withChecksum("abc")),
+			expected: true,
+		},
+		{
+			name:     "case 5: equal data volumes",
+			desired:  newDeployment(withDataVolumes(`[{"hostPath":"/data","mountTag":"data","size":"100Gi"}]`)),
+			current:  newDeployment(withDataVolumes(`[{"hostPath":"/data","mountTag":"data","size":"100Gi"}]`)),
+			expected: false,
+		},
+		{
+			name:     "case 6: data volume added",
+			desired:  newDeployment(withDataVolumes(`[{"hostPath":"/data","mountTag":"data","size":"100Gi"}]`)),
+			current:  newDeployment(nil),
+			expected: true,
+		},
+		{
+			name:     "case 7: data volume resized",
+			desired:  newDeployment(withDataVolumes(`[{"hostPath":"/data","mountTag":"data","size":"200Gi"}]`)),
+			current:  newDeployment(withDataVolumes(`[{"hostPath":"/data","mountTag":"data","size":"100Gi"}]`)),
+			expected: true,
+		},
+		{
+			name:     "case 8: data volume removed",
+			desired:  newDeployment(nil),
+			current:  newDeployment(withDataVolumes(`[{"hostPath":"/data","mountTag":"data","size":"100Gi"}]`)),
+			expected: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			result := isDeploymentModified(tc.desired, tc.current)
+			if result != tc.expected {
+				t.Fatalf("expected %t got %t", tc.expected, result)
+			}
+		})
+	}
+}
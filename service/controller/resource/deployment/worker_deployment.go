@@ -1,6 +1,7 @@
 package deployment
 
 import (
+	"encoding/json"
 	"fmt"
 
 	"github.com/giantswarm/apiextensions/v3/pkg/apis/provider/v1alpha1"
@@ -41,6 +42,18 @@ func newWorkerDeployments(customResource v1alpha1.KVMConfig, release *releasev1a
 			return nil, microerror.Maskf(invalidConfigError, "error creating memory quantity: %s", err)
 		}
 
+		rootDiskSize, err := key.RootVolumeSize(customResource, workerNode.ID)
+		if err != nil {
+			return nil, microerror.Maskf(invalidConfigError, "error determining root disk size: %s", err)
+		}
+
+		dockerDiskSize := key.DockerVolumeSizeFromNode(capabilities)
+
+		kubeletDiskSize, err := key.KubeletVolumeSize(customResource, workerNode.ID)
+		if err != nil {
+			return nil, microerror.Maskf(invalidConfigError, "error determining kubelet disk size: %s", err)
+		}
+
 		// TODO: https://github.com/giantswarm/giantswarm/issues/17340
 		//       https://github.com/giantswarm/kvm-operator/pull/1208#discussion_r636067919
 		for _, hostVolume := range capabilities.HostVolumes {
@@ -57,6 +70,7 @@ func newWorkerDeployments(customResource v1alpha1.KVMConfig, release *releasev1a
 			ObjectMeta: metav1.ObjectMeta{
 				Name: key.DeploymentName(key.WorkerID, workerNode.ID),
 				Annotations: map[string]string{
+					key.AnnotationDiskSizes:            fmt.Sprintf("root=%s,docker=%s,kubelet=%s", rootDiskSize, dockerDiskSize, kubeletDiskSize),
 					key.ReleaseVersionAnnotation:       key.ReleaseVersion(customResource),
 					key.VersionBundleVersionAnnotation: key.OperatorVersion(customResource),
 				},
@@ -190,7 +204,15 @@ func newWorkerDeployments(customResource v1alpha1.KVMConfig, release *releasev1a
 									},
 									{
 										Name:  "CONTAINERVMM_GUEST_ROOT_DISK_SIZE",
-										Value: key.DefaultOSDiskSize,
+										Value: rootDiskSize,
+									},
+									{
+										Name:  "CONTAINERVMM_GUEST_DOCKER_DISK_SIZE",
+										Value: dockerDiskSize,
+									},
+									{
+										Name:  "CONTAINERVMM_GUEST_KUBELET_DISK_SIZE",
+										Value: kubeletDiskSize,
 									},
 									{
 										Name:  "CONTAINERVMM_FLATCAR_CHANNEL",
@@ -248,7 +270,11 @@ func newWorkerDeployments(customResource v1alpha1.KVMConfig, release *releasev1a
 			},
 		}
 		addCoreComponentsAnnotations(deployment, release)
-		addHostVolumes(deployment, customResource, i)
+		err = addHostVolumes(deployment, customResource, i)
+		if err != nil {
+			return nil, microerror.Mask(err)
+		}
+		addPersistentDisks(deployment, customResource, workerNode.ID)
 
 		deployments = append(deployments, deployment)
 	}
@@ -256,19 +282,31 @@ func newWorkerDeployments(customResource v1alpha1.KVMConfig, release *releasev1a
 	return deployments, nil
 }
 
-func addHostVolumes(deployment *v1.Deployment, customObject v1alpha1.KVMConfig, workerIndex int) {
+// addHostVolumes passes the host volumes and the data volumes of the given
+// worker into its VM.
+func addHostVolumes(deployment *v1.Deployment, customObject v1alpha1.KVMConfig, workerIndex int) error {
 	caps := customObject.Spec.KVM.Workers[workerIndex]
+	nodeID := customObject.Spec.Cluster.Workers[workerIndex].ID
 
-	if len(caps.HostVolumes) == 0 {
-		return
+	dataVolumes, err := key.WorkerDataVolumes(customObject, nodeID)
+	if err != nil {
+		return microerror.Mask(err)
+	}
+
+	var hostVolumes []v1alpha1.KVMConfigSpecKVMNodeHostVolumes
+	hostVolumes = append(hostVolumes, caps.HostVolumes...)
+	hostVolumes = append(hostVolumes, key.WorkerDataVolumesToHostVolumes(dataVolumes)...)
+
+	if len(hostVolumes) == 0 {
+		return nil
 	}
 
 	for i, container := range deployment.Spec.Template.Spec.Containers {
 		if container.Name == key.K8SKVMContainerName {
-			envVars := []corev1.EnvVar{key.HostVolumesToEnvVar(caps.HostVolumes)}
+			envVars := []corev1.EnvVar{key.HostVolumesToEnvVar(hostVolumes)}
 			container.Env = append(container.Env, envVars...)
 
-			volumeMounts := key.HostVolumesToVolumeMounts(caps.HostVolumes)
+			volumeMounts := key.HostVolumesToVolumeMounts(hostVolumes)
 			container.VolumeMounts = append(container.VolumeMounts, volumeMounts...)
 
 			deployment.Spec.Template.Spec.Containers[i] = container
@@ -276,4 +314,60 @@ func addHostVolumes(deployment *v1.Deployment, customObject v1alpha1.KVMConfig,
 	}
 
 	deployment.Spec.Template.Spec.Volumes = append(deployment.Spec.Template.Spec.Volumes, key.HostVolumesToVolumes(customObject, workerIndex)...)
+	deployment.Spec.Template.Spec.Volumes = append(deployment.Spec.Template.Spec.Volumes, key.WorkerDataVolumesToVolumes(customObject, nodeID, dataVolumes)...)
+
+	if len(dataVolumes) > 0 {
+		b, err := json.Marshal(dataVolumes)
+		if err != nil {
+			return microerror.Mask(err)
+		}
+		deployment.Annotations[key.AnnotationDataVolumes] = string(b)
+	}
+
+	return nil
+}
+
+// addPersistentDisks backs the docker and kubelet disks of the given worker
+// with the block devices of its persistent volume claims, so that images and
+// kubelet state survive VM restarts. Without persistent disks k8s-kvm creates
+// the disks within the rootfs empty dir.
+func addPersistentDisks(deployment *v1.Deployment, customObject v1alpha1.KVMConfig, nodeID string) {
+	if !key.HasPersistentDisks(customObject, nodeID) {
+		return
+	}
+
+	disks := map[string]string{
+		key.PersistentDiskDocker:  "CONTAINERVMM_GUEST_DOCKER_DISK_DEVICE",
+		key.PersistentDiskKubelet: "CONTAINERVMM_GUEST_KUBELET_DISK_DEVICE",
+	}
+
+	for _, disk := range []string{key.PersistentDiskDocker, key.PersistentDiskKubelet} {
+		devicePath := fmt.Sprintf("/dev/containervmm/%s", disk)
+
+		for i, container := range deployment.Spec.Template.Spec.Containers {
+			if container.Name == key.K8SKVMContainerName {
+				container.Env = append(container.Env, corev1.EnvVar{
+					Name:  disks[disk],
+					Value: devicePath,
+				})
+				container.VolumeDevices = append(container.VolumeDevices, corev1.VolumeDevice{
+					Name:       disk,
+					DevicePath: devicePath,
+				})
+
+				deployment.Spec.Template.Spec.Containers[i] = container
+			}
+		}
+
+		deployment.Spec.Template.Spec.Volumes = append(deployment.Spec.Template.Spec.Volumes, corev1.Volume{
+			Name: disk,
+			VolumeSource: corev1.VolumeSource{
+				PersistentVolumeClaim: &corev1.PersistentVolumeClaimVolumeSource{
+					ClaimName: key.PersistentDiskPVCName(key.ClusterID(customObject), nodeID, disk),
+				},
+			},
+		})
+	}
+
+	deployment.Annotations[key.AnnotationPersistentDisks] = "true"
 }
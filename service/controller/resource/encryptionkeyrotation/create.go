@@ -0,0 +1,423 @@
+package encryptionkeyrotation
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/base64"
+
+	"github.com/giantswarm/apiextensions/v3/pkg/apis/provider/v1alpha1"
+	workloaderrors "github.com/giantswarm/errors/tenant"
+	"github.com/giantswarm/k8sclient/v5/pkg/k8sclient"
+	"github.com/giantswarm/microerror"
+	"github.com/giantswarm/operatorkit/v5/pkg/controller/context/reconciliationcanceledcontext"
+	workloadcluster "github.com/giantswarm/tenantcluster/v4/pkg/tenantcluster"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/client-go/kubernetes"
+
+	"github.com/giantswarm/kvm-operator/v4/pkg/label"
+	"github.com/giantswarm/kvm-operator/v4/pkg/project"
+	"github.com/giantswarm/kvm-operator/v4/service/controller/key"
+)
+
+const (
+	// PhaseAddingKey means the masters are rolled with an encryption config
+	// holding the new key for decryption only, so that all masters are able to
+	// read secrets encrypted with the new key before any master writes them.
+	PhaseAddingKey = "AddingKey"
+	// PhasePromotingKey means the masters are rolled with an encryption config
+	// holding the new key for encryption and the old key for decryption.
+	PhasePromotingKey = "PromotingKey"
+	// PhaseRewritingSecrets means all secrets of the workload cluster are
+	// rewritten so that they get encrypted with the new key.
+	PhaseRewritingSecrets = "RewritingSecrets"
+	// PhaseRemovingKey means the masters are rolled with an encryption config
+	// only holding the new key.
+	PhaseRemovingKey = "RemovingKey"
+	// PhaseCompleted means the rotation is completed.
+	PhaseCompleted = "Completed"
+)
+
+const (
+	// encryptionKeyLength is the length in bytes of the generated AES-CBC keys.
+	encryptionKeyLength = 32
+	// secretListLimit is the number of workload cluster secrets listed at once
+	// when rewriting them.
+	secretListLimit = 500
+)
+
+// EnsureCreated drives the rotation of the API server encryption key. The
+// phase of a rotation is stored in the encryption keys secret together with
+// the keys, so that a failure between two writes never leaves the phase and
+// the keys out of sync. The status of the KVMConfig only mirrors the phase.
+func (r *Resource) EnsureCreated(ctx context.Context, obj interface{}) error {
+	cr, err := key.ToCustomObject(obj)
+	if err != nil {
+		return microerror.Mask(err)
+	}
+
+	rotation := key.EncryptionKeyRotation(cr)
+	if rotation == "" {
+		r.logger.Debugf(ctx, "no encryption key rotation requested")
+		return nil
+	}
+
+	var secret *corev1.Secret
+	{
+		secret, err = r.k8sClient.CoreV1().Secrets(key.ClusterNamespace(cr)).Get(ctx, key.EncryptionKeysSecretName(cr), metav1.GetOptions{})
+		if apierrors.IsNotFound(err) {
+			secret = nil
+		} else if err != nil {
+			return microerror.Mask(err)
+		}
+	}
+
+	phase := secretPhase(secret)
+
+	if secret != nil {
+		err = r.updatePhaseStatus(ctx, cr, phase)
+		if err != nil {
+			return microerror.Mask(err)
+		}
+	}
+
+	// A new rotation is only started once the previous one completed. Changing
+	// the annotation while a rotation is in progress queues the next rotation.
+	if secret == nil || (string(secret.Data[key.EncryptionKeysSecretKeyRotation]) != rotation && !isInProgress(phase)) {
+		err = r.startRotation(ctx, cr, secret, rotation)
+		if err != nil {
+			return microerror.Mask(err)
+		}
+
+		return nil
+	}
+
+	primary := secret.Data[key.EncryptionKeysSecretKeyPrimary]
+	secondary := secret.Data[key.EncryptionKeysSecretKeySecondary]
+
+	switch phase {
+	case PhaseAddingKey:
+		rolled, err := r.mastersRolled(ctx, cr, key.EncryptionKeys(secret))
+		if err != nil {
+			return microerror.Mask(err)
+		}
+		if !rolled {
+			r.logger.Debugf(ctx, "waiting for masters to be rolled with the new encryption key for decryption")
+			return nil
+		}
+
+		secret.Data[key.EncryptionKeysSecretKeyPrimary] = secondary
+		secret.Data[key.EncryptionKeysSecretKeySecondary] = primary
+
+		err = r.updatePhase(ctx, cr, secret, PhasePromotingKey)
+		if err != nil {
+			return microerror.Mask(err)
+		}
+
+	case PhasePromotingKey:
+		rolled, err := r.mastersRolled(ctx, cr, key.EncryptionKeys(secret))
+		if err != nil {
+			return microerror.Mask(err)
+		}
+		if !rolled {
+			r.logger.Debugf(ctx, "waiting for masters to be rolled with the new encryption key for encryption")
+			return nil
+		}
+
+		err = r.updatePhase(ctx, cr, secret, PhaseRewritingSecrets)
+		if err != nil {
+			return microerror.Mask(err)
+		}
+
+	case PhaseRewritingSecrets:
+		rewritten, err := r.rewriteSecrets(ctx, cr)
+		if err != nil {
+			return microerror.Mask(err)
+		}
+		if !rewritten {
+			r.logger.Debugf(ctx, "waiting for workload cluster to rewrite secrets")
+			return nil
+		}
+
+		delete(secret.Data, key.EncryptionKeysSecretKeySecondary)
+
+		err = r.updatePhase(ctx, cr, secret, PhaseRemovingKey)
+		if err != nil {
+			return microerror.Mask(err)
+		}
+
+	case PhaseRemovingKey:
+		rolled, err := r.mastersRolled(ctx, cr, key.EncryptionKeys(secret))
+		if err != nil {
+			return microerror.Mask(err)
+		}
+		if !rolled {
+			r.logger.Debugf(ctx, "waiting for masters to be rolled without the old encryption key")
+			return nil
+		}
+
+		err = r.updatePhase(ctx, cr, secret, PhaseCompleted)
+		if err != nil {
+			return microerror.Mask(err)
+		}
+
+	default:
+		r.logger.Debugf(ctx, "encryption key rotation %#q completed", string(secret.Data[key.EncryptionKeysSecretKeyRotation]))
+	}
+
+	return nil
+}
+
+// mastersRolled checks whether all master deployments of the cluster have been
+// rolled out with an ignition rendering the given encryption keys.
+func (r *Resource) mastersRolled(ctx context.Context, cr v1alpha1.KVMConfig, keys []string) (bool, error) {
+	fingerprint := key.EncryptionKeysFingerprint(keys)
+
+	list, err := r.k8sClient.AppsV1().Deployments(key.ClusterNamespace(cr)).List(ctx, metav1.ListOptions{
+		LabelSelector: labels.SelectorFromSet(map[string]string{
+			key.LabelApp: key.MasterID,
+		}).String(),
+	})
+	if err != nil {
+		return false, microerror.Mask(err)
+	}
+
+	if len(list.Items) == 0 {
+		return false, nil
+	}
+
+	for _, d := range list.Items {
+		if d.GetAnnotations()[key.AnnotationEncryptionKeys] != fingerprint {
+			return false, nil
+		}
+		if d.Status.ObservedGeneration < d.GetGeneration() {
+			return false, nil
+		}
+		if d.Spec.Replicas == nil {
+			return false, nil
+		}
+		if d.Status.UpdatedReplicas != *d.Spec.Replicas || d.Status.ReadyReplicas != *d.Spec.Replicas {
+			return false, nil
+		}
+	}
+
+	return true, nil
+}
+
+// rewriteSecrets updates all secrets of the workload cluster without modifying
+// them, which causes the API server to store them encrypted with the current
+// encryption key. It returns false in case the workload cluster API is not
+// available, e.g. while masters are rolled, so that secrets are rewritten in
+// the next reconciliation loop.
+func (r *Resource) rewriteSecrets(ctx context.Context, cr v1alpha1.KVMConfig) (bool, error) {
+	r.logger.Debugf(ctx, "rewriting secrets in workload cluster")
+
+	var k8sClient kubernetes.Interface
+	{
+		k8sClients, err := key.CreateK8sClientForWorkloadCluster(ctx, cr, r.logger, r.workloadCluster)
+		if workloadcluster.IsTimeout(err) {
+			r.logger.Debugf(ctx, "waiting for certificates timed out")
+			return false, nil
+		} else if workloaderrors.IsAPINotAvailable(err) || k8sclient.IsTimeout(err) {
+			r.logger.Debugf(ctx, "workload cluster is not available")
+			return false, nil
+		} else if err != nil {
+			return false, microerror.Mask(err)
+		}
+
+		k8sClient = k8sClients.K8sClient()
+	}
+
+	count, err := rewriteAllSecrets(ctx, k8sClient)
+	if workloaderrors.IsAPINotAvailable(err) {
+		r.logger.Debugf(ctx, "workload cluster is not available")
+		return false, nil
+	} else if err != nil {
+		return false, microerror.Mask(err)
+	}
+
+	r.logger.Debugf(ctx, "rewrote %d secrets in workload cluster", count)
+
+	return true, nil
+}
+
+// rewriteAllSecrets updates all secrets of the given cluster page by page and
+// returns the number of secrets written. The listing is restarted in case the
+// continue token expired, since rewriting a secret twice is harmless.
+func rewriteAllSecrets(ctx context.Context, k8sClient kubernetes.Interface) (int, error) {
+	var count int
+	options := metav1.ListOptions{
+		Limit: secretListLimit,
+	}
+	for {
+		list, err := k8sClient.CoreV1().Secrets(metav1.NamespaceAll).List(ctx, options)
+		if options.Continue != "" && (apierrors.IsResourceExpired(err) || apierrors.IsGone(err)) {
+			count = 0
+			options.Continue = ""
+			continue
+		} else if err != nil {
+			return 0, microerror.Mask(err)
+		}
+
+		for _, s := range list.Items {
+			s := s
+			_, err = k8sClient.CoreV1().Secrets(s.Namespace).Update(ctx, &s, metav1.UpdateOptions{})
+			if apierrors.IsNotFound(err) || apierrors.IsConflict(err) {
+				// The secret has been deleted or written meanwhile, which
+				// means it is not encrypted with the old key anymore.
+				continue
+			} else if err != nil {
+				return 0, microerror.Mask(err)
+			}
+		}
+
+		count += len(list.Items)
+
+		if list.Continue == "" {
+			break
+		}
+		options.Continue = list.Continue
+	}
+
+	return count, nil
+}
+
+// startRotation generates a new encryption key and stores it in the
+// encryption keys secret as second key next to the key currently in use, so
+// that masters are able to decrypt with the new key before any of them
+// encrypts with it. Retrying a failed start generates another key, which is
+// safe as long as the secret has not been written.
+func (r *Resource) startRotation(ctx context.Context, cr v1alpha1.KVMConfig, secret *corev1.Secret, rotation string) error {
+	r.logger.Debugf(ctx, "starting encryption key rotation %#q", rotation)
+
+	var oldKey string
+	if secret != nil {
+		oldKey = string(secret.Data[key.EncryptionKeysSecretKeyPrimary])
+	} else {
+		keys, err := r.keyWatcher.SearchCluster(ctx, key.ClusterID(cr))
+		if err != nil {
+			return microerror.Mask(err)
+		}
+		oldKey = string(keys.APIServerEncryptionKey)
+	}
+	if oldKey == "" {
+		return microerror.Maskf(notFoundError, "encryption key of cluster %#q", key.ClusterID(cr))
+	}
+
+	newKey, err := newEncryptionKey()
+	if err != nil {
+		return microerror.Mask(err)
+	}
+
+	data := map[string][]byte{
+		key.EncryptionKeysSecretKeyPrimary:   []byte(oldKey),
+		key.EncryptionKeysSecretKeySecondary: []byte(newKey),
+		key.EncryptionKeysSecretKeyRotation:  []byte(rotation),
+	}
+
+	if secret == nil {
+		secret = &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      key.EncryptionKeysSecretName(cr),
+				Namespace: key.ClusterNamespace(cr),
+				Labels: map[string]string{
+					label.Cluster:   key.ClusterID(cr),
+					label.ManagedBy: project.Name(),
+				},
+			},
+			Data: data,
+		}
+	} else {
+		secret.Data = data
+	}
+
+	err = r.updatePhase(ctx, cr, secret, PhaseAddingKey)
+	if err != nil {
+		return microerror.Mask(err)
+	}
+
+	return nil
+}
+
+// updatePhase writes the given encryption keys secret with the given rotation
+// phase, mirrors the phase in the status of the KVMConfig and cancels the
+// reconciliation, so that the following resources operate on the updated
+// encryption keys.
+func (r *Resource) updatePhase(ctx context.Context, cr v1alpha1.KVMConfig, secret *corev1.Secret, phase string) error {
+	r.logger.Debugf(ctx, "updating secret %#q with encryption key rotation phase %#q", secret.Name, phase)
+
+	secret.Data[key.EncryptionKeysSecretKeyPhase] = []byte(phase)
+
+	if secret.ResourceVersion == "" {
+		_, err := r.k8sClient.CoreV1().Secrets(secret.Namespace).Create(ctx, secret, metav1.CreateOptions{})
+		if err != nil {
+			return microerror.Mask(err)
+		}
+	} else {
+		_, err := r.k8sClient.CoreV1().Secrets(secret.Namespace).Update(ctx, secret, metav1.UpdateOptions{})
+		if err != nil {
+			return microerror.Mask(err)
+		}
+	}
+
+	r.logger.Debugf(ctx, "updated secret %#q with encryption key rotation phase %#q", secret.Name, phase)
+
+	err := r.updatePhaseStatus(ctx, cr, phase)
+	if err != nil {
+		return microerror.Mask(err)
+	}
+
+	r.logger.Debugf(ctx, "canceling reconciliation")
+	reconciliationcanceledcontext.SetCanceled(ctx)
+
+	return nil
+}
+
+// updatePhaseStatus records the given rotation phase in the status of the
+// KVMConfig in case it is not recorded yet.
+func (r *Resource) updatePhaseStatus(ctx context.Context, cr v1alpha1.KVMConfig, phase string) error {
+	conditions := []v1alpha1.StatusClusterResourceCondition{
+		{
+			Status: string(corev1.ConditionTrue),
+			Type:   phase,
+		},
+	}
+
+	updated, err := key.UpdateResourceStatus(ctx, r.g8sClient, cr, Name, conditions)
+	if err != nil {
+		return microerror.Mask(err)
+	}
+
+	if updated {
+		r.logger.Debugf(ctx, "updated status with encryption key rotation phase %#q", phase)
+	}
+
+	return nil
+}
+
+// secretPhase returns the rotation phase stored in the given encryption keys
+// secret.
+func secretPhase(secret *corev1.Secret) string {
+	if secret == nil {
+		return ""
+	}
+
+	return string(secret.Data[key.EncryptionKeysSecretKeyPhase])
+}
+
+func isInProgress(phase string) bool {
+	return phase == PhaseAddingKey || phase == PhasePromotingKey || phase == PhaseRewritingSecrets || phase == PhaseRemovingKey
+}
+
+func newEncryptionKey() (string, error) {
+	b := make([]byte, encryptionKeyLength)
+
+	_, err := rand.Read(b)
+	if err != nil {
+		return "", microerror.Mask(err)
+	}
+
+	return base64.StdEncoding.EncodeToString(b), nil
+}
@@ -0,0 +1,329 @@
+package encryptionkeyrotation
+
+import (
+	"context"
+	"testing"
+
+	"github.com/giantswarm/apiextensions/v3/pkg/apis/provider/v1alpha1"
+	apiextfake "github.com/giantswarm/apiextensions/v3/pkg/clientset/versioned/fake"
+	"github.com/giantswarm/certs/v3/pkg/certs"
+	"github.com/giantswarm/certs/v3/pkg/certstest"
+	"github.com/giantswarm/micrologger/microloggertest"
+	"github.com/giantswarm/randomkeys/v2"
+	workloadcluster "github.com/giantswarm/tenantcluster/v4/pkg/tenantcluster"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/fake"
+	k8stesting "k8s.io/client-go/testing"
+
+	"github.com/giantswarm/kvm-operator/v4/service/controller/key"
+)
+
+type testKeyWatcher struct{}
+
+func (w testKeyWatcher) SearchCluster(ctx context.Context, clusterID string) (randomkeys.Cluster, error) {
+	return randomkeys.Cluster{APIServerEncryptionKey: []byte("initial")}, nil
+}
+
+// currentPhase returns the rotation phase mirrored in the status of the given
+// KVMConfig.
+func currentPhase(cr v1alpha1.KVMConfig) string {
+	for _, c := range key.ResourceStatusConditions(cr, Name) {
+		if c.Status == string(corev1.ConditionTrue) {
+			return c.Type
+		}
+	}
+
+	return ""
+}
+
+// generatedKey is expected in place of encryption keys generated during the
+// test.
+const generatedKey = "<generated>"
+
+func Test_EnsureCreated(t *testing.T) {
+	one := int32(1)
+
+	newKVMConfig := func(rotation string, phase string) *v1alpha1.KVMConfig {
+		cr := &v1alpha1.KVMConfig{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "al9qy",
+				Namespace: "default",
+			},
+			Spec: v1alpha1.KVMConfigSpec{
+				Cluster: v1alpha1.Cluster{
+					ID: "al9qy",
+				},
+			},
+		}
+		if rotation != "" {
+			cr.Annotations = map[string]string{
+				key.AnnotationEncryptionKeyRotation: rotation,
+			}
+		}
+		if phase != "" {
+			cr.Status.Cluster.Resources = []v1alpha1.StatusClusterResource{
+				{
+					Name: Name,
+					Conditions: []v1alpha1.StatusClusterResourceCondition{
+						{Status: "True", Type: phase},
+					},
+				},
+			}
+		}
+		return cr
+	}
+
+	newSecret := func(rotation string, phase string, keys ...string) *corev1.Secret {
+		s := &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:            "al9qy-encryption-keys",
+				Namespace:       "al9qy",
+				ResourceVersion: "1",
+			},
+			Data: map[string][]byte{
+				key.EncryptionKeysSecretKeyPhase:    []byte(phase),
+				key.EncryptionKeysSecretKeyPrimary:  []byte(keys[0]),
+				key.EncryptionKeysSecretKeyRotation: []byte(rotation),
+			},
+		}
+		if len(keys) > 1 {
+			s.Data[key.EncryptionKeysSecretKeySecondary] = []byte(keys[1])
+		}
+		return s
+	}
+
+	newMasterDeployment := func(keys ...string) *appsv1.Deployment {
+		return &appsv1.Deployment{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "master-a",
+				Namespace: "al9qy",
+				Annotations: map[string]string{
+					key.AnnotationEncryptionKeys: key.EncryptionKeysFingerprint(keys),
+				},
+				Labels: map[string]string{
+					key.LabelApp: key.MasterID,
+				},
+			},
+			Spec: appsv1.DeploymentSpec{
+				Replicas: &one,
+			},
+			Status: appsv1.DeploymentStatus{
+				ReadyReplicas:   1,
+				UpdatedReplicas: 1,
+			},
+		}
+	}
+
+	testCases := []struct {
+		name                 string
+		inputKVMConfig       *v1alpha1.KVMConfig
+		inputObjects         []runtime.Object
+		expectedPhase        string
+		expectedPrimaryKey   string
+		expectedSecondaryKey string
+	}{
+		{
+			name:           "case 0: no rotation requested",
+			inputKVMConfig: newKVMConfig("", ""),
+			expectedPhase:  "",
+		},
+		{
+			name:                 "case 1: first rotation adds a new key for decryption",
+			inputKVMConfig:       newKVMConfig("1", ""),
+			expectedPhase:        PhaseAddingKey,
+			expectedPrimaryKey:   "initial",
+			expectedSecondaryKey: generatedKey,
+		},
+		{
+			name:                 "case 2: wait for masters to be rolled with the new key for decryption",
+			inputKVMConfig:       newKVMConfig("1", PhaseAddingKey),
+			inputObjects:         []runtime.Object{newSecret("1", PhaseAddingKey, "initial", "new"), newMasterDeployment("initial")},
+			expectedPhase:        PhaseAddingKey,
+			expectedPrimaryKey:   "initial",
+			expectedSecondaryKey: "new",
+		},
+		{
+			name:                 "case 3: promote the new key once masters are rolled with it for decryption",
+			inputKVMConfig:       newKVMConfig("1", PhaseAddingKey),
+			inputObjects:         []runtime.Object{newSecret("1", PhaseAddingKey, "initial", "new"), newMasterDeployment("initial", "new")},
+			expectedPhase:        PhasePromotingKey,
+			expectedPrimaryKey:   "new",
+			expectedSecondaryKey: "initial",
+		},
+		{
+			name:                 "case 4: rewrite secrets once masters are rolled with the new key for encryption",
+			inputKVMConfig:       newKVMConfig("1", PhasePromotingKey),
+			inputObjects:         []runtime.Object{newSecret("1", PhasePromotingKey, "new", "initial"), newMasterDeployment("new", "initial")},
+			expectedPhase:        PhaseRewritingSecrets,
+			expectedPrimaryKey:   "new",
+			expectedSecondaryKey: "initial",
+		},
+		{
+			name:               "case 5: complete once masters are rolled without the old key",
+			inputKVMConfig:     newKVMConfig("1", PhaseRemovingKey),
+			inputObjects:       []runtime.Object{newSecret("1", PhaseRemovingKey, "new"), newMasterDeployment("new")},
+			expectedPhase:      PhaseCompleted,
+			expectedPrimaryKey: "new",
+		},
+		{
+			name:                 "case 6: changed annotation starts the next rotation",
+			inputKVMConfig:       newKVMConfig("2", PhaseCompleted),
+			inputObjects:         []runtime.Object{newSecret("1", PhaseCompleted, "new"), newMasterDeployment("new")},
+			expectedPhase:        PhaseAddingKey,
+			expectedPrimaryKey:   "new",
+			expectedSecondaryKey: generatedKey,
+		},
+		{
+			name:                 "case 7: changed annotation does not interrupt a rotation in progress",
+			inputKVMConfig:       newKVMConfig("2", PhaseAddingKey),
+			inputObjects:         []runtime.Object{newSecret("1", PhaseAddingKey, "initial", "new"), newMasterDeployment("initial")},
+			expectedPhase:        PhaseAddingKey,
+			expectedPrimaryKey:   "initial",
+			expectedSecondaryKey: "new",
+		},
+		{
+			name:                 "case 8: phase of the secret wins over an outdated status",
+			inputKVMConfig:       newKVMConfig("1", PhaseCompleted),
+			inputObjects:         []runtime.Object{newSecret("1", PhaseAddingKey, "initial", "new"), newMasterDeployment("initial", "new")},
+			expectedPhase:        PhasePromotingKey,
+			expectedPrimaryKey:   "new",
+			expectedSecondaryKey: "initial",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			var workloadCluster workloadcluster.Interface
+			{
+				c := workloadcluster.Config{
+					CertsSearcher: certstest.NewSearcher(certstest.Config{}),
+					Logger:        microloggertest.New(),
+					CertID:        certs.APICert,
+				}
+
+				var err error
+				workloadCluster, err = workloadcluster.New(c)
+				if err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			var r *Resource
+			{
+				c := Config{
+					G8sClient:       apiextfake.NewSimpleClientset(tc.inputKVMConfig),
+					K8sClient:       fake.NewSimpleClientset(tc.inputObjects...),
+					KeyWatcher:      testKeyWatcher{},
+					Logger:          microloggertest.New(),
+					WorkloadCluster: workloadCluster,
+				}
+
+				var err error
+				r, err = New(c)
+				if err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			err := r.EnsureCreated(context.Background(), tc.inputKVMConfig)
+			if err != nil {
+				t.Fatalf("error == %#v, want nil", err)
+			}
+
+			kvmConfig, err := r.g8sClient.ProviderV1alpha1().KVMConfigs(tc.inputKVMConfig.GetNamespace()).Get(context.Background(), tc.inputKVMConfig.GetName(), metav1.GetOptions{})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if phase := currentPhase(*kvmConfig); phase != tc.expectedPhase {
+				t.Fatalf("phase == %#q, want %#q", phase, tc.expectedPhase)
+			}
+
+			if tc.expectedPhase == "" {
+				return
+			}
+
+			secret, err := r.k8sClient.CoreV1().Secrets("al9qy").Get(context.Background(), "al9qy-encryption-keys", metav1.GetOptions{})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if phase := string(secret.Data[key.EncryptionKeysSecretKeyPhase]); phase != tc.expectedPhase {
+				t.Fatalf("secret phase == %#q, want %#q", phase, tc.expectedPhase)
+			}
+			for k, expected := range map[string]string{key.EncryptionKeysSecretKeyPrimary: tc.expectedPrimaryKey, key.EncryptionKeysSecretKeySecondary: tc.expectedSecondaryKey} {
+				v := string(secret.Data[k])
+				if expected == generatedKey && (v == "" || v == "initial" || v == "new") {
+					t.Fatalf("%s key == %#q, want generated key", k, v)
+				}
+				if expected != generatedKey && v != expected {
+					t.Fatalf("%s key == %#q, want %#q", k, v, expected)
+				}
+			}
+		})
+	}
+}
+
+func Test_rewriteAllSecrets(t *testing.T) {
+	newSecret := func(name string) corev1.Secret {
+		return corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      name,
+				Namespace: "default",
+			},
+		}
+	}
+
+	a := newSecret("a")
+	b := newSecret("b")
+
+	k8sClient := fake.NewSimpleClientset(&a, &b)
+
+	// The first listing fails on its second page because the continue token
+	// expired. The restarted listing succeeds.
+	pages := []struct {
+		items []corev1.Secret
+		next  string
+		err   error
+	}{
+		{items: []corev1.Secret{a}, next: "1"},
+		{err: apierrors.NewResourceExpired("continue token expired")},
+		{items: []corev1.Secret{a}, next: "2"},
+		{items: []corev1.Secret{b}},
+	}
+
+	var listed int
+	k8sClient.PrependReactor("list", "secrets", func(action k8stesting.Action) (bool, runtime.Object, error) {
+		if listed >= len(pages) {
+			t.Fatalf("expected at most %d list calls", len(pages))
+		}
+		page := pages[listed]
+		listed++
+
+		if page.err != nil {
+			return true, nil, page.err
+		}
+
+		list := &corev1.SecretList{
+			ListMeta: metav1.ListMeta{
+				Continue: page.next,
+			},
+			Items: page.items,
+		}
+
+		return true, list, nil
+	})
+
+	count, err := rewriteAllSecrets(context.Background(), k8sClient)
+	if err != nil {
+		t.Fatalf("error == %#v, want nil", err)
+	}
+	if listed != len(pages) {
+		t.Fatalf("expected %d list calls got %d", len(pages), listed)
+	}
+	if count != 2 {
+		t.Fatalf("expected %d rewritten secrets got %d", 2, count)
+	}
+}
@@ -0,0 +1,11 @@
+package encryptionkeyrotation
+
+import (
+	"context"
+)
+
+// EnsureDeleted is a no-op since the encryption keys secret lives in the
+// cluster namespace, which is deleted together with the cluster.
+func (r *Resource) EnsureDeleted(ctx context.Context, obj interface{}) error {
+	return nil
+}
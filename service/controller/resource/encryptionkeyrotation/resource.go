@@ -0,0 +1,67 @@
+package encryptionkeyrotation
+
+import (
+	"github.com/giantswarm/apiextensions/v3/pkg/clientset/versioned"
+	"github.com/giantswarm/microerror"
+	"github.com/giantswarm/micrologger"
+	"github.com/giantswarm/randomkeys/v2"
+	workloadcluster "github.com/giantswarm/tenantcluster/v4/pkg/tenantcluster"
+	"k8s.io/client-go/kubernetes"
+)
+
+const (
+	Name = "encryptionkeyrotation"
+)
+
+type Config struct {
+	G8sClient       versioned.Interface
+	K8sClient       kubernetes.Interface
+	KeyWatcher      randomkeys.Interface
+	Logger          micrologger.Logger
+	WorkloadCluster workloadcluster.Interface
+}
+
+// Resource rotates the API server encryption key of a workload cluster once
+// requested via the AnnotationEncryptionKeyRotation annotation. The keys are
+// kept in a secret within the cluster namespace, which the configmap resource
+// renders into the master ignition. The progress of a rotation is tracked in
+// the status of the KVMConfig.
+type Resource struct {
+	g8sClient       versioned.Interface
+	k8sClient       kubernetes.Interface
+	keyWatcher      randomkeys.Interface
+	logger          micrologger.Logger
+	workloadCluster workloadcluster.Interface
+}
+
+func New(config Config) (*Resource, error) {
+	if config.G8sClient == nil {
+		return nil, microerror.Maskf(invalidConfigError, "%T.G8sClient must not be empty", config)
+	}
+	if config.K8sClient == nil {
+		return nil, microerror.Maskf(invalidConfigError, "%T.K8sClient must not be empty", config)
+	}
+	if config.KeyWatcher == nil {
+		return nil, microerror.Maskf(invalidConfigError, "%T.KeyWatcher must not be empty", config)
+	}
+	if config.Logger == nil {
+		return nil, microerror.Maskf(invalidConfigError, "%T.Logger must not be empty", config)
+	}
+	if config.WorkloadCluster == nil {
+		return nil, microerror.Maskf(invalidConfigError, "%T.WorkloadCluster must not be empty", config)
+	}
+
+	r := &Resource{
+		g8sClient:       config.G8sClient,
+		k8sClient:       config.K8sClient,
+		keyWatcher:      config.KeyWatcher,
+		logger:          config.Logger,
+		workloadCluster: config.WorkloadCluster,
+	}
+
+	return r, nil
+}
+
+func (r *Resource) Name() string {
+	return Name
+}
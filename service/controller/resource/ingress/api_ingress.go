@@ -13,7 +13,7 @@ func newAPIIngress(customObject v1alpha1.KVMConfig) *networkingv1beta1.Ingress {
 	ingress := &networkingv1beta1.Ingress{
 		TypeMeta: metav1.TypeMeta{
 			Kind:       "Ingress",
-			APIVersion: "networking/v1beta",
+			APIVersion: "networking.k8s.io/v1",
 		},
 		ObjectMeta: metav1.ObjectMeta{
 			Name: APIID,
@@ -22,9 +22,6 @@ func newAPIIngress(customObject v1alpha1.KVMConfig) *networkingv1beta1.Ingress {
 				"customer": key.ClusterCustomer(customObject),
 				"app":      key.MasterID,
 			},
-			Annotations: map[string]string{
-				"nginx.ingress.kubernetes.io/ssl-passthrough": "true",
-			},
 		},
 		Spec: networkingv1beta1.IngressSpec{
 			TLS: []networkingv1beta1.IngressTLS{
@@ -41,7 +38,8 @@ func newAPIIngress(customObject v1alpha1.KVMConfig) *networkingv1beta1.Ingress {
 						HTTP: &networkingv1beta1.HTTPIngressRuleValue{
 							Paths: []networkingv1beta1.HTTPIngressPath{
 								{
-									Path: "/",
+									Path:     "/",
+									PathType: &pathTypeImplementationSpecific,
 									Backend: networkingv1beta1.IngressBackend{
 										ServiceName: key.MasterID,
 										ServicePort: intstr.FromInt(customObject.Spec.Cluster.Kubernetes.API.SecurePort),
@@ -26,7 +26,12 @@ func (r *Resource) ApplyCreateChange(ctx context.Context, obj, createChange inte
 
 		namespace := key.ClusterNamespace(customObject)
 		for _, ingress := range ingressesToCreate {
-			_, err := r.k8sClient.NetworkingV1beta1().Ingresses(namespace).Create(ctx, ingress, v1.CreateOptions{})
+			u, err := toV1Ingress(ingress)
+			if err != nil {
+				return microerror.Mask(err)
+			}
+
+			_, err = r.dynClient.Resource(ingressResource).Namespace(namespace).Create(ctx, u, v1.CreateOptions{})
 			if apierrors.IsAlreadyExists(err) {
 				// fall through
 			} else if err != nil {
@@ -8,6 +8,8 @@ import (
 	"github.com/giantswarm/micrologger/microloggertest"
 	"k8s.io/api/networking/v1beta1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
 	"k8s.io/client-go/kubernetes/fake"
 )
 
@@ -217,6 +219,7 @@ func Test_Resource_Ingress_newCreateChange(t *testing.T) {
 	var newResource *Resource
 	{
 		resourceConfig := DefaultConfig()
+		resourceConfig.DynClient = dynamicfake.NewSimpleDynamicClient(runtime.NewScheme())
 		resourceConfig.K8sClient = fake.NewSimpleClientset()
 		resourceConfig.Logger = microloggertest.New()
 		newResource, err = New(resourceConfig)
@@ -30,7 +30,7 @@ func (r *Resource) GetCurrentState(ctx context.Context, obj interface{}) (interf
 	}
 
 	for _, name := range ingressNames {
-		manifest, err := r.k8sClient.NetworkingV1beta1().Ingresses(namespace).Get(ctx, name, metav1.GetOptions{})
+		u, err := r.dynClient.Resource(ingressResource).Namespace(namespace).Get(ctx, name, metav1.GetOptions{})
 		if apierrors.IsNotFound(err) {
 			r.logger.Debugf(ctx, "did not find a ingress in the Kubernetes API")
 			// fall through
@@ -38,6 +38,11 @@ func (r *Resource) GetCurrentState(ctx context.Context, obj interface{}) (interf
 			return nil, microerror.Mask(err)
 		} else {
 			r.logger.Debugf(ctx, "found a ingress in the Kubernetes API")
+
+			manifest, err := fromV1Ingress(u)
+			if err != nil {
+				return nil, microerror.Mask(err)
+			}
 			ingresses = append(ingresses, manifest)
 		}
 	}
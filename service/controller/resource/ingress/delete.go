@@ -27,7 +27,7 @@ func (r *Resource) ApplyDeleteChange(ctx context.Context, obj, deleteChange inte
 
 		namespace := key.ClusterNamespace(customObject)
 		for _, ingress := range ingressesToDelete {
-			err := r.k8sClient.NetworkingV1beta1().Ingresses(namespace).Delete(ctx, ingress.Name, metav1.DeleteOptions{})
+			err := r.dynClient.Resource(ingressResource).Namespace(namespace).Delete(ctx, ingress.Name, metav1.DeleteOptions{})
 			if apierrors.IsNotFound(err) {
 				// fall through
 			} else if err != nil {
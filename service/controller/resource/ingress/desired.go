@@ -3,6 +3,7 @@ package ingress
 import (
 	"context"
 
+	"github.com/giantswarm/apiextensions/v3/pkg/apis/provider/v1alpha1"
 	"github.com/giantswarm/microerror"
 	"k8s.io/api/networking/v1beta1"
 
@@ -22,7 +23,33 @@ func (r *Resource) GetDesiredState(ctx context.Context, obj interface{}) (interf
 	ingresses = append(ingresses, newAPIIngress(customObject))
 	ingresses = append(ingresses, newEtcdIngress(customObject))
 
+	for _, ingress := range ingresses {
+		err = r.applyIngressClass(customObject, ingress)
+		if err != nil {
+			return nil, microerror.Mask(err)
+		}
+	}
+
 	r.logger.Debugf(ctx, "computed the %d new ingresses", len(ingresses))
 
 	return ingresses, nil
 }
+
+// applyIngressClass sets the ingress class and TLS passthrough annotations
+// configured for the installation or the given cluster on the given ingress.
+func (r *Resource) applyIngressClass(customObject v1alpha1.KVMConfig, ingress *v1beta1.Ingress) error {
+	text := key.IngressPassthroughAnnotations(customObject, r.passthroughAnnotations)
+
+	annotations, err := renderPassthroughAnnotations(text, newPassthroughData(key.ClusterID(customObject), ingress))
+	if err != nil {
+		return microerror.Mask(err)
+	}
+	ingress.Annotations = annotations
+	setManagedAnnotations(ingress)
+
+	if class := key.IngressClass(customObject, r.class); class != "" {
+		ingress.Spec.IngressClassName = &class
+	}
+
+	return nil
+}
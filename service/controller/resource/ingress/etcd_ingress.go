@@ -13,7 +13,7 @@ func newEtcdIngress(customObject v1alpha1.KVMConfig) *v1beta1.Ingress {
 	ingress := &v1beta1.Ingress{
 		TypeMeta: metav1.TypeMeta{
 			Kind:       "Ingress",
-			APIVersion: "networking/v1beta",
+			APIVersion: "networking.k8s.io/v1",
 		},
 		ObjectMeta: metav1.ObjectMeta{
 			Name: EtcdID,
@@ -22,9 +22,6 @@ func newEtcdIngress(customObject v1alpha1.KVMConfig) *v1beta1.Ingress {
 				"customer": key.ClusterCustomer(customObject),
 				"app":      key.MasterID,
 			},
-			Annotations: map[string]string{
-				"nginx.ingress.kubernetes.io/ssl-passthrough": "true",
-			},
 		},
 		Spec: v1beta1.IngressSpec{
 			TLS: []v1beta1.IngressTLS{
@@ -41,7 +38,8 @@ func newEtcdIngress(customObject v1alpha1.KVMConfig) *v1beta1.Ingress {
 						HTTP: &v1beta1.HTTPIngressRuleValue{
 							Paths: []v1beta1.HTTPIngressPath{
 								{
-									Path: "/",
+									Path:     "/",
+									PathType: &pathTypeImplementationSpecific,
 									Backend: v1beta1.IngressBackend{
 										ServiceName: key.MasterID,
 										ServicePort: intstr.FromInt(2379),
@@ -0,0 +1,74 @@
+package ingress
+
+import (
+	"bytes"
+	"strings"
+	"text/template"
+
+	"github.com/giantswarm/microerror"
+	"k8s.io/api/networking/v1beta1"
+	"k8s.io/apimachinery/pkg/util/yaml"
+)
+
+const (
+	defaultPassthroughAnnotations = `nginx.ingress.kubernetes.io/ssl-passthrough: "true"`
+)
+
+// passthroughData is the data the passthrough annotation templates are
+// rendered with.
+type passthroughData struct {
+	ClusterID   string
+	Host        string
+	ServiceName string
+	ServicePort string
+}
+
+// renderPassthroughAnnotations renders the given template into the annotations
+// enabling TLS passthrough on an ingress. The rendered template must be a YAML
+// or JSON object of strings, e.g. `haproxy.org/ssl-passthrough: "true"` for the
+// HAProxy ingress controller.
+func renderPassthroughAnnotations(text string, data passthroughData) (map[string]string, error) {
+	t, err := template.New("passthrough").Option("missingkey=error").Parse(text)
+	if err != nil {
+		return nil, microerror.Maskf(invalidConfigError, "passthrough annotations template must be valid: %s", err)
+	}
+
+	var b bytes.Buffer
+	err = t.Execute(&b, data)
+	if err != nil {
+		return nil, microerror.Maskf(invalidConfigError, "passthrough annotations template must be valid: %s", err)
+	}
+
+	annotations := map[string]string{}
+	if strings.TrimSpace(b.String()) == "" {
+		return annotations, nil
+	}
+
+	err = yaml.NewYAMLOrJSONDecoder(&b, 4096).Decode(&annotations)
+	if err != nil {
+		return nil, microerror.Maskf(invalidConfigError, "passthrough annotations must be a YAML object of strings: %s", err)
+	}
+
+	return annotations, nil
+}
+
+// newPassthroughData returns the template data of the given ingress, which is
+// expected to route a single host to a single backend.
+func newPassthroughData(clusterID string, ingress *v1beta1.Ingress) passthroughData {
+	data := passthroughData{
+		ClusterID: clusterID,
+	}
+
+	for _, rule := range ingress.Spec.Rules {
+		data.Host = rule.Host
+		if rule.HTTP == nil {
+			continue
+		}
+		for _, path := range rule.HTTP.Paths {
+			data.ServiceName = path.Backend.ServiceName
+			data.ServicePort = path.Backend.ServicePort.String()
+		}
+	}
+
+	return data
+}
@@ -0,0 +1,71 @@
+package ingress
+
+import (
+	"reflect"
+	"testing"
+)
+
+func Test_renderPassthroughAnnotations(t *testing.T) {
+	testCases := []struct {
+		name         string
+		template     string
+		expected     map[string]string
+		errorMatcher func(error) bool
+	}{
+		{
+			name:     "case 0: default template",
+			template: defaultPassthroughAnnotations,
+			expected: map[string]string{
+				"nginx.ingress.kubernetes.io/ssl-passthrough": "true",
+			},
+		},
+		{
+			name:     "case 1: template using ingress data",
+			template: "haproxy.org/ssl-passthrough: \"true\"\nexample.com/backend: '{{ .ClusterID }}/{{ .ServiceName }}:{{ .ServicePort }}'\n",
+			expected: map[string]string{
+				"haproxy.org/ssl-passthrough": "true",
+				"example.com/backend":         "al9qy/master:443",
+			},
+		},
+		{
+			name:     "case 2: empty template",
+			template: "",
+			expected: map[string]string{},
+		},
+		{
+			name:         "case 3: unknown field",
+			template:     "example.com/host: '{{ .Unknown }}'",
+			errorMatcher: IsInvalidConfig,
+		},
+		{
+			name:         "case 4: no object",
+			template:     "- true",
+			errorMatcher: IsInvalidConfig,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			data := passthroughData{
+				ClusterID:   "al9qy",
+				Host:        "api.al9qy.example.com",
+				ServiceName: "master",
+				ServicePort: "443",
+			}
+
+			annotations, err := renderPassthroughAnnotations(tc.template, data)
+			if tc.errorMatcher != nil {
+				if !tc.errorMatcher(err) {
+					t.Fatalf("expected matching error got %#v", err)
+				}
+				return
+			} else if err != nil {
+				t.Fatalf("expected %#v got %#v", nil, err)
+			}
+
+			if !reflect.DeepEqual(annotations, tc.expected) {
+				t.Fatalf("expected %#v got %#v", tc.expected, annotations)
+			}
+		})
+	}
+}
@@ -1,9 +1,14 @@
 package ingress
 
 import (
+	"reflect"
+	"sort"
+	"strings"
+
 	"github.com/giantswarm/microerror"
 	"github.com/giantswarm/micrologger"
 	"k8s.io/api/networking/v1beta1"
+	"k8s.io/client-go/dynamic"
 	"k8s.io/client-go/kubernetes"
 )
 
@@ -12,13 +17,29 @@ const (
 	EtcdID = "etcd"
 	// Name is the identifier of the resource.
 	Name = "ingress"
+
+	// managedAnnotationsAnnotation lists the annotations of an ingress which
+	// are managed by this resource, so that annotations set by others are
+	// kept and annotations no longer configured are removed.
+	managedAnnotationsAnnotation = "kvm-operator.giantswarm.io/managed-annotations"
 )
 
+// legacyManagedAnnotations are the annotations managed on ingresses created
+// before managedAnnotationsAnnotation was set.
+var legacyManagedAnnotations = []string{
+	"nginx.ingress.kubernetes.io/ssl-passthrough",
+}
+
 // Config represents the configuration used to create a new ingress resource.
 type Config struct {
 	// Dependencies.
+	DynClient dynamic.Interface
 	K8sClient kubernetes.Interface
 	Logger    micrologger.Logger
+
+	// Settings.
+	Class                  string
+	PassthroughAnnotations string
 }
 
 // DefaultConfig provides a default configuration to create a new ingress
@@ -26,21 +47,34 @@ type Config struct {
 func DefaultConfig() Config {
 	return Config{
 		// Dependencies.
+		DynClient: nil,
 		K8sClient: nil,
 		Logger:    nil,
+
+		// Settings.
+		Class:                  "",
+		PassthroughAnnotations: defaultPassthroughAnnotations,
 	}
 }
 
 // Resource implements the ingress resource.
 type Resource struct {
 	// Dependencies.
+	dynClient dynamic.Interface
 	k8sClient kubernetes.Interface
 	logger    micrologger.Logger
+
+	// Settings.
+	class                  string
+	passthroughAnnotations string
 }
 
 // New creates a new configured ingress resource.
 func New(config Config) (*Resource, error) {
 	// Dependencies.
+	if config.DynClient == nil {
+		return nil, microerror.Maskf(invalidConfigError, "config.DynClient must not be empty")
+	}
 	if config.K8sClient == nil {
 		return nil, microerror.Maskf(invalidConfigError, "config.K8sClient must not be empty")
 	}
@@ -48,10 +82,23 @@ func New(config Config) (*Resource, error) {
 		return nil, microerror.Maskf(invalidConfigError, "config.Logger must not be empty")
 	}
 
+	// Settings.
+	{
+		_, err := renderPassthroughAnnotations(config.PassthroughAnnotations, passthroughData{})
+		if err != nil {
+			return nil, microerror.Mask(err)
+		}
+	}
+
 	newResource := &Resource{
 		// Dependencies.
+		dynClient: config.DynClient,
 		k8sClient: config.K8sClient,
 		logger:    config.Logger,
+
+		// Settings.
+		class:                  config.Class,
+		passthroughAnnotations: config.PassthroughAnnotations,
 	}
 
 	return newResource, nil
@@ -71,6 +118,102 @@ func containsIngress(list []*v1beta1.Ingress, item *v1beta1.Ingress) bool {
 	return false
 }
 
+func getIngressByName(list []*v1beta1.Ingress, name string) (*v1beta1.Ingress, error) {
+	for _, l := range list {
+		if l.Name == name {
+			return l, nil
+		}
+	}
+
+	return nil, microerror.Mask(notFoundError)
+}
+
+func isIngressModified(a, b *v1beta1.Ingress) bool {
+	if !reflect.DeepEqual(a.Spec.IngressClassName, b.Spec.IngressClassName) {
+		return true
+	}
+
+	for _, k := range append(managedAnnotations(a), managedAnnotations(b)...) {
+		av, aok := a.Annotations[k]
+		bv, bok := b.Annotations[k]
+		if aok != bok || av != bv {
+			return true
+		}
+	}
+
+	if !reflect.DeepEqual(a.Spec.Rules, b.Spec.Rules) {
+		return true
+	}
+
+	if !reflect.DeepEqual(a.Spec.TLS, b.Spec.TLS) {
+		return true
+	}
+
+	return false
+}
+
+// managedAnnotations returns the keys of the annotations of the given ingress
+// managed by this resource, including the legacy ingress class annotation
+// replaced by spec.ingressClassName.
+func managedAnnotations(ingress *v1beta1.Ingress) []string {
+	keys := []string{legacyIngressClassAnnotation, managedAnnotationsAnnotation}
+
+	v, ok := ingress.Annotations[managedAnnotationsAnnotation]
+	if !ok {
+		return append(keys, legacyManagedAnnotations...)
+	}
+
+	for _, k := range strings.Split(v, ",") {
+		if k != "" {
+			keys = append(keys, k)
+		}
+	}
+
+	return keys
+}
+
+// mergeAnnotations returns the annotations of the current ingress with the
+// managed annotations replaced by the ones of the desired ingress. The legacy
+// ingress class annotation is removed once spec.ingressClassName is set.
+func mergeAnnotations(current, desired *v1beta1.Ingress) map[string]string {
+	merged := map[string]string{}
+	for k, v := range current.Annotations {
+		merged[k] = v
+	}
+
+	for _, k := range managedAnnotations(current) {
+		delete(merged, k)
+	}
+	if desired.Spec.IngressClassName == nil {
+		if v, ok := current.Annotations[legacyIngressClassAnnotation]; ok {
+			merged[legacyIngressClassAnnotation] = v
+		}
+	}
+
+	for k, v := range desired.Annotations {
+		merged[k] = v
+	}
+
+	return merged
+}
+
+// setManagedAnnotations records the keys of the current annotations of the
+// given ingress as managed by this resource.
+func setManagedAnnotations(ingress *v1beta1.Ingress) {
+	var keys []string
+	for k := range ingress.Annotations {
+		if k != managedAnnotationsAnnotation {
+			keys = append(keys, k)
+		}
+	}
+	sort.Strings(keys)
+
+	if ingress.Annotations == nil {
+		ingress.Annotations = map[string]string{}
+	}
+	ingress.Annotations[managedAnnotationsAnnotation] = strings.Join(keys, ",")
+}
+
 func toIngresses(v interface{}) ([]*v1beta1.Ingress, error) {
 	if v == nil {
 		return nil, nil
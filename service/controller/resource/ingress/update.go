@@ -5,9 +5,46 @@ import (
 
 	"github.com/giantswarm/microerror"
 	"github.com/giantswarm/operatorkit/v5/pkg/resource/crud"
+	"k8s.io/api/networking/v1beta1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/giantswarm/kvm-operator/v4/service/controller/key"
 )
 
 func (r *Resource) ApplyUpdateChange(ctx context.Context, obj, updateChange interface{}) error {
+	customObject, err := key.ToCustomObject(obj)
+	if err != nil {
+		return microerror.Mask(err)
+	}
+	ingressesToUpdate, err := toIngresses(updateChange)
+	if err != nil {
+		return microerror.Mask(err)
+	}
+
+	if len(ingressesToUpdate) != 0 {
+		r.logger.Debugf(ctx, "updating the ingresses in the Kubernetes API")
+
+		namespace := key.ClusterNamespace(customObject)
+		for _, ingress := range ingressesToUpdate {
+			u, err := toV1Ingress(ingress)
+			if err != nil {
+				return microerror.Mask(err)
+			}
+
+			_, err = r.dynClient.Resource(ingressResource).Namespace(namespace).Update(ctx, u, metav1.UpdateOptions{})
+			if apierrors.IsConflict(err) || apierrors.IsNotFound(err) {
+				// fall through, the ingress is updated with the next reconciliation
+			} else if err != nil {
+				return microerror.Mask(err)
+			}
+		}
+
+		r.logger.Debugf(ctx, "updated the ingresses in the Kubernetes API")
+	} else {
+		r.logger.Debugf(ctx, "the ingresses do not need to be updated in the Kubernetes API")
+	}
+
 	return nil
 }
 
@@ -30,5 +67,55 @@ func (r *Resource) NewUpdatePatch(ctx context.Context, obj, currentState, desire
 }
 
 func (r *Resource) newUpdateChange(ctx context.Context, obj, currentState, desiredState interface{}) (interface{}, error) {
-	return nil, nil
+	currentIngresses, err := toIngresses(currentState)
+	if err != nil {
+		return nil, microerror.Mask(err)
+	}
+	desiredIngresses, err := toIngresses(desiredState)
+	if err != nil {
+		return nil, microerror.Mask(err)
+	}
+
+	r.logger.Debugf(ctx, "finding out which ingresses have to be updated")
+
+	var ingressesToUpdate []*v1beta1.Ingress
+
+	for _, currentIngress := range currentIngresses {
+		desiredIngress, err := getIngressByName(desiredIngresses, currentIngress.Name)
+		if IsNotFound(err) {
+			continue
+		} else if err != nil {
+			return nil, microerror.Mask(err)
+		}
+
+		ingressToUpdate := desiredIngress.DeepCopy()
+
+		// Ingresses created through networking.k8s.io/v1beta1 may select their
+		// ingress controller via the legacy annotation, which must not be set
+		// together with spec.ingressClassName. Unless an ingress class is
+		// configured, its value is moved into the spec and kept there.
+		if ingressToUpdate.Spec.IngressClassName == nil {
+			if class := currentIngress.Annotations[legacyIngressClassAnnotation]; class != "" {
+				ingressToUpdate.Spec.IngressClassName = &class
+			} else {
+				ingressToUpdate.Spec.IngressClassName = currentIngress.Spec.IngressClassName
+			}
+		}
+
+		// Annotations not managed by this resource are kept.
+		ingressToUpdate.Annotations = mergeAnnotations(currentIngress, ingressToUpdate)
+
+		if !isIngressModified(ingressToUpdate, currentIngress) {
+			continue
+		}
+
+		ingressToUpdate.ResourceVersion = currentIngress.ResourceVersion
+		ingressesToUpdate = append(ingressesToUpdate, ingressToUpdate)
+
+		r.logger.Debugf(ctx, "found ingress %#q that has to be updated", ingressToUpdate.Name)
+	}
+
+	r.logger.Debugf(ctx, "found %d ingresses that have to be updated", len(ingressesToUpdate))
+
+	return ingressesToUpdate, nil
 }
@@ -0,0 +1,203 @@
+package ingress
+
+import (
+	"context"
+	"testing"
+
+	"github.com/giantswarm/apiextensions/v3/pkg/apis/provider/v1alpha1"
+	"github.com/giantswarm/micrologger/microloggertest"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
+	"k8s.io/client-go/kubernetes/fake"
+
+	"github.com/giantswarm/kvm-operator/v4/service/controller/key"
+)
+
+// testLegacyIngress returns an ingress as created through
+// networking.k8s.io/v1beta1 by earlier versions of this resource.
+func testLegacyIngress(name, host string, port int64) *unstructured.Unstructured {
+	return &unstructured.Unstructured{
+		Object: map[string]interface{}{
+			"apiVersion": "networking.k8s.io/v1",
+			"kind":       "Ingress",
+			"metadata": map[string]interface{}{
+				"name":            name,
+				"namespace":       "al9qy",
+				"resourceVersion": "1",
+				"annotations": map[string]interface{}{
+					legacyIngressClassAnnotation:                  "nginx",
+					"nginx.ingress.kubernetes.io/ssl-passthrough": "true",
+					"external-dns.alpha.kubernetes.io/ttl":        "60",
+				},
+			},
+			"spec": map[string]interface{}{
+				"tls": []interface{}{
+					map[string]interface{}{
+						"hosts": []interface{}{host},
+					},
+				},
+				"rules": []interface{}{
+					map[string]interface{}{
+						"host": host,
+						"http": map[string]interface{}{
+							"paths": []interface{}{
+								map[string]interface{}{
+									"path":     "/",
+									"pathType": "ImplementationSpecific",
+									"backend": map[string]interface{}{
+										"service": map[string]interface{}{
+											"name": "master",
+											"port": map[string]interface{}{
+												"number": port,
+											},
+										},
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func Test_Resource_Ingress_update(t *testing.T) {
+	testCases := []struct {
+		name                string
+		class               string
+		annotations         map[string]string
+		expectedClass       string
+		expectedAnnotations map[string]string
+	}{
+		{
+			name:          "case 0: legacy ingress class annotation is migrated",
+			expectedClass: "nginx",
+			expectedAnnotations: map[string]string{
+				managedAnnotationsAnnotation:                  "nginx.ingress.kubernetes.io/ssl-passthrough",
+				"nginx.ingress.kubernetes.io/ssl-passthrough": "true",
+				"external-dns.alpha.kubernetes.io/ttl":        "60",
+			},
+		},
+		{
+			name:          "case 1: installation ingress class replaces legacy annotation",
+			class:         "ingress-passthrough",
+			expectedClass: "ingress-passthrough",
+			expectedAnnotations: map[string]string{
+				managedAnnotationsAnnotation:                  "nginx.ingress.kubernetes.io/ssl-passthrough",
+				"nginx.ingress.kubernetes.io/ssl-passthrough": "true",
+				"external-dns.alpha.kubernetes.io/ttl":        "60",
+			},
+		},
+		{
+			name:  "case 2: cluster overrides class and passthrough annotations",
+			class: "ingress-passthrough",
+			annotations: map[string]string{
+				key.AnnotationIngressClass:                  "haproxy",
+				key.AnnotationIngressPassthroughAnnotations: `haproxy.org/ssl-passthrough: "true"`,
+			},
+			expectedClass: "haproxy",
+			expectedAnnotations: map[string]string{
+				managedAnnotationsAnnotation:           "haproxy.org/ssl-passthrough",
+				"haproxy.org/ssl-passthrough":          "true",
+				"external-dns.alpha.kubernetes.io/ttl": "60",
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			customObject := &v1alpha1.KVMConfig{
+				ObjectMeta: metav1.ObjectMeta{
+					Annotations: tc.annotations,
+				},
+				Spec: v1alpha1.KVMConfigSpec{
+					Cluster: v1alpha1.Cluster{
+						ID: "al9qy",
+						Etcd: v1alpha1.ClusterEtcd{
+							Domain: "etcd.al9qy.example.com",
+						},
+						Kubernetes: v1alpha1.ClusterKubernetes{
+							API: v1alpha1.ClusterKubernetesAPI{
+								Domain:     "api.al9qy.example.com",
+								SecurePort: 443,
+							},
+						},
+					},
+				},
+			}
+
+			dynClient := dynamicfake.NewSimpleDynamicClient(runtime.NewScheme(),
+				testLegacyIngress(APIID, "api.al9qy.example.com", 443),
+				testLegacyIngress(EtcdID, "etcd.al9qy.example.com", 2379),
+			)
+
+			var err error
+			var newResource *Resource
+			{
+				resourceConfig := DefaultConfig()
+				resourceConfig.Class = tc.class
+				resourceConfig.DynClient = dynClient
+				resourceConfig.K8sClient = fake.NewSimpleClientset()
+				resourceConfig.Logger = microloggertest.New()
+				newResource, err = New(resourceConfig)
+				if err != nil {
+					t.Fatal("expected", nil, "got", err)
+				}
+			}
+
+			current, err := newResource.GetCurrentState(context.TODO(), customObject)
+			if err != nil {
+				t.Fatalf("expected %#v got %#v", nil, err)
+			}
+			desired, err := newResource.GetDesiredState(context.TODO(), customObject)
+			if err != nil {
+				t.Fatalf("expected %#v got %#v", nil, err)
+			}
+			update, err := newResource.newUpdateChange(context.TODO(), customObject, current, desired)
+			if err != nil {
+				t.Fatalf("expected %#v got %#v", nil, err)
+			}
+			err = newResource.ApplyUpdateChange(context.TODO(), customObject, update)
+			if err != nil {
+				t.Fatalf("expected %#v got %#v", nil, err)
+			}
+
+			current, err = newResource.GetCurrentState(context.TODO(), customObject)
+			if err != nil {
+				t.Fatalf("expected %#v got %#v", nil, err)
+			}
+			ingresses, err := toIngresses(current)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(ingresses) != 2 {
+				t.Fatalf("expected %d ingresses got %d", 2, len(ingresses))
+			}
+
+			for _, ingress := range ingresses {
+				if ingress.Spec.IngressClassName == nil || *ingress.Spec.IngressClassName != tc.expectedClass {
+					t.Fatalf("expected ingress %#q to have class %#q got %#v", ingress.Name, tc.expectedClass, ingress.Spec.IngressClassName)
+				}
+				if len(ingress.Annotations) != len(tc.expectedAnnotations) {
+					t.Fatalf("expected ingress %#q to have annotations %#v got %#v", ingress.Name, tc.expectedAnnotations, ingress.Annotations)
+				}
+				for k, v := range tc.expectedAnnotations {
+					if ingress.Annotations[k] != v {
+						t.Fatalf("expected ingress %#q to have annotations %#v got %#v", ingress.Name, tc.expectedAnnotations, ingress.Annotations)
+					}
+				}
+			}
+
+			update, err = newResource.newUpdateChange(context.TODO(), customObject, current, desired)
+			if err != nil {
+				t.Fatalf("expected %#v got %#v", nil, err)
+			}
+			if u, _ := toIngresses(update); len(u) != 0 {
+				t.Fatalf("expected no further updates got %d", len(u))
+			}
+		})
+	}
+}
@@ -0,0 +1,156 @@
+package ingress
+
+import (
+	"github.com/giantswarm/microerror"
+	"k8s.io/api/networking/v1beta1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+// The vendored client-go does not ship the networking.k8s.io/v1 Ingress types.
+// Ingresses are therefore written and read through the dynamic client and
+// converted from and to the v1beta1 representation used within this resource.
+// Objects created through networking.k8s.io/v1beta1 are served by the v1 API
+// as well, so they are migrated by the next update.
+var ingressResource = schema.GroupVersionResource{
+	Group:    "networking.k8s.io",
+	Version:  "v1",
+	Resource: "ingresses",
+}
+
+// pathTypeImplementationSpecific keeps the path matching of ingresses created
+// before the path type was required.
+var pathTypeImplementationSpecific = v1beta1.PathTypeImplementationSpecific
+
+const (
+	// legacyIngressClassAnnotation is the annotation used to select the
+	// ingress controller before spec.ingressClassName existed.
+	legacyIngressClassAnnotation = "kubernetes.io/ingress.class"
+)
+
+// toV1Ingress converts the given v1beta1 ingress into its
+// networking.k8s.io/v1 representation.
+func toV1Ingress(ingress *v1beta1.Ingress) (*unstructured.Unstructured, error) {
+	content, err := runtime.DefaultUnstructuredConverter.ToUnstructured(ingress)
+	if err != nil {
+		return nil, microerror.Mask(err)
+	}
+
+	u := &unstructured.Unstructured{Object: content}
+	u.SetAPIVersion(ingressResource.GroupVersion().String())
+	u.SetKind("Ingress")
+	unstructured.RemoveNestedField(u.Object, "status")
+
+	rules, _, err := unstructured.NestedSlice(u.Object, "spec", "rules")
+	if err != nil {
+		return nil, microerror.Mask(err)
+	}
+	for _, rule := range rules {
+		paths, _, err := unstructured.NestedSlice(rule.(map[string]interface{}), "http", "paths")
+		if err != nil {
+			return nil, microerror.Mask(err)
+		}
+		for _, path := range paths {
+			p := path.(map[string]interface{})
+			if _, ok := p["pathType"]; !ok {
+				p["pathType"] = string(v1beta1.PathTypeImplementationSpecific)
+			}
+			b, ok := p["backend"].(map[string]interface{})
+			if ok {
+				p["backend"] = toV1Backend(b)
+			}
+		}
+		if len(paths) > 0 {
+			err = unstructured.SetNestedSlice(rule.(map[string]interface{}), paths, "http", "paths")
+			if err != nil {
+				return nil, microerror.Mask(err)
+			}
+		}
+	}
+	if len(rules) > 0 {
+		err = unstructured.SetNestedSlice(u.Object, rules, "spec", "rules")
+		if err != nil {
+			return nil, microerror.Mask(err)
+		}
+	}
+
+	return u, nil
+}
+
+// fromV1Ingress converts the given networking.k8s.io/v1 ingress into its
+// v1beta1 representation.
+func fromV1Ingress(u *unstructured.Unstructured) (*v1beta1.Ingress, error) {
+	content := u.DeepCopy().Object
+
+	rules, _, err := unstructured.NestedSlice(content, "spec", "rules")
+	if err != nil {
+		return nil, microerror.Mask(err)
+	}
+	for _, rule := range rules {
+		paths, _, err := unstructured.NestedSlice(rule.(map[string]interface{}), "http", "paths")
+		if err != nil {
+			return nil, microerror.Mask(err)
+		}
+		for _, path := range paths {
+			p := path.(map[string]interface{})
+			b, ok := p["backend"].(map[string]interface{})
+			if ok {
+				p["backend"] = fromV1Backend(b)
+			}
+		}
+		if len(paths) > 0 {
+			err = unstructured.SetNestedSlice(rule.(map[string]interface{}), paths, "http", "paths")
+			if err != nil {
+				return nil, microerror.Mask(err)
+			}
+		}
+	}
+	if len(rules) > 0 {
+		err = unstructured.SetNestedSlice(content, rules, "spec", "rules")
+		if err != nil {
+			return nil, microerror.Mask(err)
+		}
+	}
+
+	var ingress v1beta1.Ingress
+	err = runtime.DefaultUnstructuredConverter.FromUnstructured(content, &ingress)
+	if err != nil {
+		return nil, microerror.Mask(err)
+	}
+
+	return &ingress, nil
+}
+
+func toV1Backend(backend map[string]interface{}) map[string]interface{} {
+	port := map[string]interface{}{}
+	switch v := backend["servicePort"].(type) {
+	case int64:
+		port["number"] = v
+	case string:
+		port["name"] = v
+	}
+
+	return map[string]interface{}{
+		"service": map[string]interface{}{
+			"name": backend["serviceName"],
+			"port": port,
+		},
+	}
+}
+
+func fromV1Backend(backend map[string]interface{}) map[string]interface{} {
+	name, _, _ := unstructured.NestedString(backend, "service", "name")
+	number, _, _ := unstructured.NestedInt64(backend, "service", "port", "number")
+	portName, _, _ := unstructured.NestedString(backend, "service", "port", "name")
+
+	var servicePort interface{} = number
+	if portName != "" {
+		servicePort = portName
+	}
+
+	return map[string]interface{}{
+		"serviceName": name,
+		"servicePort": servicePort,
+	}
+}
@@ -0,0 +1,63 @@
+package ingress
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/giantswarm/apiextensions/v3/pkg/apis/provider/v1alpha1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+func Test_toV1Ingress(t *testing.T) {
+	customObject := v1alpha1.KVMConfig{
+		Spec: v1alpha1.KVMConfigSpec{
+			Cluster: v1alpha1.Cluster{
+				ID: "al9qy",
+				Kubernetes: v1alpha1.ClusterKubernetes{
+					API: v1alpha1.ClusterKubernetesAPI{
+						Domain:     "api.al9qy.example.com",
+						SecurePort: 443,
+					},
+				},
+			},
+		},
+	}
+
+	ingress := newAPIIngress(customObject)
+
+	u, err := toV1Ingress(ingress)
+	if err != nil {
+		t.Fatalf("expected %#v got %#v", nil, err)
+	}
+
+	if u.GetAPIVersion() != "networking.k8s.io/v1" {
+		t.Fatalf("expected API version %#q got %#q", "networking.k8s.io/v1", u.GetAPIVersion())
+	}
+
+	paths, _, err := unstructured.NestedSlice(u.Object, "spec", "rules")
+	if err != nil || len(paths) != 1 {
+		t.Fatalf("expected one rule got %#v", paths)
+	}
+	rule := paths[0].(map[string]interface{})
+	path := rule["http"].(map[string]interface{})["paths"].([]interface{})[0].(map[string]interface{})
+
+	name, _, _ := unstructured.NestedString(path, "backend", "service", "name")
+	if name != "master" {
+		t.Fatalf("expected service name %#q got %#q", "master", name)
+	}
+	number, _, _ := unstructured.NestedInt64(path, "backend", "service", "port", "number")
+	if number != 443 {
+		t.Fatalf("expected service port %d got %d", 443, number)
+	}
+	if path["pathType"] != "ImplementationSpecific" {
+		t.Fatalf("expected path type %#q got %#v", "ImplementationSpecific", path["pathType"])
+	}
+
+	roundTrip, err := fromV1Ingress(u)
+	if err != nil {
+		t.Fatalf("expected %#v got %#v", nil, err)
+	}
+	if !reflect.DeepEqual(roundTrip.Spec, ingress.Spec) {
+		t.Fatalf("expected %#v got %#v", ingress.Spec, roundTrip.Spec)
+	}
+}
@@ -0,0 +1,21 @@
+package lastreconciled
+
+import (
+	"context"
+
+	"github.com/giantswarm/microerror"
+
+	"github.com/giantswarm/kvm-operator/v4/service/controller/key"
+	"github.com/giantswarm/kvm-operator/v4/service/metric"
+)
+
+func (r *Resource) EnsureCreated(ctx context.Context, obj interface{}) error {
+	cr, err := key.ToCustomObject(obj)
+	if err != nil {
+		return microerror.Mask(err)
+	}
+
+	metric.ClusterLastReconciledGauge.WithLabelValues(key.ClusterID(cr), key.ClusterCustomer(cr)).SetToCurrentTime()
+
+	return nil
+}
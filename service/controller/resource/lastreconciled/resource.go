@@ -0,0 +1,38 @@
+package lastreconciled
+
+import (
+	"github.com/giantswarm/microerror"
+	"github.com/giantswarm/micrologger"
+)
+
+const (
+	Name = "lastreconciled"
+)
+
+type Config struct {
+	Logger micrologger.Logger
+}
+
+// Resource records the time of the last successful reconciliation of a
+// cluster in metric.ClusterLastReconciledGauge. It has to be the last resource
+// of the cluster controller, so that it is only executed once all other
+// resources succeeded.
+type Resource struct {
+	logger micrologger.Logger
+}
+
+func New(config Config) (*Resource, error) {
+	if config.Logger == nil {
+		return nil, microerror.Maskf(invalidConfigError, "%T.Logger must not be empty", config)
+	}
+
+	r := &Resource{
+		logger: config.Logger,
+	}
+
+	return r, nil
+}
+
+func (r *Resource) Name() string {
+	return Name
+}
@@ -0,0 +1,61 @@
+package networkpolicy
+
+import (
+	"context"
+
+	"github.com/giantswarm/microerror"
+	networkingv1 "k8s.io/api/networking/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func (r *Resource) ApplyCreateChange(ctx context.Context, obj, createChange interface{}) error {
+	networkPoliciesToCreate, err := toNetworkPolicies(createChange)
+	if err != nil {
+		return microerror.Mask(err)
+	}
+
+	if len(networkPoliciesToCreate) != 0 {
+		r.logger.Debugf(ctx, "creating the network policies in the Kubernetes API")
+
+		for _, networkPolicy := range networkPoliciesToCreate {
+			_, err := r.k8sClient.NetworkingV1().NetworkPolicies(networkPolicy.Namespace).Create(ctx, networkPolicy, metav1.CreateOptions{})
+			if apierrors.IsAlreadyExists(err) {
+				// fall through
+			} else if err != nil {
+				return microerror.Mask(err)
+			}
+		}
+
+		r.logger.Debugf(ctx, "created the network policies in the Kubernetes API")
+	} else {
+		r.logger.Debugf(ctx, "the network policies do not need to be created in the Kubernetes API")
+	}
+
+	return nil
+}
+
+func (r *Resource) newCreateChange(ctx context.Context, obj, currentState, desiredState interface{}) (interface{}, error) {
+	currentNetworkPolicies, err := toNetworkPolicies(currentState)
+	if err != nil {
+		return nil, microerror.Mask(err)
+	}
+	desiredNetworkPolicies, err := toNetworkPolicies(desiredState)
+	if err != nil {
+		return nil, microerror.Mask(err)
+	}
+
+	r.logger.Debugf(ctx, "finding out which network policies have to be created")
+
+	var networkPoliciesToCreate []*networkingv1.NetworkPolicy
+
+	for _, desiredNetworkPolicy := range desiredNetworkPolicies {
+		if !containsNetworkPolicy(currentNetworkPolicies, desiredNetworkPolicy) {
+			networkPoliciesToCreate = append(networkPoliciesToCreate, desiredNetworkPolicy)
+		}
+	}
+
+	r.logger.Debugf(ctx, "found %d network policies that have to be created", len(networkPoliciesToCreate))
+
+	return networkPoliciesToCreate, nil
+}
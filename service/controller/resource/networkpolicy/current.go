@@ -0,0 +1,52 @@
+package networkpolicy
+
+import (
+	"context"
+
+	"github.com/giantswarm/microerror"
+	"github.com/giantswarm/operatorkit/v5/pkg/controller/context/resourcecanceledcontext"
+	networkingv1 "k8s.io/api/networking/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+
+	"github.com/giantswarm/kvm-operator/v4/service/controller/key"
+)
+
+func (r *Resource) GetCurrentState(ctx context.Context, obj interface{}) (interface{}, error) {
+	customObject, err := key.ToCustomObject(obj)
+	if err != nil {
+		return nil, microerror.Mask(err)
+	}
+
+	if key.IsDeleted(&customObject) {
+		r.logger.Debugf(ctx, "redirecting responsibility of deletion of network policies to namespace termination")
+		resourcecanceledcontext.SetCanceled(ctx)
+		r.logger.Debugf(ctx, "canceling resource")
+
+		return nil, nil
+	}
+
+	r.logger.Debugf(ctx, "looking for a list of network policies in the Kubernetes API")
+
+	var currentNetworkPolicies []*networkingv1.NetworkPolicy
+	{
+		lo := metav1.ListOptions{
+			LabelSelector: labels.SelectorFromSet(labels.Set{
+				key.LabelManagedBy: key.OperatorName,
+			}).String(),
+		}
+
+		list, err := r.k8sClient.NetworkingV1().NetworkPolicies(key.ClusterNamespace(customObject)).List(ctx, lo)
+		if err != nil {
+			return nil, microerror.Mask(err)
+		}
+
+		for i := range list.Items {
+			currentNetworkPolicies = append(currentNetworkPolicies, &list.Items[i])
+		}
+	}
+
+	r.logger.Debugf(ctx, "found a list of %d network policies in the Kubernetes API", len(currentNetworkPolicies))
+
+	return currentNetworkPolicies, nil
+}
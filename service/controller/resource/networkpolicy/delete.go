@@ -0,0 +1,69 @@
+package networkpolicy
+
+import (
+	"context"
+
+	"github.com/giantswarm/microerror"
+	"github.com/giantswarm/operatorkit/v5/pkg/resource/crud"
+	networkingv1 "k8s.io/api/networking/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// ApplyDeleteChange deletes network policies which are no longer desired, e.g.
+// the allow-list once the annotation defining it got removed. Network policies
+// of deleted clusters are removed by the namespace termination.
+func (r *Resource) ApplyDeleteChange(ctx context.Context, obj, deleteChange interface{}) error {
+	networkPoliciesToDelete, err := toNetworkPolicies(deleteChange)
+	if err != nil {
+		return microerror.Mask(err)
+	}
+
+	if len(networkPoliciesToDelete) != 0 {
+		r.logger.Debugf(ctx, "deleting the network policies in the Kubernetes API")
+
+		for _, networkPolicy := range networkPoliciesToDelete {
+			err := r.k8sClient.NetworkingV1().NetworkPolicies(networkPolicy.Namespace).Delete(ctx, networkPolicy.Name, metav1.DeleteOptions{})
+			if apierrors.IsNotFound(err) {
+				// fall through
+			} else if err != nil {
+				return microerror.Mask(err)
+			}
+		}
+
+		r.logger.Debugf(ctx, "deleted the network policies in the Kubernetes API")
+	} else {
+		r.logger.Debugf(ctx, "the network policies do not need to be deleted from the Kubernetes API")
+	}
+
+	return nil
+}
+
+func (r *Resource) NewDeletePatch(ctx context.Context, obj, currentState, desiredState interface{}) (*crud.Patch, error) {
+	return crud.NewPatch(), nil
+}
+
+func (r *Resource) newDeleteChange(ctx context.Context, obj, currentState, desiredState interface{}) (interface{}, error) {
+	currentNetworkPolicies, err := toNetworkPolicies(currentState)
+	if err != nil {
+		return nil, microerror.Mask(err)
+	}
+	desiredNetworkPolicies, err := toNetworkPolicies(desiredState)
+	if err != nil {
+		return nil, microerror.Mask(err)
+	}
+
+	r.logger.Debugf(ctx, "finding out which network policies have to be deleted")
+
+	var networkPoliciesToDelete []*networkingv1.NetworkPolicy
+
+	for _, currentNetworkPolicy := range currentNetworkPolicies {
+		if !containsNetworkPolicy(desiredNetworkPolicies, currentNetworkPolicy) {
+			networkPoliciesToDelete = append(networkPoliciesToDelete, currentNetworkPolicy)
+		}
+	}
+
+	r.logger.Debugf(ctx, "found %d network policies that have to be deleted", len(networkPoliciesToDelete))
+
+	return networkPoliciesToDelete, nil
+}
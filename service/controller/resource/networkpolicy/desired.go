@@ -0,0 +1,203 @@
+package networkpolicy
+
+import (
+	"context"
+
+	"github.com/giantswarm/apiextensions/v3/pkg/apis/provider/v1alpha1"
+	"github.com/giantswarm/microerror"
+	corev1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+
+	"github.com/giantswarm/kvm-operator/v4/service/controller/key"
+)
+
+func (r *Resource) GetDesiredState(ctx context.Context, obj interface{}) (interface{}, error) {
+	customObject, err := key.ToCustomObject(obj)
+	if err != nil {
+		return nil, microerror.Mask(err)
+	}
+
+	r.logger.Debugf(ctx, "computing the new network policies")
+
+	networkPolicies, err := r.newNetworkPolicies(customObject)
+	if err != nil {
+		return nil, microerror.Mask(err)
+	}
+
+	r.logger.Debugf(ctx, "computed the %d new network policies", len(networkPolicies))
+
+	return networkPolicies, nil
+}
+
+// newNetworkPolicies returns the network policies isolating the namespace of
+// the given cluster. All ingress traffic to the VM pods is denied by default
+// and only the traffic required to operate the cluster is allowed, on top of
+// the per-cluster allow-list.
+func (r *Resource) newNetworkPolicies(customObject v1alpha1.KVMConfig) ([]*networkingv1.NetworkPolicy, error) {
+	allowList, err := key.NetworkPolicyAllowList(customObject)
+	if err != nil {
+		return nil, microerror.Mask(err)
+	}
+
+	workerService, err := key.WorkerService(customObject)
+	if err != nil {
+		return nil, microerror.Mask(err)
+	}
+
+	clusterSelector := metav1.LabelSelector{
+		MatchLabels: map[string]string{
+			"cluster": key.ClusterID(customObject),
+		},
+	}
+	masterSelector := metav1.LabelSelector{
+		MatchLabels: map[string]string{
+			key.LabelApp: key.MasterID,
+			"cluster":    key.ClusterID(customObject),
+		},
+	}
+	workerSelector := metav1.LabelSelector{
+		MatchLabels: map[string]string{
+			key.LabelApp: key.WorkerID,
+			"cluster":    key.ClusterID(customObject),
+		},
+	}
+
+	var networkPolicies []*networkingv1.NetworkPolicy
+
+	// Selecting all pods without any ingress rule denies all ingress traffic
+	// not allowed by one of the other policies, including any traffic from
+	// other namespaces.
+	networkPolicies = append(networkPolicies, newNetworkPolicy(customObject, DefaultDenyID, metav1.LabelSelector{}, nil))
+
+	// The API and etcd ingresses, or TLS routes, are served by the ingress
+	// controller of the management cluster.
+	networkPolicies = append(networkPolicies, newNetworkPolicy(customObject, AllowIngressControllerID, masterSelector, []networkingv1.NetworkPolicyIngressRule{
+		{
+			From: []networkingv1.NetworkPolicyPeer{
+				{
+					NamespaceSelector: r.ingressControllerSelector.DeepCopy(),
+				},
+			},
+			Ports: []networkingv1.NetworkPolicyPort{
+				newPort(customObject.Spec.Cluster.Kubernetes.API.SecurePort),
+				newPort(2379),
+			},
+		},
+	}))
+
+	// The VMs of the cluster talk to each other on any port, e.g. workers
+	// reach the API servers, the API servers reach the kubelets, webhooks and
+	// aggregated APIs, masters form the etcd cluster and nodes exchange the
+	// overlay network traffic.
+	networkPolicies = append(networkPolicies, newNetworkPolicy(customObject, AllowClusterID, metav1.LabelSelector{}, []networkingv1.NetworkPolicyIngressRule{
+		{
+			From: []networkingv1.NetworkPolicyPeer{
+				{
+					PodSelector: clusterSelector.DeepCopy(),
+				},
+			},
+		},
+	}))
+
+	// The monitoring of the management cluster scrapes the master and worker
+	// services and the VMs behind them.
+	networkPolicies = append(networkPolicies, newNetworkPolicy(customObject, AllowMonitoringID, metav1.LabelSelector{}, []networkingv1.NetworkPolicyIngressRule{
+		{
+			From: []networkingv1.NetworkPolicyPeer{
+				{
+					NamespaceSelector: r.monitoringSelector.DeepCopy(),
+				},
+			},
+		},
+	}))
+
+	// The worker ingress service exposes the port mappings to clients outside
+	// of the management cluster, unless it is only reachable within the
+	// management cluster, where it is consumed by the ingress controller.
+	{
+		rule := networkingv1.NetworkPolicyIngressRule{}
+		for _, p := range key.PortMappings(customObject) {
+			rule.Ports = append(rule.Ports, newPort(p.TargetPort.IntValue()))
+		}
+		if workerService.Type == corev1.ServiceTypeClusterIP {
+			rule.From = []networkingv1.NetworkPolicyPeer{
+				{
+					NamespaceSelector: r.ingressControllerSelector.DeepCopy(),
+				},
+			}
+		}
+
+		networkPolicies = append(networkPolicies, newNetworkPolicy(customObject, AllowWorkerIngressID, workerSelector, []networkingv1.NetworkPolicyIngressRule{rule}))
+	}
+
+	// The liveness probes and the shutdown-deferrer are served on the loopback
+	// of the VM pods and must stay reachable within the namespace.
+	networkPolicies = append(networkPolicies, newNetworkPolicy(customObject, AllowProbesID, metav1.LabelSelector{}, []networkingv1.NetworkPolicyIngressRule{
+		{
+			From: []networkingv1.NetworkPolicyPeer{
+				{
+					PodSelector: &metav1.LabelSelector{},
+				},
+			},
+			Ports: []networkingv1.NetworkPolicyPort{
+				newPort(int(key.LivenessPort(customObject))),
+				newPort(key.ShutdownDeferrerListenPort(customObject)),
+			},
+		},
+	}))
+
+	// The Kubernetes API defaults the protocol of ports, which is done upfront
+	// so that the allow-list does not cause an update on every reconciliation.
+	for i := range allowList {
+		for j := range allowList[i].Ports {
+			if allowList[i].Ports[j].Protocol == nil {
+				protocol := corev1.ProtocolTCP
+				allowList[i].Ports[j].Protocol = &protocol
+			}
+		}
+	}
+	if len(allowList) != 0 {
+		networkPolicies = append(networkPolicies, newNetworkPolicy(customObject, AllowListID, metav1.LabelSelector{}, allowList))
+	}
+
+	return networkPolicies, nil
+}
+
+func newNetworkPolicy(customObject v1alpha1.KVMConfig, name string, podSelector metav1.LabelSelector, ingress []networkingv1.NetworkPolicyIngressRule) *networkingv1.NetworkPolicy {
+	return &networkingv1.NetworkPolicy{
+		TypeMeta: metav1.TypeMeta{
+			Kind:       "NetworkPolicy",
+			APIVersion: "networking.k8s.io/v1",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: key.ClusterNamespace(customObject),
+			Labels: map[string]string{
+				"cluster":             key.ClusterID(customObject),
+				"customer":            key.ClusterCustomer(customObject),
+				key.LabelCluster:      key.ClusterID(customObject),
+				key.LabelManagedBy:    key.OperatorName,
+				key.LabelOrganization: key.ClusterCustomer(customObject),
+			},
+		},
+		Spec: networkingv1.NetworkPolicySpec{
+			PodSelector: podSelector,
+			Ingress:     ingress,
+			PolicyTypes: []networkingv1.PolicyType{
+				networkingv1.PolicyTypeIngress,
+			},
+		},
+	}
+}
+
+func newPort(port int) networkingv1.NetworkPolicyPort {
+	protocol := corev1.ProtocolTCP
+	p := intstr.FromInt(port)
+
+	return networkingv1.NetworkPolicyPort{
+		Protocol: &protocol,
+		Port:     &p,
+	}
+}
@@ -0,0 +1,297 @@
+package networkpolicy
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"github.com/giantswarm/apiextensions/v3/pkg/apis/provider/v1alpha1"
+	"github.com/giantswarm/micrologger/microloggertest"
+	networkingv1 "k8s.io/api/networking/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/client-go/kubernetes/fake"
+
+	"github.com/giantswarm/kvm-operator/v4/service/controller/key"
+)
+
+func testKVMConfig(annotations map[string]string) *v1alpha1.KVMConfig {
+	return &v1alpha1.KVMConfig{
+		ObjectMeta: metav1.ObjectMeta{
+			Annotations: annotations,
+		},
+		Spec: v1alpha1.KVMConfigSpec{
+			Cluster: v1alpha1.Cluster{
+				ID: "al9qy",
+				Kubernetes: v1alpha1.ClusterKubernetes{
+					API: v1alpha1.ClusterKubernetesAPI{
+						SecurePort: 443,
+					},
+				},
+			},
+			KVM: v1alpha1.KVMConfigSpecKVM{
+				Network: v1alpha1.KVMConfigSpecKVMNetwork{
+					Flannel: v1alpha1.KVMConfigSpecKVMNetworkFlannel{
+						VNI: 5,
+					},
+				},
+			},
+		},
+	}
+}
+
+func testResource(t *testing.T, k8sClient *fake.Clientset) *Resource {
+	c := Config{
+		K8sClient: k8sClient,
+		Logger:    microloggertest.New(),
+
+		IngressControllerSelector: "kubernetes.io/metadata.name=kube-system",
+		MonitoringSelector:        "kubernetes.io/metadata.name=monitoring",
+	}
+
+	r, err := New(c)
+	if err != nil {
+		t.Fatalf("expected nil, got %#v", err)
+	}
+
+	return r
+}
+
+func Test_Resource_NetworkPolicy_GetDesiredState(t *testing.T) {
+	testCases := []struct {
+		name                  string
+		annotations           map[string]string
+		expectedNames         []string
+		expectedWorkerIngress []networkingv1.NetworkPolicyPeer
+	}{
+		{
+			name: "case 0: worker ingress is reachable from anywhere",
+			expectedNames: []string{
+				DefaultDenyID,
+				AllowIngressControllerID,
+				AllowClusterID,
+				AllowMonitoringID,
+				AllowWorkerIngressID,
+				AllowProbesID,
+			},
+		},
+		{
+			name: "case 1: ClusterIP worker ingress is reachable from the ingress controller",
+			annotations: map[string]string{
+				key.AnnotationWorkerService: `{"type":"ClusterIP"}`,
+			},
+			expectedNames: []string{
+				DefaultDenyID,
+				AllowIngressControllerID,
+				AllowClusterID,
+				AllowMonitoringID,
+				AllowWorkerIngressID,
+				AllowProbesID,
+			},
+			expectedWorkerIngress: []networkingv1.NetworkPolicyPeer{
+				{
+					NamespaceSelector: &metav1.LabelSelector{
+						MatchLabels: map[string]string{
+							"kubernetes.io/metadata.name": "kube-system",
+						},
+					},
+				},
+			},
+		},
+		{
+			name: "case 2: allow-list",
+			annotations: map[string]string{
+				key.AnnotationNetworkPolicyAllowList: `[{"from":[{"ipBlock":{"cidr":"10.0.0.0/8"}}],"ports":[{"port":22}]}]`,
+			},
+			expectedNames: []string{
+				DefaultDenyID,
+				AllowIngressControllerID,
+				AllowClusterID,
+				AllowMonitoringID,
+				AllowWorkerIngressID,
+				AllowProbesID,
+				AllowListID,
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			r := testResource(t, fake.NewSimpleClientset())
+
+			result, err := r.GetDesiredState(context.TODO(), testKVMConfig(tc.annotations))
+			if err != nil {
+				t.Fatalf("expected nil, got %#v", err)
+			}
+			networkPolicies, err := toNetworkPolicies(result)
+			if err != nil {
+				t.Fatalf("expected nil, got %#v", err)
+			}
+
+			var names []string
+			for _, networkPolicy := range networkPolicies {
+				names = append(names, networkPolicy.Name)
+
+				if networkPolicy.Namespace != "al9qy" {
+					t.Fatalf("expected namespace %#q, got %#q", "al9qy", networkPolicy.Namespace)
+				}
+				if !reflect.DeepEqual(networkPolicy.Spec.PolicyTypes, []networkingv1.PolicyType{networkingv1.PolicyTypeIngress}) {
+					t.Fatalf("expected ingress policy type, got %v", networkPolicy.Spec.PolicyTypes)
+				}
+			}
+			if !reflect.DeepEqual(names, tc.expectedNames) {
+				t.Fatalf("expected network policies %v, got %v", tc.expectedNames, names)
+			}
+
+			defaultDeny, _ := getNetworkPolicyByName(networkPolicies, DefaultDenyID)
+			if len(defaultDeny.Spec.PodSelector.MatchLabels) != 0 || len(defaultDeny.Spec.Ingress) != 0 {
+				t.Fatalf("expected default deny to select all pods without ingress rules, got %#v", defaultDeny.Spec)
+			}
+
+			workerIngress, _ := getNetworkPolicyByName(networkPolicies, AllowWorkerIngressID)
+			if !reflect.DeepEqual(workerIngress.Spec.Ingress[0].From, tc.expectedWorkerIngress) {
+				t.Fatalf("expected worker ingress peers %#v, got %#v", tc.expectedWorkerIngress, workerIngress.Spec.Ingress[0].From)
+			}
+			if len(workerIngress.Spec.Ingress[0].Ports) != 2 {
+				t.Fatalf("expected 2 worker ingress ports, got %d", len(workerIngress.Spec.Ingress[0].Ports))
+			}
+
+			probes, _ := getNetworkPolicyByName(networkPolicies, AllowProbesID)
+			ports := probes.Spec.Ingress[0].Ports
+			if ports[0].Port.IntValue() != 23005 || ports[1].Port.IntValue() != int(key.ShutdownDeferrerListenPort(*testKVMConfig(nil))) {
+				t.Fatalf("expected liveness and shutdown-deferrer ports, got %v and %v", ports[0].Port, ports[1].Port)
+			}
+		})
+	}
+}
+
+func Test_Resource_NetworkPolicy_GetDesiredState_allowedTraffic(t *testing.T) {
+	master := map[string]string{key.LabelApp: key.MasterID, "cluster": "al9qy"}
+	worker := map[string]string{key.LabelApp: key.WorkerID, "cluster": "al9qy"}
+
+	testCases := []struct {
+		name          string
+		fromNamespace map[string]string
+		fromPod       map[string]string
+		to            map[string]string
+		port          int
+		expected      bool
+	}{
+		{
+			name:     "case 0: masters reach the kubelets of workers",
+			fromPod:  master,
+			to:       worker,
+			port:     10250,
+			expected: true,
+		},
+		{
+			name:     "case 1: workers reach the API servers",
+			fromPod:  worker,
+			to:       master,
+			port:     443,
+			expected: true,
+		},
+		{
+			name:     "case 2: workers reach each other",
+			fromPod:  worker,
+			to:       worker,
+			port:     4789,
+			expected: true,
+		},
+		{
+			name:     "case 3: masters reach each other",
+			fromPod:  master,
+			to:       master,
+			port:     2380,
+			expected: true,
+		},
+		{
+			name:          "case 4: monitoring scrapes the masters",
+			fromNamespace: map[string]string{"kubernetes.io/metadata.name": "monitoring"},
+			fromPod:       map[string]string{"app": "prometheus"},
+			to:            master,
+			port:          30010,
+			expected:      true,
+		},
+		{
+			name:          "case 5: other namespaces do not reach the kubelets of workers",
+			fromNamespace: map[string]string{"kubernetes.io/metadata.name": "default"},
+			fromPod:       map[string]string{"app": "test"},
+			to:            worker,
+			port:          10250,
+			expected:      false,
+		},
+		{
+			name:     "case 6: pods of other clusters do not reach the kubelets of workers",
+			fromPod:  map[string]string{key.LabelApp: key.MasterID, "cluster": "b2bbb"},
+			to:       worker,
+			port:     10250,
+			expected: false,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			r := testResource(t, fake.NewSimpleClientset())
+
+			networkPolicies, err := r.newNetworkPolicies(*testKVMConfig(nil))
+			if err != nil {
+				t.Fatalf("expected nil, got %#v", err)
+			}
+
+			allowed := isIngressAllowed(t, networkPolicies, tc.fromNamespace, tc.fromPod, tc.to, tc.port)
+			if allowed != tc.expected {
+				t.Fatalf("expected allowed %t, got %t", tc.expected, allowed)
+			}
+		})
+	}
+}
+
+// isIngressAllowed returns true in case one of the given network policies
+// allows TCP traffic from the given pod to the given port of a pod within the
+// namespace of the network policies. Traffic from within the namespace is
+// given by a nil namespace.
+func isIngressAllowed(t *testing.T, networkPolicies []*networkingv1.NetworkPolicy, fromNamespace, fromPod, to map[string]string, port int) bool {
+	matches := func(selector *metav1.LabelSelector, l map[string]string) bool {
+		s, err := metav1.LabelSelectorAsSelector(selector)
+		if err != nil {
+			t.Fatalf("expected nil, got %#v", err)
+		}
+		return s.Matches(labels.Set(l))
+	}
+
+	for _, networkPolicy := range networkPolicies {
+		if !matches(&networkPolicy.Spec.PodSelector, to) {
+			continue
+		}
+
+		for _, rule := range networkPolicy.Spec.Ingress {
+			portAllowed := len(rule.Ports) == 0
+			for _, p := range rule.Ports {
+				if p.Port.IntValue() == port {
+					portAllowed = true
+				}
+			}
+
+			peerAllowed := len(rule.From) == 0
+			for _, peer := range rule.From {
+				switch {
+				case peer.NamespaceSelector != nil:
+					if fromNamespace != nil && matches(peer.NamespaceSelector, fromNamespace) && (peer.PodSelector == nil || matches(peer.PodSelector, fromPod)) {
+						peerAllowed = true
+					}
+				case peer.PodSelector != nil:
+					if fromNamespace == nil && matches(peer.PodSelector, fromPod) {
+						peerAllowed = true
+					}
+				}
+			}
+
+			if portAllowed && peerAllowed {
+				return true
+			}
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,152 @@
+package networkpolicy
+
+import (
+	"reflect"
+
+	"github.com/giantswarm/microerror"
+	"github.com/giantswarm/micrologger"
+	networkingv1 "k8s.io/api/networking/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+)
+
+const (
+	// Name is the identifier of the resource.
+	Name = "networkpolicy"
+)
+
+const (
+	AllowClusterID           = "allow-cluster"
+	AllowIngressControllerID = "allow-ingress-controller"
+	AllowListID              = "allow-list"
+	AllowMonitoringID        = "allow-monitoring"
+	AllowProbesID            = "allow-probes"
+	AllowWorkerIngressID     = "allow-worker-ingress"
+	DefaultDenyID            = "default-deny"
+)
+
+// Config represents the configuration used to create a new network policy
+// resource.
+type Config struct {
+	// Dependencies.
+	K8sClient kubernetes.Interface
+	Logger    micrologger.Logger
+
+	// Settings.
+
+	// IngressControllerSelector is the label selector of the namespaces the
+	// ingress controller exposing the masters runs in, e.g.
+	// kubernetes.io/metadata.name=kube-system.
+	IngressControllerSelector string
+	// MonitoringSelector is the label selector of the namespaces the
+	// monitoring scraping the VM pods runs in, e.g.
+	// kubernetes.io/metadata.name=monitoring.
+	MonitoringSelector string
+}
+
+// Resource implements the network policy resource.
+type Resource struct {
+	// Dependencies.
+	k8sClient kubernetes.Interface
+	logger    micrologger.Logger
+
+	// Settings.
+	ingressControllerSelector *metav1.LabelSelector
+	monitoringSelector        *metav1.LabelSelector
+}
+
+// New creates a new configured network policy resource.
+func New(config Config) (*Resource, error) {
+	// Dependencies.
+	if config.K8sClient == nil {
+		return nil, microerror.Maskf(invalidConfigError, "%T.K8sClient must not be empty", config)
+	}
+	if config.Logger == nil {
+		return nil, microerror.Maskf(invalidConfigError, "%T.Logger must not be empty", config)
+	}
+
+	// Settings.
+	if config.IngressControllerSelector == "" {
+		return nil, microerror.Maskf(invalidConfigError, "%T.IngressControllerSelector must not be empty", config)
+	}
+	if config.MonitoringSelector == "" {
+		return nil, microerror.Maskf(invalidConfigError, "%T.MonitoringSelector must not be empty", config)
+	}
+
+	ingressControllerSelector, err := parseLabelSelector(config.IngressControllerSelector)
+	if err != nil {
+		return nil, microerror.Maskf(invalidConfigError, "%T.IngressControllerSelector must be a label selector: %s", config, err)
+	}
+	monitoringSelector, err := parseLabelSelector(config.MonitoringSelector)
+	if err != nil {
+		return nil, microerror.Maskf(invalidConfigError, "%T.MonitoringSelector must be a label selector: %s", config, err)
+	}
+
+	r := &Resource{
+		// Dependencies.
+		k8sClient: config.K8sClient,
+		logger:    config.Logger,
+
+		// Settings.
+		ingressControllerSelector: ingressControllerSelector,
+		monitoringSelector:        monitoringSelector,
+	}
+
+	return r, nil
+}
+
+func (r *Resource) Name() string {
+	return Name
+}
+
+func containsNetworkPolicy(list []*networkingv1.NetworkPolicy, item *networkingv1.NetworkPolicy) bool {
+	_, err := getNetworkPolicyByName(list, item.Name)
+	if IsNotFound(err) {
+		return false
+	} else if err != nil {
+		return false
+	}
+
+	return true
+}
+
+func getNetworkPolicyByName(list []*networkingv1.NetworkPolicy, name string) (*networkingv1.NetworkPolicy, error) {
+	for _, l := range list {
+		if l.Name == name {
+			return l, nil
+		}
+	}
+
+	return nil, microerror.Maskf(notFoundError, "network policy '%s' not found", name)
+}
+
+func isNetworkPolicyModified(a, b *networkingv1.NetworkPolicy) bool {
+	return !reflect.DeepEqual(a.Labels, b.Labels) || !reflect.DeepEqual(a.Spec, b.Spec)
+}
+
+func parseLabelSelector(selector string) (*metav1.LabelSelector, error) {
+	labelSelector, err := metav1.ParseToLabelSelector(selector)
+	if err != nil {
+		return nil, microerror.Mask(err)
+	}
+	// The Kubernetes API drops empty match expressions, which would otherwise
+	// cause an update of the network policies on every reconciliation.
+	if len(labelSelector.MatchExpressions) == 0 {
+		labelSelector.MatchExpressions = nil
+	}
+
+	return labelSelector, nil
+}
+
+func toNetworkPolicies(v interface{}) ([]*networkingv1.NetworkPolicy, error) {
+	if v == nil {
+		return nil, nil
+	}
+
+	networkPolicies, ok := v.([]*networkingv1.NetworkPolicy)
+	if !ok {
+		return nil, microerror.Maskf(wrongTypeError, "expected '%T', got '%T'", []*networkingv1.NetworkPolicy{}, v)
+	}
+
+	return networkPolicies, nil
+}
@@ -0,0 +1,100 @@
+package networkpolicy
+
+import (
+	"context"
+
+	"github.com/giantswarm/microerror"
+	"github.com/giantswarm/operatorkit/v5/pkg/resource/crud"
+	networkingv1 "k8s.io/api/networking/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func (r *Resource) ApplyUpdateChange(ctx context.Context, obj, updateChange interface{}) error {
+	networkPoliciesToUpdate, err := toNetworkPolicies(updateChange)
+	if err != nil {
+		return microerror.Mask(err)
+	}
+
+	if len(networkPoliciesToUpdate) != 0 {
+		r.logger.Debugf(ctx, "updating the network policies in the Kubernetes API")
+
+		for _, networkPolicy := range networkPoliciesToUpdate {
+			_, err := r.k8sClient.NetworkingV1().NetworkPolicies(networkPolicy.Namespace).Update(ctx, networkPolicy, metav1.UpdateOptions{})
+			if apierrors.IsConflict(err) || apierrors.IsNotFound(err) {
+				// fall through, the network policy is updated with the next
+				// reconciliation
+			} else if err != nil {
+				return microerror.Mask(err)
+			}
+		}
+
+		r.logger.Debugf(ctx, "updated the network policies in the Kubernetes API")
+	} else {
+		r.logger.Debugf(ctx, "the network policies do not need to be updated in the Kubernetes API")
+	}
+
+	return nil
+}
+
+func (r *Resource) NewUpdatePatch(ctx context.Context, obj, currentState, desiredState interface{}) (*crud.Patch, error) {
+	create, err := r.newCreateChange(ctx, obj, currentState, desiredState)
+	if err != nil {
+		return nil, microerror.Mask(err)
+	}
+
+	obsolete, err := r.newDeleteChange(ctx, obj, currentState, desiredState)
+	if err != nil {
+		return nil, microerror.Mask(err)
+	}
+
+	update, err := r.newUpdateChange(ctx, obj, currentState, desiredState)
+	if err != nil {
+		return nil, microerror.Mask(err)
+	}
+
+	patch := crud.NewPatch()
+	patch.SetCreateChange(create)
+	patch.SetDeleteChange(obsolete)
+	patch.SetUpdateChange(update)
+
+	return patch, nil
+}
+
+func (r *Resource) newUpdateChange(ctx context.Context, obj, currentState, desiredState interface{}) (interface{}, error) {
+	currentNetworkPolicies, err := toNetworkPolicies(currentState)
+	if err != nil {
+		return nil, microerror.Mask(err)
+	}
+	desiredNetworkPolicies, err := toNetworkPolicies(desiredState)
+	if err != nil {
+		return nil, microerror.Mask(err)
+	}
+
+	r.logger.Debugf(ctx, "finding out which network policies have to be updated")
+
+	var networkPoliciesToUpdate []*networkingv1.NetworkPolicy
+
+	for _, currentNetworkPolicy := range currentNetworkPolicies {
+		desiredNetworkPolicy, err := getNetworkPolicyByName(desiredNetworkPolicies, currentNetworkPolicy.Name)
+		if IsNotFound(err) {
+			continue
+		} else if err != nil {
+			return nil, microerror.Mask(err)
+		}
+
+		if !isNetworkPolicyModified(desiredNetworkPolicy, currentNetworkPolicy) {
+			continue
+		}
+
+		networkPolicyToUpdate := desiredNetworkPolicy.DeepCopy()
+		networkPolicyToUpdate.ResourceVersion = currentNetworkPolicy.ResourceVersion
+		networkPoliciesToUpdate = append(networkPoliciesToUpdate, networkPolicyToUpdate)
+
+		r.logger.Debugf(ctx, "found network policy %#q that has to be updated", networkPolicyToUpdate.Name)
+	}
+
+	r.logger.Debugf(ctx, "found %d network policies that have to be updated", len(networkPoliciesToUpdate))
+
+	return networkPoliciesToUpdate, nil
+}
@@ -0,0 +1,102 @@
+package networkpolicy
+
+import (
+	"context"
+	"testing"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+
+	"github.com/giantswarm/kvm-operator/v4/service/controller/key"
+)
+
+func Test_Resource_NetworkPolicy_update(t *testing.T) {
+	k8sClient := fake.NewSimpleClientset()
+	r := testResource(t, k8sClient)
+
+	reconcile := func(annotations map[string]string) {
+		customObject := testKVMConfig(annotations)
+
+		current, err := r.GetCurrentState(context.TODO(), customObject)
+		if err != nil {
+			t.Fatalf("expected nil, got %#v", err)
+		}
+		desired, err := r.GetDesiredState(context.TODO(), customObject)
+		if err != nil {
+			t.Fatalf("expected nil, got %#v", err)
+		}
+
+		create, err := r.newCreateChange(context.TODO(), customObject, current, desired)
+		if err != nil {
+			t.Fatalf("expected nil, got %#v", err)
+		}
+		obsolete, err := r.newDeleteChange(context.TODO(), customObject, current, desired)
+		if err != nil {
+			t.Fatalf("expected nil, got %#v", err)
+		}
+		update, err := r.newUpdateChange(context.TODO(), customObject, current, desired)
+		if err != nil {
+			t.Fatalf("expected nil, got %#v", err)
+		}
+
+		err = r.ApplyCreateChange(context.TODO(), customObject, create)
+		if err != nil {
+			t.Fatalf("expected nil, got %#v", err)
+		}
+		err = r.ApplyDeleteChange(context.TODO(), customObject, obsolete)
+		if err != nil {
+			t.Fatalf("expected nil, got %#v", err)
+		}
+		err = r.ApplyUpdateChange(context.TODO(), customObject, update)
+		if err != nil {
+			t.Fatalf("expected nil, got %#v", err)
+		}
+	}
+
+	assertNetworkPolicies := func(expected int) {
+		list, err := k8sClient.NetworkingV1().NetworkPolicies("al9qy").List(context.TODO(), metav1.ListOptions{})
+		if err != nil {
+			t.Fatalf("expected nil, got %#v", err)
+		}
+		if len(list.Items) != expected {
+			t.Fatalf("expected %d network policies, got %d", expected, len(list.Items))
+		}
+	}
+
+	withAllowList := map[string]string{
+		key.AnnotationNetworkPolicyAllowList: `[{"from":[{"ipBlock":{"cidr":"10.0.0.0/8"}}],"ports":[{"port":22}]}]`,
+	}
+
+	reconcile(withAllowList)
+	assertNetworkPolicies(7)
+
+	// The allow-list defaults the protocol of its ports, so an unchanged
+	// cluster must not cause any update.
+	{
+		customObject := testKVMConfig(withAllowList)
+
+		current, err := r.GetCurrentState(context.TODO(), customObject)
+		if err != nil {
+			t.Fatalf("expected nil, got %#v", err)
+		}
+		desired, err := r.GetDesiredState(context.TODO(), customObject)
+		if err != nil {
+			t.Fatalf("expected nil, got %#v", err)
+		}
+		update, err := r.newUpdateChange(context.TODO(), customObject, current, desired)
+		if err != nil {
+			t.Fatalf("expected nil, got %#v", err)
+		}
+		networkPolicies, err := toNetworkPolicies(update)
+		if err != nil {
+			t.Fatalf("expected nil, got %#v", err)
+		}
+		if len(networkPolicies) != 0 {
+			t.Fatalf("expected 0 network policies to update, got %d", len(networkPolicies))
+		}
+	}
+
+	// Removing the allow-list deletes its network policy.
+	reconcile(nil)
+	assertNetworkPolicies(6)
+}
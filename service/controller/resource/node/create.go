@@ -102,6 +102,14 @@ func (r *Resource) EnsureCreated(ctx context.Context, obj interface{}) error {
 		r.logger.Debugf(ctx, "deleted node '%s' in the workload cluster's Kubernetes API", n.GetName())
 	}
 
+	// The other way around, management cluster pods whose VM never registered a
+	// node in the workload cluster's Kubernetes API are reported and optionally
+	// recycled.
+	err = r.ensureOrphanPods(ctx, pods, nodes)
+	if err != nil {
+		return microerror.Mask(err)
+	}
+
 	return nil
 }
 
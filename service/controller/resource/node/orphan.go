@@ -0,0 +1,188 @@
+package node
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/giantswarm/microerror"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+
+	"github.com/giantswarm/kvm-operator/v4/service/controller/key"
+)
+
+const (
+	eventReasonOrphanPod         = "OrphanPod"
+	eventReasonOrphanPodRecycled = "OrphanPodRecycled"
+)
+
+// ensureOrphanPods reports VM pods which have been running for longer than
+// the grace period without a workload cluster node registering for their VM.
+// Orphaned pods get the key.WorkloadClusterNodeRegistered condition set to
+// false and a warning event. When recycling is enabled orphaned pods are
+// deleted, at most the configured number per cluster at once.
+func (r *Resource) ensureOrphanPods(ctx context.Context, pods []corev1.Pod, nodes []corev1.Node) error {
+	orphans, registered := findOrphanPods(pods, nodes, time.Now(), r.orphanPodGracePeriod)
+
+	for _, p := range registered {
+		condition := corev1.PodCondition{
+			Type:   key.WorkloadClusterNodeRegistered,
+			Status: corev1.ConditionTrue,
+			Reason: "NodeRegistered",
+		}
+
+		_, err := r.ensurePodCondition(ctx, p, condition)
+		if err != nil {
+			return microerror.Mask(err)
+		}
+	}
+
+	for _, p := range orphans {
+		condition := corev1.PodCondition{
+			Type:    key.WorkloadClusterNodeRegistered,
+			Status:  corev1.ConditionFalse,
+			Reason:  "NodeNotRegistered",
+			Message: fmt.Sprintf("no workload cluster node registered within %s", r.orphanPodGracePeriod),
+		}
+
+		transitioned, err := r.ensurePodCondition(ctx, p, condition)
+		if err != nil {
+			return microerror.Mask(err)
+		}
+		if transitioned {
+			r.logger.Debugf(ctx, "pod %#q is orphaned", p.GetName())
+			r.eventRecorder.Eventf(&p, corev1.EventTypeWarning, eventReasonOrphanPod, "no workload cluster node registered within %s", r.orphanPodGracePeriod)
+		}
+	}
+
+	if !r.orphanPodRecycle || len(orphans) == 0 {
+		return nil
+	}
+
+	for _, p := range podsToRecycle(pods, orphans, r.orphanPodMaxRecycled) {
+		r.logger.Debugf(ctx, "deleting orphaned pod %#q", p.GetName())
+
+		err := r.k8sClient.CoreV1().Pods(p.GetNamespace()).Delete(ctx, p.GetName(), metav1.DeleteOptions{})
+		if errors.IsNotFound(err) {
+			continue
+		} else if err != nil {
+			return microerror.Mask(err)
+		}
+
+		r.eventRecorder.Eventf(&p, corev1.EventTypeWarning, eventReasonOrphanPodRecycled, "deleted pod since no workload cluster node registered within %s", r.orphanPodGracePeriod)
+		r.logger.Debugf(ctx, "deleted orphaned pod %#q", p.GetName())
+	}
+
+	return nil
+}
+
+// ensurePodCondition patches the given condition into the status of the given
+// pod in case its status changed. True is returned in case the pod has been
+// patched.
+func (r *Resource) ensurePodCondition(ctx context.Context, pod corev1.Pod, condition corev1.PodCondition) (bool, error) {
+	current, ok := key.FindPodCondition(pod, condition.Type)
+	if ok && current.Status == condition.Status {
+		return false, nil
+	}
+	condition.LastTransitionTime = metav1.Now()
+
+	conditionJSON, err := json.Marshal(condition)
+	if err != nil {
+		return false, microerror.Mask(err)
+	}
+	patch := []byte(fmt.Sprintf("{\"status\":{\"conditions\":[%s]}}", conditionJSON))
+
+	_, err = r.k8sClient.CoreV1().Pods(pod.GetNamespace()).Patch(ctx, pod.GetName(), types.StrategicMergePatchType, patch, metav1.PatchOptions{}, "status")
+	if errors.IsNotFound(err) {
+		return false, nil
+	} else if err != nil {
+		return false, microerror.Mask(err)
+	}
+
+	return true, nil
+}
+
+// findOrphanPods returns the running VM pods without registered workload
+// cluster node which started before the grace period, as well as the VM pods
+// whose node is registered.
+func findOrphanPods(pods []corev1.Pod, nodes []corev1.Node, now time.Time, gracePeriod time.Duration) ([]corev1.Pod, []corev1.Pod) {
+	var orphans []corev1.Pod
+	var registered []corev1.Pod
+	for _, p := range pods {
+		if !isVMPod(p) || p.GetDeletionTimestamp() != nil {
+			continue
+		}
+
+		if doesPodExistAsNode(nodes, p) {
+			registered = append(registered, p)
+			continue
+		}
+
+		if p.Status.Phase != corev1.PodRunning {
+			continue
+		}
+		if now.Sub(podStartTime(p)) < gracePeriod {
+			continue
+		}
+
+		orphans = append(orphans, p)
+	}
+
+	return orphans, registered
+}
+
+// podsToRecycle returns the orphaned pods to be deleted, oldest first. Pods
+// already being deleted count against the maximum.
+func podsToRecycle(pods []corev1.Pod, orphans []corev1.Pod, maxRecycled int) []corev1.Pod {
+	inFlight := 0
+	for _, p := range pods {
+		if isVMPod(p) && p.GetDeletionTimestamp() != nil {
+			inFlight++
+		}
+	}
+
+	n := maxRecycled - inFlight
+	if n <= 0 {
+		return nil
+	}
+
+	sorted := append([]corev1.Pod{}, orphans...)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return podStartTime(sorted[i]).Before(podStartTime(sorted[j]))
+	})
+	if len(sorted) > n {
+		sorted = sorted[:n]
+	}
+
+	return sorted
+}
+
+func doesPodExistAsNode(nodes []corev1.Node, p corev1.Pod) bool {
+	for _, n := range nodes {
+		if n.GetName() == p.GetName() {
+			return true
+		}
+	}
+
+	return false
+}
+
+// isVMPod returns true for master and worker pods, which run the VMs of the
+// workload cluster nodes.
+func isVMPod(p corev1.Pod) bool {
+	_, ok := p.GetLabels()["node"]
+	return ok
+}
+
+func podStartTime(p corev1.Pod) time.Time {
+	if p.Status.StartTime != nil {
+		return p.Status.StartTime.Time
+	}
+
+	return p.GetCreationTimestamp().Time
+}
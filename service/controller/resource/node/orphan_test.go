@@ -0,0 +1,177 @@
+package node
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/giantswarm/micrologger/microloggertest"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+	"k8s.io/client-go/tools/record"
+
+	"github.com/giantswarm/kvm-operator/v4/service/controller/key"
+)
+
+func testPod(name string, phase corev1.PodPhase, started time.Time, deleting bool) corev1.Pod {
+	p := corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: "al9qy",
+			Labels: map[string]string{
+				"node": name,
+			},
+		},
+		Status: corev1.PodStatus{
+			Phase:     phase,
+			StartTime: &metav1.Time{Time: started},
+		},
+	}
+	if deleting {
+		p.DeletionTimestamp = &metav1.Time{Time: started}
+	}
+
+	return p
+}
+
+func testNode(name string) corev1.Node {
+	return corev1.Node{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: name,
+		},
+	}
+}
+
+func podNames(pods []corev1.Pod) []string {
+	var names []string
+	for _, p := range pods {
+		names = append(names, p.GetName())
+	}
+
+	return names
+}
+
+func Test_findOrphanPods(t *testing.T) {
+	now := time.Now()
+
+	pods := []corev1.Pod{
+		testPod("registered", corev1.PodRunning, now.Add(-time.Hour), false),
+		testPod("orphan", corev1.PodRunning, now.Add(-time.Hour), false),
+		testPod("booting", corev1.PodRunning, now.Add(-time.Minute), false),
+		testPod("pending", corev1.PodPending, now.Add(-time.Hour), false),
+		testPod("deleting", corev1.PodRunning, now.Add(-time.Hour), true),
+		{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: "no-vm",
+			},
+			Status: corev1.PodStatus{
+				Phase: corev1.PodRunning,
+			},
+		},
+	}
+	nodes := []corev1.Node{
+		testNode("registered"),
+	}
+
+	orphans, registered := findOrphanPods(pods, nodes, now, 30*time.Minute)
+
+	if names := podNames(orphans); len(names) != 1 || names[0] != "orphan" {
+		t.Fatalf("expected orphans %#v got %#v", []string{"orphan"}, names)
+	}
+	if names := podNames(registered); len(names) != 1 || names[0] != "registered" {
+		t.Fatalf("expected registered %#v got %#v", []string{"registered"}, names)
+	}
+}
+
+func Test_podsToRecycle(t *testing.T) {
+	now := time.Now()
+
+	older := testPod("older", corev1.PodRunning, now.Add(-2*time.Hour), false)
+	newer := testPod("newer", corev1.PodRunning, now.Add(-time.Hour), false)
+	deleting := testPod("deleting", corev1.PodRunning, now.Add(-time.Hour), true)
+
+	testCases := []struct {
+		name        string
+		pods        []corev1.Pod
+		maxRecycled int
+		expected    []string
+	}{
+		{
+			name:        "case 0: oldest orphans are recycled first",
+			pods:        []corev1.Pod{newer, older},
+			maxRecycled: 1,
+			expected:    []string{"older"},
+		},
+		{
+			name:        "case 1: pods being deleted count against the maximum",
+			pods:        []corev1.Pod{newer, older, deleting},
+			maxRecycled: 2,
+			expected:    []string{"older"},
+		},
+		{
+			name:        "case 2: nothing is recycled when the maximum is reached",
+			pods:        []corev1.Pod{newer, older, deleting},
+			maxRecycled: 1,
+			expected:    nil,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			result := podNames(podsToRecycle(tc.pods, []corev1.Pod{newer, older}, tc.maxRecycled))
+
+			if len(result) != len(tc.expected) {
+				t.Fatalf("expected %#v got %#v", tc.expected, result)
+			}
+			for i := range result {
+				if result[i] != tc.expected[i] {
+					t.Fatalf("expected %#v got %#v", tc.expected, result)
+				}
+			}
+		})
+	}
+}
+
+func Test_Resource_ensureOrphanPods(t *testing.T) {
+	now := time.Now()
+
+	orphan := testPod("orphan", corev1.PodRunning, now.Add(-time.Hour), false)
+	registered := testPod("registered", corev1.PodRunning, now.Add(-time.Hour), false)
+
+	k8sClient := fake.NewSimpleClientset(&orphan, &registered)
+	recorder := record.NewFakeRecorder(10)
+
+	r := &Resource{
+		eventRecorder: recorder,
+		k8sClient:     k8sClient,
+		logger:        microloggertest.New(),
+
+		orphanPodGracePeriod: 30 * time.Minute,
+		orphanPodMaxRecycled: 1,
+		orphanPodRecycle:     true,
+	}
+
+	err := r.ensureOrphanPods(context.TODO(), []corev1.Pod{orphan, registered}, []corev1.Node{testNode("registered")})
+	if err != nil {
+		t.Fatalf("expected nil, got %#v", err)
+	}
+
+	p, err := k8sClient.CoreV1().Pods("al9qy").Get(context.TODO(), "registered", metav1.GetOptions{})
+	if err != nil {
+		t.Fatalf("expected nil, got %#v", err)
+	}
+	condition, ok := key.FindPodCondition(*p, key.WorkloadClusterNodeRegistered)
+	if !ok || condition.Status != corev1.ConditionTrue {
+		t.Fatalf("expected registered condition to be true, got %#v", p.Status.Conditions)
+	}
+
+	_, err = k8sClient.CoreV1().Pods("al9qy").Get(context.TODO(), "orphan", metav1.GetOptions{})
+	if err == nil {
+		t.Fatalf("expected orphaned pod to be deleted")
+	}
+
+	if len(recorder.Events) != 2 {
+		t.Fatalf("expected 2 events, got %d", len(recorder.Events))
+	}
+}
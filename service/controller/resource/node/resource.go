@@ -1,10 +1,13 @@
 package node
 
 import (
+	"time"
+
 	"github.com/giantswarm/microerror"
 	"github.com/giantswarm/micrologger"
 	workloadcluster "github.com/giantswarm/tenantcluster/v4/pkg/tenantcluster"
 	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/tools/record"
 )
 
 const (
@@ -12,18 +15,36 @@ const (
 )
 
 type Config struct {
+	EventRecorder   record.EventRecorder
 	K8sClient       kubernetes.Interface
 	Logger          micrologger.Logger
 	WorkloadCluster workloadcluster.Interface
+
+	// OrphanPodGracePeriod is the duration after which running VM pods without
+	// registered workload cluster node are reported as orphaned.
+	OrphanPodGracePeriod time.Duration
+	// OrphanPodMaxRecycled is the maximum number of orphaned VM pods of a
+	// cluster being deleted at once.
+	OrphanPodMaxRecycled int
+	// OrphanPodRecycle defines whether orphaned VM pods are deleted.
+	OrphanPodRecycle bool
 }
 
 type Resource struct {
+	eventRecorder   record.EventRecorder
 	k8sClient       kubernetes.Interface
 	logger          micrologger.Logger
 	workloadCluster workloadcluster.Interface
+
+	orphanPodGracePeriod time.Duration
+	orphanPodMaxRecycled int
+	orphanPodRecycle     bool
 }
 
 func New(config Config) (*Resource, error) {
+	if config.EventRecorder == nil {
+		return nil, microerror.Maskf(invalidConfigError, "%T.EventRecorder must not be empty", config)
+	}
 	if config.K8sClient == nil {
 		return nil, microerror.Maskf(invalidConfigError, "%T.K8sClient must not be empty", config)
 	}
@@ -34,10 +55,22 @@ func New(config Config) (*Resource, error) {
 		return nil, microerror.Maskf(invalidConfigError, "%T.WorkloadCluster must not be empty", config)
 	}
 
+	if config.OrphanPodGracePeriod <= 0 {
+		return nil, microerror.Maskf(invalidConfigError, "%T.OrphanPodGracePeriod must be positive", config)
+	}
+	if config.OrphanPodMaxRecycled < 0 {
+		return nil, microerror.Maskf(invalidConfigError, "%T.OrphanPodMaxRecycled must not be negative", config)
+	}
+
 	r := &Resource{
+		eventRecorder:   config.EventRecorder,
 		k8sClient:       config.K8sClient,
 		logger:          config.Logger,
 		workloadCluster: config.WorkloadCluster,
+
+		orphanPodGracePeriod: config.OrphanPodGracePeriod,
+		orphanPodMaxRecycled: config.OrphanPodMaxRecycled,
+		orphanPodRecycle:     config.OrphanPodRecycle,
 	}
 
 	return r, nil
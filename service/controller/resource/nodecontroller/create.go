@@ -3,17 +3,18 @@ package nodecontroller
 import (
 	"context"
 	"fmt"
-	"time"
 
 	workloaderrors "github.com/giantswarm/errors/tenant"
 	"github.com/giantswarm/k8sclient/v5/pkg/k8sclient"
 	"github.com/giantswarm/microerror"
 	workloadcluster "github.com/giantswarm/tenantcluster/v4/pkg/tenantcluster"
 	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/selection"
 
 	"github.com/giantswarm/kvm-operator/v4/pkg/label"
 	"github.com/giantswarm/kvm-operator/v4/pkg/project"
 	"github.com/giantswarm/kvm-operator/v4/service/controller/internal/nodecontroller"
+	"github.com/giantswarm/kvm-operator/v4/service/controller/internal/workloadinformer"
 	"github.com/giantswarm/kvm-operator/v4/service/controller/key"
 )
 
@@ -23,80 +24,93 @@ func (r *Resource) EnsureCreated(ctx context.Context, obj interface{}) error {
 		return microerror.Mask(err)
 	}
 
-	r.logger.Debugf(ctx, "ensuring controller is running for workload cluster")
+	r.logger.Debugf(ctx, "ensuring node informer is running for workload cluster")
 
-	clusterID := key.ClusterID(cr)
-	controllerKey := controllerMapKey(cr)
-
-	var shouldStop bool
 	var k8sClient *k8sclient.Clients
 	{
 		k8sClient, err = key.CreateK8sClientForWorkloadCluster(ctx, cr, r.logger, r.workloadCluster)
 		if workloadcluster.IsTimeout(err) {
 			r.logger.Debugf(ctx, "waiting for certificates timed out")
-			shouldStop = true
 		} else if workloaderrors.IsAPINotAvailable(err) || k8sclient.IsTimeout(err) {
 			r.logger.Debugf(ctx, "workload cluster is not available")
-			shouldStop = true
 		} else if err != nil {
 			return microerror.Mask(err)
 		}
 	}
 
-	var desiredController *nodecontroller.Controller
+	// A running informer keeps retrying with backoff on its own while the
+	// workload cluster is not available, so it is only replaced once a client
+	// could be created.
 	if k8sClient != nil {
 		config := nodecontroller.Config{
 			Cluster:             cr,
 			ManagementK8sClient: r.k8sClient,
-			WorkloadK8sClient:   k8sClient,
 			Logger:              r.logger,
-			Name:                fmt.Sprintf("%s-%s-nodes", project.Name(), clusterID),
-			Selector: labels.SelectorFromSet(map[string]string{
-				// When managing endpoints, we only consider node- and pod-readiness for workers. Master endpoint IPs
-				// are always present in the master endpoints object. This may change when we implement HA for KVM.
-				"role":                key.WorkerID,
-				label.OperatorVersion: project.Version(),
-			}),
+			WorkloadK8sClient:   k8sClient.K8sClient(),
+			Name:                fmt.Sprintf("%s-%s-nodes", project.Name(), key.ClusterID(cr)),
 		}
-		desiredController, err = nodecontroller.New(config)
+		handler, err := nodecontroller.New(config)
 		if err != nil {
 			return microerror.Mask(err)
 		}
-	}
 
-	// Defer all changes to the controller map until the end of this function so we can wrap in a mutex
-	r.controllerMutex.Lock()
-	defer r.controllerMutex.Unlock()
-
-	if current, ok := r.controllers[controllerKey]; ok {
-		if !current.Equal(desiredController) {
-			r.logger.Debugf(ctx, "controllers don't match")
-			shouldStop = true
-		} else if time.Since(current.LastReconciled()) > 2*nodecontroller.ResyncPeriod {
-			r.logger.Debugf(ctx, "controller hasn't reconciled in more than %d minutes", int(2*nodecontroller.ResyncPeriod.Minutes()))
-			shouldStop = true
+		selector, err := nodeSelector()
+		if err != nil {
+			return microerror.Mask(err)
 		}
 
-		if shouldStop {
-			r.logger.Debugf(ctx, "stopping existing controller")
-			current.Stop()
-			delete(r.controllers, controllerKey)
-		} else {
-			// Current controller matches expected and is reconciling, do nothing
-			return nil
+		cluster := workloadinformer.Cluster{
+			Handler:    handler,
+			K8sClient:  k8sClient.K8sClient(),
+			RESTConfig: k8sClient.RESTConfig(),
+			Revision:   cr.GetAnnotations()[key.AnnotationNodeLabelsAndTaints],
+			Selector:   selector,
+		}
+
+		err = r.informers.Ensure(ctx, informerKey(cr), cluster)
+		if err != nil {
+			return microerror.Mask(err)
 		}
 	}
 
-	if desiredController != nil {
-		r.logger.Debugf(ctx, "booting new controller")
-		err = desiredController.Boot()
+	health, ok := r.informers.Health(informerKey(cr))
+	if !ok {
+		err = r.updateInformerStatus(ctx, cr, informerStatusUnavailable)
 		if err != nil {
 			return microerror.Mask(err)
 		}
+	} else {
+		if health.LastError != nil && health.Status() == workloadinformer.HealthUnhealthy {
+			r.logger.Debugf(ctx, "node informer failed to talk to the workload cluster API: %s", health.LastError)
+		}
+		if health.LastPanic != nil && health.Status() == workloadinformer.HealthPanicked {
+			r.logger.Debugf(ctx, "node controller panicked: %s", health.LastPanic)
+		}
 
-		r.controllers[controllerKey] = desiredController
-		r.logger.Debugf(ctx, "controller booted and registered")
+		err = r.updateInformerStatus(ctx, cr, health.Status())
+		if err != nil {
+			return microerror.Mask(err)
+		}
 	}
 
+	r.logger.Debugf(ctx, "ensured node informer is running for workload cluster")
+
 	return nil
 }
+
+// nodeSelector selects the master and worker nodes of a workload cluster which
+// are managed by this operator version. Node readiness only affects endpoints
+// of workers, since master endpoint IPs are always present in the master
+// endpoints object. The remaining node state is mirrored for all nodes.
+func nodeSelector() (labels.Selector, error) {
+	roleRequirement, err := labels.NewRequirement("role", selection.In, []string{key.MasterID, key.WorkerID})
+	if err != nil {
+		return nil, microerror.Mask(err)
+	}
+	versionRequirement, err := labels.NewRequirement(label.OperatorVersion, selection.Equals, []string{project.Version()})
+	if err != nil {
+		return nil, microerror.Mask(err)
+	}
+
+	return labels.NewSelector().Add(*roleRequirement, *versionRequirement), nil
+}
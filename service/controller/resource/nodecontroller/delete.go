@@ -14,20 +14,12 @@ func (r *Resource) EnsureDeleted(ctx context.Context, obj interface{}) error {
 		return microerror.Mask(err)
 	}
 
-	r.logger.Debugf(ctx, "ensuring node controller is shut down")
-
-	controllerKey := controllerMapKey(cr)
-	r.controllerMutex.Lock()
-	if controller, ok := r.controllers[controllerKey]; ok {
-		controller.Stop()
-		delete(r.controllers, controllerKey)
-		r.logger.Debugf(ctx, "controller stopped and deleted")
-	} else {
-		r.logger.Debugf(ctx, "controller not found")
-	}
-	r.controllerMutex.Unlock()
+	r.logger.Debugf(ctx, "ensuring node informer is shut down")
+
+	r.informers.Remove(ctx, informerKey(cr))
+	deleteInformerMetric(cr)
 
-	r.logger.Debugf(ctx, "ensured node controller is shut down")
+	r.logger.Debugf(ctx, "ensured node informer is shut down")
 
 	return nil
 }
@@ -1,41 +1,55 @@
 package nodecontroller
 
 import (
+	"fmt"
 	"os"
 	"os/signal"
-	"sync"
 	"syscall"
 
 	"github.com/giantswarm/apiextensions/v3/pkg/apis/provider/v1alpha1"
+	"github.com/giantswarm/apiextensions/v3/pkg/clientset/versioned"
 	"github.com/giantswarm/microerror"
 	"github.com/giantswarm/micrologger"
 	workloadcluster "github.com/giantswarm/tenantcluster/v4/pkg/tenantcluster"
 	"k8s.io/apimachinery/pkg/types"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
+	"github.com/giantswarm/kvm-operator/v4/pkg/project"
 	"github.com/giantswarm/kvm-operator/v4/service/controller/internal/nodecontroller"
+	"github.com/giantswarm/kvm-operator/v4/service/controller/internal/workloadinformer"
+	"github.com/giantswarm/kvm-operator/v4/service/controller/key"
 )
 
 const (
-	Name = "nodecontroller"
+	Name = key.NodeControllerResourceName
+)
+
+const (
+	// informerWorkers is the number of workload cluster nodes reconciled in
+	// parallel across all workload clusters.
+	informerWorkers = 4
 )
 
 type Config struct {
+	G8sClient       versioned.Interface
 	K8sClient       client.Client
 	Logger          micrologger.Logger
 	WorkloadCluster workloadcluster.Interface
 }
 
 type Resource struct {
+	g8sClient       versioned.Interface
 	k8sClient       client.Client
 	logger          micrologger.Logger
 	workloadCluster workloadcluster.Interface
 
-	controllerMutex sync.Mutex // Used to protect controllers map from concurrent access
-	controllers     map[types.NamespacedName]*nodecontroller.Controller
+	informers *workloadinformer.Manager
 }
 
 func New(config Config) (*Resource, error) {
+	if config.G8sClient == nil {
+		return nil, microerror.Maskf(invalidConfigError, "%T.G8sClient must not be empty", config)
+	}
 	if config.K8sClient == nil {
 		return nil, microerror.Maskf(invalidConfigError, "%T.K8sClient must not be empty", config)
 	}
@@ -46,14 +60,35 @@ func New(config Config) (*Resource, error) {
 		return nil, microerror.Maskf(invalidConfigError, "%T.WorkloadCluster must not be empty", config)
 	}
 
+	var informers *workloadinformer.Manager
+	{
+		c := workloadinformer.Config{
+			Logger: config.Logger,
+
+			Name:         fmt.Sprintf("%s-nodes", project.Name()),
+			RestartAfter: 2 * nodecontroller.ResyncPeriod,
+			ResyncPeriod: nodecontroller.ResyncPeriod,
+			Workers:      informerWorkers,
+		}
+
+		var err error
+		informers, err = workloadinformer.New(c)
+		if err != nil {
+			return nil, microerror.Mask(err)
+		}
+	}
+
 	r := &Resource{
+		g8sClient:       config.G8sClient,
 		k8sClient:       config.K8sClient,
 		logger:          config.Logger,
 		workloadCluster: config.WorkloadCluster,
 
-		controllers: map[types.NamespacedName]*nodecontroller.Controller{},
+		informers: informers,
 	}
 
+	r.informers.Boot()
+
 	c := make(chan os.Signal, 2)
 	signal.Notify(c, os.Interrupt, syscall.SIGTERM)
 	go func() {
@@ -69,14 +104,10 @@ func (r *Resource) Name() string {
 }
 
 func (r *Resource) Stop() {
-	r.controllerMutex.Lock()
-	for _, controller := range r.controllers {
-		controller.Stop()
-	}
-	r.controllerMutex.Unlock()
+	r.informers.Stop()
 }
 
-func controllerMapKey(cluster v1alpha1.KVMConfig) types.NamespacedName {
+func informerKey(cluster v1alpha1.KVMConfig) types.NamespacedName {
 	return types.NamespacedName{
 		Namespace: cluster.Namespace,
 		Name:      cluster.Name,
@@ -0,0 +1,79 @@
+package nodecontroller
+
+import (
+	"context"
+
+	"github.com/giantswarm/apiextensions/v3/pkg/apis/provider/v1alpha1"
+	"github.com/giantswarm/microerror"
+	corev1 "k8s.io/api/core/v1"
+
+	"github.com/giantswarm/kvm-operator/v4/service/controller/internal/workloadinformer"
+	"github.com/giantswarm/kvm-operator/v4/service/controller/key"
+	"github.com/giantswarm/kvm-operator/v4/service/metric"
+)
+
+const (
+	// informerStatusUnavailable means no node informer is running because no
+	// client for the workload cluster API could be created.
+	informerStatusUnavailable = "Unavailable"
+)
+
+// informerStatuses are all statuses of the node informer of a workload
+// cluster exposed via metric.NodeControllerStateGauge.
+var informerStatuses = []string{
+	workloadinformer.HealthHealthy,
+	workloadinformer.HealthPanicked,
+	workloadinformer.HealthSyncing,
+	workloadinformer.HealthUnhealthy,
+	informerStatusUnavailable,
+}
+
+// updateInformerMetric sets the node controller state metric of the given
+// cluster to the given status.
+func updateInformerMetric(cr v1alpha1.KVMConfig, status string) {
+	for _, s := range informerStatuses {
+		var v float64
+		if s == status {
+			v = 1
+		}
+		metric.NodeControllerStateGauge.WithLabelValues(key.ClusterID(cr), key.ClusterCustomer(cr), s).Set(v)
+	}
+}
+
+// deleteInformerMetric removes the node controller state metric of the given
+// cluster.
+func deleteInformerMetric(cr v1alpha1.KVMConfig) {
+	for _, s := range informerStatuses {
+		metric.NodeControllerStateGauge.DeleteLabelValues(key.ClusterID(cr), key.ClusterCustomer(cr), s)
+	}
+}
+
+// updateInformerStatus records the health of the node informer of the given
+// cluster in its status.
+func (r *Resource) updateInformerStatus(ctx context.Context, cr v1alpha1.KVMConfig, status string) error {
+	updateInformerMetric(cr, status)
+
+	var conditions []v1alpha1.StatusClusterResourceCondition
+	for _, s := range informerStatuses {
+		condition := v1alpha1.StatusClusterResourceCondition{
+			Status: string(corev1.ConditionFalse),
+			Type:   key.NodeInformerCondition + s,
+		}
+		if s == status {
+			condition.Status = string(corev1.ConditionTrue)
+		}
+
+		conditions = append(conditions, condition)
+	}
+
+	updated, err := key.UpdateResourceStatus(ctx, r.g8sClient, cr, Name, conditions)
+	if err != nil {
+		return microerror.Mask(err)
+	}
+
+	if updated {
+		r.logger.Debugf(ctx, "updated status with node informer health %#q", status)
+	}
+
+	return nil
+}
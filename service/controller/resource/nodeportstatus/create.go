@@ -0,0 +1,227 @@
+package nodeportstatus
+
+import (
+	"context"
+
+	"github.com/giantswarm/apiextensions/v3/pkg/apis/provider/v1alpha1"
+	"github.com/giantswarm/microerror"
+	"github.com/giantswarm/operatorkit/v5/pkg/controller/context/reconciliationcanceledcontext"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/giantswarm/kvm-operator/v4/service/controller/key"
+)
+
+func (r *Resource) EnsureCreated(ctx context.Context, obj interface{}) error {
+	cr, err := key.ToCustomObject(obj)
+	if err != nil {
+		return microerror.Mask(err)
+	}
+
+	// Without node port range the Kubernetes API chooses the node ports and
+	// ClusterIP services do not use node ports at all, so allocations of
+	// previous configurations are released.
+	var allocated map[string]int32
+	if r.nodePortRange != nil && key.WorkerServiceUsesNodePorts(cr) {
+		var others []v1alpha1.KVMConfig
+		{
+			list, err := r.g8sClient.ProviderV1alpha1().KVMConfigs(metav1.NamespaceAll).List(ctx, metav1.ListOptions{})
+			if err != nil {
+				return microerror.Mask(err)
+			}
+
+			for _, other := range list.Items {
+				if key.ClusterID(other) == key.ClusterID(cr) {
+					continue
+				}
+				if !key.WorkerServiceUsesNodePorts(other) {
+					continue
+				}
+				others = append(others, other)
+			}
+		}
+
+		current, err := r.k8sClient.CoreV1().Services(key.ClusterID(cr)).Get(ctx, key.WorkerID, metav1.GetOptions{})
+		if apierrors.IsNotFound(err) {
+			current = nil
+		} else if err != nil {
+			return microerror.Mask(err)
+		}
+
+		allocated, err = r.allocateNodePorts(ctx, cr, others, current)
+		if err != nil {
+			return microerror.Mask(err)
+		}
+	}
+
+	{
+		r.logger.Debugf(ctx, "updating node ports")
+
+		updated, err := key.UpdateStatusAnnotation(ctx, r.g8sClient, cr, key.AnnotationAllocatedNodePorts, allocated)
+		if err != nil {
+			return microerror.Mask(err)
+		}
+
+		if updated {
+			r.logger.Debugf(ctx, "updated node ports")
+
+			r.logger.Debugf(ctx, "canceling reconciliation")
+			reconciliationcanceledcontext.SetCanceled(ctx)
+
+			return nil
+		} else {
+			r.logger.Debugf(ctx, "did not update node ports")
+		}
+	}
+
+	return nil
+}
+
+// allocateNodePorts assigns node ports to the port mappings of the given
+// KVMConfig which do not define one. Previous allocations are kept as long as
+// they do not conflict with any other cluster. Node ports of an existing
+// worker service are adopted so that migrated clusters keep their ports.
+// Ports requested explicitly or allocated by any other cluster are never
+// taken. Only when two clusters persisted the same allocation before either
+// worker service uses it, the conflict is resolved in favour of the cluster
+// with the lower ID.
+func (r *Resource) allocateNodePorts(ctx context.Context, cr v1alpha1.KVMConfig, others []v1alpha1.KVMConfig, current *corev1.Service) (map[string]int32, error) {
+	reserved := map[int32]string{}
+	allocatedByOthers := map[int32]string{}
+
+	for _, p := range cr.Spec.KVM.PortMappings {
+		if p.NodePort != 0 {
+			reserved[int32(p.NodePort)] = key.ClusterID(cr)
+		}
+	}
+	for _, other := range others {
+		for _, p := range other.Spec.KVM.PortMappings {
+			if p.NodePort != 0 {
+				reserved[int32(p.NodePort)] = key.ClusterID(other)
+			}
+		}
+		for _, port := range key.AllocatedNodePorts(other) {
+			allocatedByOthers[port] = key.ClusterID(other)
+		}
+	}
+
+	currentPorts := map[string]int32{}
+	if current != nil {
+		for _, p := range current.Spec.Ports {
+			currentPorts[p.Name] = p.NodePort
+		}
+	}
+
+	previous := key.AllocatedNodePorts(cr)
+	allocated := map[string]int32{}
+	used := map[int32]bool{}
+
+	isFree := func(name string, port int32) (bool, error) {
+		if port == 0 || used[port] {
+			return false, nil
+		}
+		if _, ok := reserved[port]; ok {
+			return false, nil
+		}
+
+		owner, ok := allocatedByOthers[port]
+		if !ok {
+			return true, nil
+		}
+
+		// The worker service of this cluster already uses the port, so the
+		// other cluster's worker service cannot use it.
+		if currentPorts[name] == port {
+			return true, nil
+		}
+
+		// Ports allocated by another cluster are only ever taken over when
+		// both clusters persisted the allocation concurrently and neither
+		// worker service uses the port yet.
+		if previous[name] != port || key.ClusterID(cr) > owner {
+			return false, nil
+		}
+
+		inUse, err := r.isNodePortInUse(ctx, owner, port)
+		if err != nil {
+			return false, microerror.Mask(err)
+		}
+
+		return !inUse, nil
+	}
+
+	// Existing allocations are kept first so that reallocating a conflicting
+	// port mapping never takes the port of another one.
+	for _, p := range cr.Spec.KVM.PortMappings {
+		if p.NodePort != 0 {
+			continue
+		}
+
+		for _, candidate := range []int32{previous[p.Name], currentPorts[p.Name]} {
+			free, err := isFree(p.Name, candidate)
+			if err != nil {
+				return nil, microerror.Mask(err)
+			}
+			if free {
+				allocated[p.Name] = candidate
+				used[candidate] = true
+				break
+			}
+		}
+
+		if previous[p.Name] != 0 && allocated[p.Name] != previous[p.Name] {
+			r.logger.Debugf(ctx, "node port %d of port mapping %#q conflicts with another cluster", previous[p.Name], p.Name)
+		}
+	}
+
+	for _, p := range cr.Spec.KVM.PortMappings {
+		if p.NodePort != 0 {
+			continue
+		}
+		if _, ok := allocated[p.Name]; ok {
+			continue
+		}
+
+		var port int32
+		for candidate := r.nodePortRange.min; port == 0 && candidate <= r.nodePortRange.max; candidate++ {
+			free, err := isFree(p.Name, candidate)
+			if err != nil {
+				return nil, microerror.Mask(err)
+			}
+			if free {
+				port = candidate
+			}
+		}
+
+		if port == 0 {
+			return nil, microerror.Maskf(noFreeNodePortError, "no node port left in range %d-%d for port mapping %#q", r.nodePortRange.min, r.nodePortRange.max, p.Name)
+		}
+
+		r.logger.Debugf(ctx, "allocated node port %d to port mapping %#q", port, p.Name)
+
+		allocated[p.Name] = port
+		used[port] = true
+	}
+
+	return allocated, nil
+}
+
+// isNodePortInUse returns true in case the worker service of the cluster with
+// the given ID uses the given node port.
+func (r *Resource) isNodePortInUse(ctx context.Context, clusterID string, port int32) (bool, error) {
+	service, err := r.k8sClient.CoreV1().Services(clusterID).Get(ctx, key.WorkerID, metav1.GetOptions{})
+	if apierrors.IsNotFound(err) {
+		return false, nil
+	} else if err != nil {
+		return false, microerror.Mask(err)
+	}
+
+	for _, p := range service.Spec.Ports {
+		if p.NodePort == port {
+			return true, nil
+		}
+	}
+
+	return false, nil
+}
@@ -0,0 +1,314 @@
+package nodeportstatus
+
+import (
+	"context"
+	"encoding/json"
+	"reflect"
+	"testing"
+
+	"github.com/giantswarm/apiextensions/v3/pkg/apis/provider/v1alpha1"
+	apiextfake "github.com/giantswarm/apiextensions/v3/pkg/clientset/versioned/fake"
+	"github.com/giantswarm/micrologger/microloggertest"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/fake"
+
+	"github.com/giantswarm/kvm-operator/v4/service/controller/key"
+)
+
+func testNodePortCluster(id string, mappings []v1alpha1.KVMConfigSpecKVMPortMappings, allocated map[string]int32) v1alpha1.KVMConfig {
+	cr := v1alpha1.KVMConfig{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      id,
+			Namespace: "default",
+		},
+		Spec: v1alpha1.KVMConfigSpec{
+			Cluster: v1alpha1.Cluster{
+				ID: id,
+			},
+			KVM: v1alpha1.KVMConfigSpecKVM{
+				PortMappings: mappings,
+			},
+		},
+	}
+
+	if len(allocated) > 0 {
+		b, _ := json.Marshal(allocated)
+		cr.Annotations = map[string]string{
+			key.AnnotationAllocatedNodePorts: string(b),
+		}
+	}
+
+	return cr
+}
+
+func Test_EnsureCreated(t *testing.T) {
+	mappings := []v1alpha1.KVMConfigSpecKVMPortMappings{
+		{Name: "http", TargetPort: 30010},
+	}
+
+	clusterIP := testNodePortCluster("b2bbb", mappings, map[string]int32{"http": 30100})
+	clusterIP.Annotations[key.AnnotationWorkerService] = `{"type":"ClusterIP"}`
+
+	testCases := []struct {
+		name          string
+		cluster       v1alpha1.KVMConfig
+		others        []v1alpha1.KVMConfig
+		services      []runtime.Object
+		nodePortRange string
+		expectedPorts map[string]int32
+	}{
+		{
+			name:    "case 0: allocate node ports not requested by other clusters",
+			cluster: testNodePortCluster("b2bbb", mappings, nil),
+			others: []v1alpha1.KVMConfig{
+				testNodePortCluster("c3ccc", []v1alpha1.KVMConfigSpecKVMPortMappings{{Name: "http", NodePort: 30100}}, nil),
+				testNodePortCluster("a1aaa", mappings, map[string]int32{"http": 30101}),
+			},
+			nodePortRange: "30100-30105",
+			expectedPorts: map[string]int32{"http": 30102},
+		},
+		{
+			name:    "case 1: adopt node ports of the existing worker service",
+			cluster: testNodePortCluster("b2bbb", mappings, nil),
+			services: []runtime.Object{
+				&corev1.Service{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      key.WorkerID,
+						Namespace: "b2bbb",
+					},
+					Spec: corev1.ServiceSpec{
+						Ports: []corev1.ServicePort{
+							{Name: "http", NodePort: 30104},
+						},
+					},
+				},
+			},
+			nodePortRange: "30100-30105",
+			expectedPorts: map[string]int32{"http": 30104},
+		},
+		{
+			name:          "case 2: release node ports of ClusterIP services",
+			cluster:       clusterIP,
+			nodePortRange: "30100-30105",
+			expectedPorts: map[string]int32{},
+		},
+		{
+			name:          "case 3: release node ports without node port range",
+			cluster:       testNodePortCluster("b2bbb", mappings, map[string]int32{"http": 30100}),
+			expectedPorts: map[string]int32{},
+		},
+		{
+			name:    "case 4: lower cluster ID reconciling second does not take ports of other clusters",
+			cluster: testNodePortCluster("a1aaa", mappings, nil),
+			others: []v1alpha1.KVMConfig{
+				testNodePortCluster("b2bbb", mappings, map[string]int32{"http": 30100}),
+			},
+			services: []runtime.Object{
+				&corev1.Service{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      key.WorkerID,
+						Namespace: "b2bbb",
+					},
+					Spec: corev1.ServiceSpec{
+						Ports: []corev1.ServicePort{
+							{Name: "http", NodePort: 30100},
+						},
+					},
+				},
+			},
+			nodePortRange: "30100-30105",
+			expectedPorts: map[string]int32{"http": 30101},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			objs := []runtime.Object{&tc.cluster}
+			for i := range tc.others {
+				objs = append(objs, &tc.others[i])
+			}
+			g8sClient := apiextfake.NewSimpleClientset(objs...)
+
+			var r *Resource
+			{
+				c := Config{
+					G8sClient: g8sClient,
+					K8sClient: fake.NewSimpleClientset(tc.services...),
+					Logger:    microloggertest.New(),
+
+					NodePortRange: tc.nodePortRange,
+				}
+
+				var err error
+				r, err = New(c)
+				if err != nil {
+					t.Fatalf("expected nil, got %#v", err)
+				}
+			}
+
+			err := r.EnsureCreated(context.TODO(), &tc.cluster)
+			if err != nil {
+				t.Fatalf("expected nil, got %#v", err)
+			}
+
+			updated, err := g8sClient.ProviderV1alpha1().KVMConfigs(tc.cluster.Namespace).Get(context.TODO(), tc.cluster.Name, metav1.GetOptions{})
+			if err != nil {
+				t.Fatalf("expected nil, got %#v", err)
+			}
+
+			ports := key.AllocatedNodePorts(*updated)
+			if !reflect.DeepEqual(ports, tc.expectedPorts) {
+				t.Fatalf("expected node ports %v, got %v", tc.expectedPorts, ports)
+			}
+		})
+	}
+}
+
+func Test_allocateNodePorts(t *testing.T) {
+	mappings := []v1alpha1.KVMConfigSpecKVMPortMappings{
+		{Name: "http", TargetPort: 30010},
+		{Name: "https", TargetPort: 30011},
+	}
+
+	testCases := []struct {
+		name         string
+		customObject v1alpha1.KVMConfig
+		others       []v1alpha1.KVMConfig
+		current      *corev1.Service
+		services     []runtime.Object
+		expected     map[string]int32
+		errorMatcher func(error) bool
+	}{
+		{
+			name:         "case 0: allocate lowest free ports",
+			customObject: testNodePortCluster("b2bbb", mappings, nil),
+			others: []v1alpha1.KVMConfig{
+				testNodePortCluster("c3ccc", []v1alpha1.KVMConfigSpecKVMPortMappings{{Name: "http", NodePort: 30100}}, nil),
+				testNodePortCluster("a1aaa", mappings, map[string]int32{"http": 30101}),
+			},
+			expected: map[string]int32{"http": 30102, "https": 30103},
+		},
+		{
+			name:         "case 1: keep previous allocations",
+			customObject: testNodePortCluster("b2bbb", mappings, map[string]int32{"http": 30105, "https": 30104}),
+			expected:     map[string]int32{"http": 30105, "https": 30104},
+		},
+		{
+			name:         "case 2: adopt node ports of existing service",
+			customObject: testNodePortCluster("b2bbb", mappings, nil),
+			current: &corev1.Service{
+				Spec: corev1.ServiceSpec{
+					Ports: []corev1.ServicePort{
+						{Name: "http", NodePort: 31234},
+						{Name: "https", NodePort: 31235},
+					},
+				},
+			},
+			expected: map[string]int32{"http": 31234, "https": 31235},
+		},
+		{
+			name:         "case 3: reallocate ports taken by explicit port mappings",
+			customObject: testNodePortCluster("b2bbb", mappings, map[string]int32{"http": 30100, "https": 30101}),
+			others: []v1alpha1.KVMConfig{
+				testNodePortCluster("c3ccc", []v1alpha1.KVMConfigSpecKVMPortMappings{{Name: "http", NodePort: 30100}}, nil),
+			},
+			expected: map[string]int32{"http": 30102, "https": 30101},
+		},
+		{
+			name:         "case 4: conflicting allocations are won by the lower cluster ID",
+			customObject: testNodePortCluster("b2bbb", mappings, map[string]int32{"http": 30100, "https": 30101}),
+			others: []v1alpha1.KVMConfig{
+				testNodePortCluster("a1aaa", mappings, map[string]int32{"http": 30100}),
+				testNodePortCluster("c3ccc", mappings, map[string]int32{"http": 30101}),
+			},
+			expected: map[string]int32{"http": 30102, "https": 30101},
+		},
+		{
+			name: "case 5: explicit node ports are not allocated",
+			customObject: testNodePortCluster("b2bbb", []v1alpha1.KVMConfigSpecKVMPortMappings{
+				{Name: "http", NodePort: 30100, TargetPort: 30010},
+				{Name: "https", TargetPort: 30011},
+			}, nil),
+			expected: map[string]int32{"https": 30101},
+		},
+		{
+			name:         "case 6: range exhausted",
+			customObject: testNodePortCluster("b2bbb", mappings, nil),
+			others: []v1alpha1.KVMConfig{
+				testNodePortCluster("a1aaa", mappings, map[string]int32{"http": 30100, "https": 30101, "metrics": 30102, "ssh": 30103, "dns": 30104}),
+			},
+			errorMatcher: IsNoFreeNodePort,
+		},
+		{
+			name:         "case 7: ports allocated by higher cluster IDs are not taken",
+			customObject: testNodePortCluster("a1aaa", mappings, nil),
+			others: []v1alpha1.KVMConfig{
+				testNodePortCluster("b2bbb", mappings, map[string]int32{"http": 30100, "https": 30101}),
+			},
+			expected: map[string]int32{"http": 30102, "https": 30103},
+		},
+		{
+			name:         "case 8: conflicting allocations used by the worker service of a higher cluster ID are not taken",
+			customObject: testNodePortCluster("a1aaa", mappings, map[string]int32{"http": 30100, "https": 30101}),
+			others: []v1alpha1.KVMConfig{
+				testNodePortCluster("b2bbb", mappings, map[string]int32{"http": 30100}),
+			},
+			services: []runtime.Object{
+				&corev1.Service{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      key.WorkerID,
+						Namespace: "b2bbb",
+					},
+					Spec: corev1.ServiceSpec{
+						Ports: []corev1.ServicePort{
+							{Name: "http", NodePort: 30100},
+						},
+					},
+				},
+			},
+			expected: map[string]int32{"http": 30102, "https": 30101},
+		},
+		{
+			name:         "case 9: conflicting allocations used by the own worker service are kept",
+			customObject: testNodePortCluster("b2bbb", mappings, map[string]int32{"http": 30100, "https": 30101}),
+			others: []v1alpha1.KVMConfig{
+				testNodePortCluster("a1aaa", mappings, map[string]int32{"http": 30100}),
+			},
+			current: &corev1.Service{
+				Spec: corev1.ServiceSpec{
+					Ports: []corev1.ServicePort{
+						{Name: "http", NodePort: 30100},
+						{Name: "https", NodePort: 30101},
+					},
+				},
+			},
+			expected: map[string]int32{"http": 30100, "https": 30101},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			r := &Resource{
+				k8sClient:     fake.NewSimpleClientset(tc.services...),
+				logger:        microloggertest.New(),
+				nodePortRange: &nodePortRange{min: 30100, max: 30105},
+			}
+
+			result, err := r.allocateNodePorts(context.Background(), tc.customObject, tc.others, tc.current)
+			if tc.errorMatcher != nil {
+				if !tc.errorMatcher(err) {
+					t.Fatalf("expected matching error got %#v", err)
+				}
+				return
+			} else if err != nil {
+				t.Fatalf("expected %#v got %#v", nil, err)
+			}
+
+			if !reflect.DeepEqual(result, tc.expected) {
+				t.Fatalf("expected %#v got %#v", tc.expected, result)
+			}
+		})
+	}
+}
@@ -0,0 +1,11 @@
+package nodeportstatus
+
+import (
+	"context"
+)
+
+// EnsureDeleted does nothing, the node ports of deleted clusters are released
+// by the service resource once their services are deleted.
+func (r *Resource) EnsureDeleted(ctx context.Context, obj interface{}) error {
+	return nil
+}
@@ -0,0 +1,23 @@
+package nodeportstatus
+
+import (
+	"github.com/giantswarm/microerror"
+)
+
+var invalidConfigError = &microerror.Error{
+	Kind: "invalidConfigError",
+}
+
+// IsInvalidConfig asserts invalidConfigError.
+func IsInvalidConfig(err error) bool {
+	return microerror.Cause(err) == invalidConfigError
+}
+
+var noFreeNodePortError = &microerror.Error{
+	Kind: "noFreeNodePortError",
+}
+
+// IsNoFreeNodePort asserts noFreeNodePortError.
+func IsNoFreeNodePort(err error) bool {
+	return microerror.Cause(err) == noFreeNodePortError
+}
@@ -0,0 +1,102 @@
+package nodeportstatus
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/giantswarm/apiextensions/v3/pkg/clientset/versioned"
+	"github.com/giantswarm/microerror"
+	"github.com/giantswarm/micrologger"
+	"k8s.io/client-go/kubernetes"
+
+	"github.com/giantswarm/kvm-operator/v4/service/controller/key"
+)
+
+const (
+	Name = key.NodePortStatusResourceName
+)
+
+type Config struct {
+	G8sClient versioned.Interface
+	K8sClient kubernetes.Interface
+	Logger    micrologger.Logger
+
+	// NodePortRange is the range node ports are allocated from, e.g.
+	// 30100-30999. Node ports are chosen by the Kubernetes API in case it is
+	// empty.
+	NodePortRange string
+}
+
+// Resource allocates node ports to the port mappings of clusters which do not
+// define one and persists them in the AnnotationAllocatedNodePorts annotation
+// of the KVMConfig, so that the service resource only has to read them.
+type Resource struct {
+	g8sClient versioned.Interface
+	k8sClient kubernetes.Interface
+	logger    micrologger.Logger
+
+	nodePortRange *nodePortRange
+}
+
+func New(config Config) (*Resource, error) {
+	if config.G8sClient == nil {
+		return nil, microerror.Maskf(invalidConfigError, "%T.G8sClient must not be empty", config)
+	}
+	if config.K8sClient == nil {
+		return nil, microerror.Maskf(invalidConfigError, "%T.K8sClient must not be empty", config)
+	}
+	if config.Logger == nil {
+		return nil, microerror.Maskf(invalidConfigError, "%T.Logger must not be empty", config)
+	}
+
+	var portRange *nodePortRange
+	if config.NodePortRange != "" {
+		var err error
+		portRange, err = parseNodePortRange(config.NodePortRange)
+		if err != nil {
+			return nil, microerror.Mask(err)
+		}
+	}
+
+	r := &Resource{
+		g8sClient: config.G8sClient,
+		k8sClient: config.K8sClient,
+		logger:    config.Logger,
+
+		nodePortRange: portRange,
+	}
+
+	return r, nil
+}
+
+func (r *Resource) Name() string {
+	return Name
+}
+
+// nodePortRange is the inclusive range node ports are allocated from for port
+// mappings which do not define a node port.
+type nodePortRange struct {
+	min int32
+	max int32
+}
+
+func parseNodePortRange(s string) (*nodePortRange, error) {
+	parts := strings.Split(s, "-")
+	if len(parts) != 2 {
+		return nil, microerror.Maskf(invalidConfigError, "node port range %#q must have the format <min>-<max>", s)
+	}
+
+	min, err := strconv.ParseUint(strings.TrimSpace(parts[0]), 10, 16)
+	if err != nil {
+		return nil, microerror.Maskf(invalidConfigError, "node port range %#q must have the format <min>-<max>", s)
+	}
+	max, err := strconv.ParseUint(strings.TrimSpace(parts[1]), 10, 16)
+	if err != nil {
+		return nil, microerror.Maskf(invalidConfigError, "node port range %#q must have the format <min>-<max>", s)
+	}
+	if min == 0 || min > max {
+		return nil, microerror.Maskf(invalidConfigError, "node port range %#q must not be empty", s)
+	}
+
+	return &nodePortRange{min: int32(min), max: int32(max)}, nil
+}
@@ -0,0 +1,59 @@
+package nodeportstatus
+
+import (
+	"reflect"
+	"testing"
+)
+
+func Test_parseNodePortRange(t *testing.T) {
+	testCases := []struct {
+		name         string
+		input        string
+		expected     *nodePortRange
+		errorMatcher func(error) bool
+	}{
+		{
+			name:     "case 0: valid range",
+			input:    "30100-30999",
+			expected: &nodePortRange{min: 30100, max: 30999},
+		},
+		{
+			name:     "case 1: single port",
+			input:    "30100-30100",
+			expected: &nodePortRange{min: 30100, max: 30100},
+		},
+		{
+			name:         "case 2: reversed range",
+			input:        "30999-30100",
+			errorMatcher: IsInvalidConfig,
+		},
+		{
+			name:         "case 3: missing max",
+			input:        "30100",
+			errorMatcher: IsInvalidConfig,
+		},
+		{
+			name:         "case 4: port out of range",
+			input:        "30100-70000",
+			errorMatcher: IsInvalidConfig,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			result, err := parseNodePortRange(tc.input)
+			if tc.errorMatcher != nil {
+				if !tc.errorMatcher(err) {
+					t.Fatalf("expected matching error got %#v", err)
+				}
+				return
+			} else if err != nil {
+				t.Fatalf("expected %#v got %#v", nil, err)
+			}
+
+			if !reflect.DeepEqual(result, tc.expected) {
+				t.Fatalf("expected %#v got %#v", tc.expected, result)
+			}
+		})
+	}
+}
@@ -2,6 +2,7 @@ package pod
 
 import (
 	"context"
+	"time"
 
 	corev1alpha1 "github.com/giantswarm/apiextensions/v3/pkg/apis/core/v1alpha1"
 	"github.com/giantswarm/microerror"
@@ -12,6 +13,15 @@ import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
 	"github.com/giantswarm/kvm-operator/v4/service/controller/key"
+	"github.com/giantswarm/kvm-operator/v4/service/metric"
+)
+
+const (
+	// Results of draining a workload cluster node exposed via
+	// metric.DrainDurationHistogram.
+	drainResultDrained    = "drained"
+	drainResultNotRunning = "not_running"
+	drainResultTimeout    = "timeout"
 )
 
 func (r *Resource) EnsureDeleted(ctx context.Context, obj interface{}) error {
@@ -102,14 +112,14 @@ func (r *Resource) EnsureDeleted(ctx context.Context, obj interface{}) error {
 		if drainerConfig.Status.HasDrainedCondition() {
 			r.logger.Debugf(ctx, "drainer config of workload cluster has drained condition")
 
-			err := r.finishDraining(ctx, currentPod, drainerConfig)
+			err := r.finishDraining(ctx, currentPod, drainerConfig, drainResultDrained)
 			if err != nil {
 				return microerror.Mask(err)
 			}
 		} else if drainerConfig.Status.HasTimeoutCondition() {
 			r.logger.Debugf(ctx, "drainer config of workload cluster has timeout condition")
 
-			err := r.finishDraining(ctx, currentPod, drainerConfig)
+			err := r.finishDraining(ctx, currentPod, drainerConfig, drainResultTimeout)
 			if err != nil {
 				return microerror.Mask(err)
 			}
@@ -117,7 +127,7 @@ func (r *Resource) EnsureDeleted(ctx context.Context, obj interface{}) error {
 			r.logger.Debugf(ctx, "pod is treated as drained")
 			r.logger.Debugf(ctx, "no pod containers are running")
 
-			err := r.finishDraining(ctx, currentPod, drainerConfig)
+			err := r.finishDraining(ctx, currentPod, drainerConfig, drainResultNotRunning)
 			if err != nil {
 				return microerror.Mask(err)
 			}
@@ -175,7 +185,7 @@ func (r *Resource) createDrainerConfig(ctx context.Context, pod *corev1.Pod) err
 	return nil
 }
 
-func (r *Resource) finishDraining(ctx context.Context, currentPod *corev1.Pod, drainerConfig *corev1alpha1.DrainerConfig) error {
+func (r *Resource) finishDraining(ctx context.Context, currentPod *corev1.Pod, drainerConfig *corev1alpha1.DrainerConfig, result string) error {
 	var err error
 
 	{
@@ -242,5 +252,10 @@ func (r *Resource) finishDraining(ctx context.Context, currentPod *corev1.Pod, d
 		r.logger.Debugf(ctx, "deleted the pod in the Kubernetes API")
 	}
 
+	if podToDelete.GetDeletionTimestamp() != nil {
+		d := time.Since(podToDelete.GetDeletionTimestamp().Time)
+		metric.DrainDurationHistogram.WithLabelValues(podToDelete.GetNamespace(), podToDelete.GetLabels()[key.LabelOrganization], result).Observe(d.Seconds())
+	}
+
 	return nil
 }
@@ -0,0 +1,174 @@
+package portstatus
+
+import (
+	"context"
+
+	"github.com/giantswarm/apiextensions/v3/pkg/apis/provider/v1alpha1"
+	"github.com/giantswarm/microerror"
+	"github.com/giantswarm/operatorkit/v5/pkg/controller/context/reconciliationcanceledcontext"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/giantswarm/kvm-operator/v4/service/controller/key"
+)
+
+// hostPorts are the kinds of ports allocated per cluster.
+var hostPorts = []string{
+	key.HostPortLiveness,
+	key.HostPortShutdownDeferrer,
+}
+
+func (r *Resource) EnsureCreated(ctx context.Context, obj interface{}) error {
+	cr, err := key.ToCustomObject(obj)
+	if err != nil {
+		return microerror.Mask(err)
+	}
+
+	var others []v1alpha1.KVMConfig
+	{
+		list, err := r.g8sClient.ProviderV1alpha1().KVMConfigs(metav1.NamespaceAll).List(ctx, metav1.ListOptions{})
+		if err != nil {
+			return microerror.Mask(err)
+		}
+
+		for _, other := range list.Items {
+			if key.ClusterID(other) == key.ClusterID(cr) {
+				continue
+			}
+			others = append(others, other)
+		}
+	}
+
+	ports, err := r.allocatePorts(ctx, cr, others)
+	if err != nil {
+		return microerror.Mask(err)
+	}
+
+	{
+		r.logger.Debugf(ctx, "updating ports")
+
+		updated, err := key.UpdateStatusAnnotation(ctx, r.g8sClient, cr, key.AnnotationAllocatedHostPorts, ports)
+		if err != nil {
+			return microerror.Mask(err)
+		}
+
+		if updated {
+			r.logger.Debugf(ctx, "updated ports")
+
+			r.logger.Debugf(ctx, "canceling reconciliation")
+			reconciliationcanceledcontext.SetCanceled(ctx)
+
+			return nil
+		} else {
+			r.logger.Debugf(ctx, "did not update ports")
+		}
+	}
+
+	return nil
+}
+
+// allocatePorts returns the liveness and shutdown-deferrer ports of the given
+// cluster. Ports already persisted in its status are kept. Clusters without
+// persisted ports keep the ports derived from their VNI, so that existing
+// clusters are migrated without changing their ports. Ports outside of their
+// range or claimed by another cluster are reallocated to the lowest free port
+// of the range.
+func (r *Resource) allocatePorts(ctx context.Context, cr v1alpha1.KVMConfig, others []v1alpha1.KVMConfig) (map[string]int, error) {
+	persisted := key.AllocatedHostPorts(cr)
+
+	ports := map[string]int{}
+	for _, name := range hostPorts {
+		port, ok := persisted[name]
+		if !ok {
+			port = key.LegacyHostPort(cr, name)
+		}
+
+		min, max := key.HostPortRange(name)
+		if port < min || port > max {
+			r.logger.Debugf(ctx, "%s port %d is out of range %d-%d", name, port, min, max)
+			continue
+		}
+
+		if other, ok := claimingCluster(cr, others, port, ok); ok {
+			r.logger.Debugf(ctx, "%s port %d is claimed by cluster %#q", name, port, key.ClusterID(other))
+			continue
+		}
+
+		ports[name] = port
+	}
+
+	for _, name := range hostPorts {
+		if _, ok := ports[name]; ok {
+			continue
+		}
+
+		used := map[int]bool{}
+		for _, other := range others {
+			for _, port := range hostPortsOf(other) {
+				used[port] = true
+			}
+		}
+
+		min, max := key.HostPortRange(name)
+		for port := min; port <= max; port++ {
+			if !used[port] {
+				ports[name] = port
+				break
+			}
+		}
+
+		if _, ok := ports[name]; !ok {
+			return nil, microerror.Maskf(noFreePortError, "all %s ports in range %d-%d are allocated", name, min, max)
+		}
+
+		r.logger.Debugf(ctx, "allocated %s port %d", name, ports[name])
+	}
+
+	return ports, nil
+}
+
+// claimingCluster returns the other cluster which has a stronger claim on the
+// given port than the given cluster. Persisted ports win over ports derived
+// from the VNI of not yet migrated clusters. Between clusters with equal
+// claims the lower cluster ID wins, so that conflicts are resolved the same way
+// regardless of which cluster is reconciled first.
+func claimingCluster(cr v1alpha1.KVMConfig, others []v1alpha1.KVMConfig, port int, persisted bool) (v1alpha1.KVMConfig, bool) {
+	for _, other := range others {
+		otherPersisted := key.AllocatedHostPorts(other)
+
+		for _, otherName := range hostPorts {
+			otherPort, ok := otherPersisted[otherName]
+			if !ok {
+				otherPort = key.LegacyHostPort(other, otherName)
+			}
+			if otherPort != port {
+				continue
+			}
+
+			if ok && !persisted {
+				return other, true
+			}
+			if ok == persisted && key.ClusterID(other) < key.ClusterID(cr) {
+				return other, true
+			}
+		}
+	}
+
+	return v1alpha1.KVMConfig{}, false
+}
+
+// hostPortsOf returns the ports the given cluster uses, either persisted in its
+// status or derived from its VNI.
+func hostPortsOf(cr v1alpha1.KVMConfig) []int {
+	persisted := key.AllocatedHostPorts(cr)
+
+	var ports []int
+	for _, name := range hostPorts {
+		port, ok := persisted[name]
+		if !ok {
+			port = key.LegacyHostPort(cr, name)
+		}
+		ports = append(ports, port)
+	}
+
+	return ports
+}
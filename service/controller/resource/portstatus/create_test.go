@@ -0,0 +1,182 @@
+package portstatus
+
+import (
+	"context"
+	"encoding/json"
+	"reflect"
+	"testing"
+
+	"github.com/giantswarm/apiextensions/v3/pkg/apis/provider/v1alpha1"
+	"github.com/giantswarm/apiextensions/v3/pkg/clientset/versioned/fake"
+	"github.com/giantswarm/micrologger/microloggertest"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	"github.com/giantswarm/kvm-operator/v4/service/controller/key"
+)
+
+func testKVMConfig(id string, vni int, ports map[string]int) *v1alpha1.KVMConfig {
+	cr := &v1alpha1.KVMConfig{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      id,
+			Namespace: "default",
+		},
+		Spec: v1alpha1.KVMConfigSpec{
+			Cluster: v1alpha1.Cluster{
+				ID: id,
+			},
+			KVM: v1alpha1.KVMConfigSpecKVM{
+				Network: v1alpha1.KVMConfigSpecKVMNetwork{
+					Flannel: v1alpha1.KVMConfigSpecKVMNetworkFlannel{
+						VNI: vni,
+					},
+				},
+			},
+		},
+	}
+
+	if ports != nil {
+		b, _ := json.Marshal(ports)
+		cr.Annotations = map[string]string{
+			key.AnnotationAllocatedHostPorts: string(b),
+		}
+	}
+
+	return cr
+}
+
+func Test_EnsureCreated(t *testing.T) {
+	testCases := []struct {
+		name          string
+		cluster       *v1alpha1.KVMConfig
+		others        []*v1alpha1.KVMConfig
+		expectedPorts map[string]int
+		errorMatcher  func(error) bool
+	}{
+		{
+			name:    "case 0: existing cluster keeps ports derived from VNI",
+			cluster: testKVMConfig("b2222", 5, nil),
+			expectedPorts: map[string]int{
+				key.HostPortLiveness:         23005,
+				key.HostPortShutdownDeferrer: 47005,
+			},
+		},
+		{
+			name: "case 1: persisted ports are kept",
+			cluster: testKVMConfig("b2222", 5, map[string]int{
+				key.HostPortLiveness:         23100,
+				key.HostPortShutdownDeferrer: 47100,
+			}),
+			expectedPorts: map[string]int{
+				key.HostPortLiveness:         23100,
+				key.HostPortShutdownDeferrer: 47100,
+			},
+		},
+		{
+			name:    "case 2: ports of large VNI are reallocated within range",
+			cluster: testKVMConfig("b2222", 20000, nil),
+			expectedPorts: map[string]int{
+				key.HostPortLiveness:         43000,
+				key.HostPortShutdownDeferrer: 47000,
+			},
+		},
+		{
+			name:    "case 3: cluster with lower ID keeps shared VNI ports",
+			cluster: testKVMConfig("a1111", 5, nil),
+			others: []*v1alpha1.KVMConfig{
+				testKVMConfig("b2222", 5, nil),
+			},
+			expectedPorts: map[string]int{
+				key.HostPortLiveness:         23005,
+				key.HostPortShutdownDeferrer: 47005,
+			},
+		},
+		{
+			name:    "case 4: cluster with higher ID moves off shared VNI ports",
+			cluster: testKVMConfig("b2222", 5, nil),
+			others: []*v1alpha1.KVMConfig{
+				testKVMConfig("a1111", 5, nil),
+				testKVMConfig("c3333", 0, nil),
+			},
+			expectedPorts: map[string]int{
+				key.HostPortLiveness:         23001,
+				key.HostPortShutdownDeferrer: 47001,
+			},
+		},
+		{
+			name:    "case 5: persisted ports win over ports derived from VNI",
+			cluster: testKVMConfig("a1111", 5, nil),
+			others: []*v1alpha1.KVMConfig{
+				testKVMConfig("b2222", 7, map[string]int{
+					key.HostPortLiveness:         23005,
+					key.HostPortShutdownDeferrer: 47007,
+				}),
+			},
+			expectedPorts: map[string]int{
+				key.HostPortLiveness:         23000,
+				key.HostPortShutdownDeferrer: 47005,
+			},
+		},
+		{
+			name: "case 6: persisted ports out of range are reallocated",
+			cluster: testKVMConfig("b2222", 5, map[string]int{
+				key.HostPortLiveness:         47005,
+				key.HostPortShutdownDeferrer: 47100,
+			}),
+			expectedPorts: map[string]int{
+				key.HostPortLiveness:         23005,
+				key.HostPortShutdownDeferrer: 47100,
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			objs := []runtime.Object{tc.cluster}
+			for _, o := range tc.others {
+				objs = append(objs, o)
+			}
+			g8sClient := fake.NewSimpleClientset(objs...)
+
+			var r *Resource
+			{
+				c := Config{
+					G8sClient: g8sClient,
+					Logger:    microloggertest.New(),
+				}
+
+				var err error
+				r, err = New(c)
+				if err != nil {
+					t.Fatalf("expected nil, got %#v", err)
+				}
+			}
+
+			err := r.EnsureCreated(context.TODO(), tc.cluster)
+			if tc.errorMatcher != nil {
+				if !tc.errorMatcher(err) {
+					t.Fatalf("expected matching error, got %#v", err)
+				}
+				return
+			} else if err != nil {
+				t.Fatalf("expected nil, got %#v", err)
+			}
+
+			updated, err := g8sClient.ProviderV1alpha1().KVMConfigs(tc.cluster.Namespace).Get(context.TODO(), tc.cluster.Name, metav1.GetOptions{})
+			if err != nil {
+				t.Fatalf("expected nil, got %#v", err)
+			}
+
+			ports := key.AllocatedHostPorts(*updated)
+			if !reflect.DeepEqual(ports, tc.expectedPorts) {
+				t.Fatalf("expected ports %v, got %v", tc.expectedPorts, ports)
+			}
+			if int(key.LivenessPort(*updated)) != tc.expectedPorts[key.HostPortLiveness] {
+				t.Fatalf("expected liveness port %d, got %d", tc.expectedPorts[key.HostPortLiveness], key.LivenessPort(*updated))
+			}
+			if key.ShutdownDeferrerListenPort(*updated) != tc.expectedPorts[key.HostPortShutdownDeferrer] {
+				t.Fatalf("expected shutdown-deferrer port %d, got %d", tc.expectedPorts[key.HostPortShutdownDeferrer], key.ShutdownDeferrerListenPort(*updated))
+			}
+		})
+	}
+}
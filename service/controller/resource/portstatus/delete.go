@@ -0,0 +1,11 @@
+package portstatus
+
+import (
+	"context"
+)
+
+// EnsureDeleted does nothing, the ports of deleted clusters are released
+// together with their KVMConfig.
+func (r *Resource) EnsureDeleted(ctx context.Context, obj interface{}) error {
+	return nil
+}
@@ -0,0 +1,23 @@
+package portstatus
+
+import (
+	"github.com/giantswarm/microerror"
+)
+
+var invalidConfigError = &microerror.Error{
+	Kind: "invalidConfigError",
+}
+
+// IsInvalidConfig asserts invalidConfigError.
+func IsInvalidConfig(err error) bool {
+	return microerror.Cause(err) == invalidConfigError
+}
+
+var noFreePortError = &microerror.Error{
+	Kind: "noFreePortError",
+}
+
+// IsNoFreePort asserts noFreePortError.
+func IsNoFreePort(err error) bool {
+	return microerror.Cause(err) == noFreePortError
+}
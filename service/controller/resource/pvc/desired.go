@@ -32,7 +32,12 @@ func (r *Resource) GetDesiredState(ctx context.Context, obj interface{}) (interf
 		r.logger.Debugf(ctx, "not computing the new master PVCs because storage type is not 'persistentVolume'")
 	}
 
-	if key.HasHostVolumes(customObject) {
+	hasDataVolumes, err := key.HasAnyWorkerDataVolumes(customObject)
+	if err != nil {
+		return nil, microerror.Maskf(invalidConfigError, "%s", err)
+	}
+
+	if key.HasHostVolumes(customObject) || hasDataVolumes || key.HasAnyPersistentDisks(customObject) {
 		r.logger.Debugf(ctx, "computing the new worker PVCs")
 
 		localPVCs, err := r.getDesiredWorkerPVCs(ctx, customObject)
@@ -44,7 +49,7 @@ func (r *Resource) GetDesiredState(ctx context.Context, obj interface{}) (interf
 
 		r.logger.Debugf(ctx, "computed the %d new worker PVCs", len(PVCs))
 	} else {
-		r.logger.Debugf(ctx, "not computing the new PVCs because no worker has defined host volumes")
+		r.logger.Debugf(ctx, "not computing the new PVCs because no worker has defined host volumes, data volumes or persistent disks")
 	}
 
 	return PVCs, nil
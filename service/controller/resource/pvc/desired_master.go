@@ -3,6 +3,7 @@ package pvc
 import (
 	"github.com/giantswarm/apiextensions/v3/pkg/apis/provider/v1alpha1"
 	"github.com/giantswarm/microerror"
+	"github.com/giantswarm/to"
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -12,19 +13,33 @@ import (
 )
 
 const (
-	// EtcdPVSize is the size the persistent volume for etcd is configured with.
+	// EtcdPVSize is the default size the persistent volume for etcd is
+	// configured with. It can be overridden per cluster using the
+	// key.AnnotationEtcdVolumeSize annotation.
 	EtcdPVSize = "15Gi"
 )
 
 func (r *Resource) getDesiredMasterPVCs(customObject v1alpha1.KVMConfig) ([]corev1.PersistentVolumeClaim, error) {
 	var persistentVolumeClaims []corev1.PersistentVolumeClaim
 
-	for i, masterNode := range customObject.Spec.Cluster.Masters {
-		quantity, err := resource.ParseQuantity(EtcdPVSize)
-		if err != nil {
-			return nil, microerror.Mask(err)
-		}
+	size := EtcdPVSize
+	if key.EtcdVolumeSize(customObject) != "" {
+		size = key.EtcdVolumeSize(customObject)
+	}
+	quantity, err := resource.ParseQuantity(size)
+	if err != nil {
+		return nil, microerror.Maskf(invalidConfigError, "etcd volume size %#q must be a quantity: %s", size, err)
+	}
+	if quantity.Sign() <= 0 {
+		return nil, microerror.Maskf(invalidConfigError, "etcd volume size %#q must be positive", size)
+	}
 
+	storageClass := EtcdStorageClass
+	if key.EtcdStorageClass(customObject) != "" {
+		storageClass = key.EtcdStorageClass(customObject)
+	}
+
+	for i, masterNode := range customObject.Spec.Cluster.Masters {
 		persistentVolumeClaim := corev1.PersistentVolumeClaim{
 			ObjectMeta: metav1.ObjectMeta{
 				Name: key.EtcdPVCName(key.ClusterID(customObject), key.VMNumber(i)),
@@ -37,9 +52,6 @@ func (r *Resource) getDesiredMasterPVCs(customObject v1alpha1.KVMConfig) ([]core
 					key.LabelVersionBundle: key.OperatorVersion(customObject),
 					"node":                 masterNode.ID,
 				},
-				Annotations: map[string]string{
-					"volume.beta.kubernetes.io/storage-class": EtcdStorageClass,
-				},
 			},
 			Spec: corev1.PersistentVolumeClaimSpec{
 				AccessModes: []corev1.PersistentVolumeAccessMode{
@@ -50,6 +62,7 @@ func (r *Resource) getDesiredMasterPVCs(customObject v1alpha1.KVMConfig) ([]core
 						corev1.ResourceStorage: quantity,
 					},
 				},
+				StorageClassName: to.StringP(storageClass),
 			},
 		}
 
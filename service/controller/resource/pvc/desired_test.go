@@ -6,9 +6,13 @@ import (
 	"testing"
 
 	"github.com/giantswarm/apiextensions/v3/pkg/apis/provider/v1alpha1"
+	apiextfake "github.com/giantswarm/apiextensions/v3/pkg/clientset/versioned/fake"
 	"github.com/giantswarm/micrologger/microloggertest"
 	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/client-go/kubernetes/fake"
+
+	"github.com/giantswarm/kvm-operator/v4/service/controller/key"
 )
 
 func Test_Resource_PVC_GetDesiredState(t *testing.T) {
@@ -183,6 +187,7 @@ func Test_Resource_PVC_GetDesiredState(t *testing.T) {
 	var newResource *Resource
 	{
 		resourceConfig := DefaultConfig()
+		resourceConfig.G8sClient = apiextfake.NewSimpleClientset()
 		resourceConfig.K8sClient = fake.NewSimpleClientset()
 		resourceConfig.Logger = microloggertest.New()
 		newResource, err = New(resourceConfig)
@@ -219,3 +224,276 @@ func testGetEtcdCount(PVCs []corev1.PersistentVolumeClaim) int {
 
 	return count
 }
+
+func Test_Resource_PVC_getDesiredMasterPVCs(t *testing.T) {
+	testCases := []struct {
+		Name                 string
+		Annotations          map[string]string
+		ExpectedSize         string
+		ExpectedStorageClass string
+		ErrorMatcher         func(error) bool
+	}{
+		{
+			Name:                 "case 0: installation defaults",
+			ExpectedSize:         EtcdPVSize,
+			ExpectedStorageClass: EtcdStorageClass,
+		},
+		{
+			Name: "case 1: cluster specific size and storage class",
+			Annotations: map[string]string{
+				key.AnnotationEtcdStorageClass: "ceph-rbd",
+				key.AnnotationEtcdVolumeSize:   "30Gi",
+			},
+			ExpectedSize:         "30Gi",
+			ExpectedStorageClass: "ceph-rbd",
+		},
+		{
+			Name: "case 2: invalid size",
+			Annotations: map[string]string{
+				key.AnnotationEtcdVolumeSize: "thirty",
+			},
+			ErrorMatcher: IsInvalidConfig,
+		},
+	}
+
+	var err error
+	var newResource *Resource
+	{
+		resourceConfig := DefaultConfig()
+		resourceConfig.G8sClient = apiextfake.NewSimpleClientset()
+		resourceConfig.K8sClient = fake.NewSimpleClientset()
+		resourceConfig.Logger = microloggertest.New()
+		newResource, err = New(resourceConfig)
+		if err != nil {
+			t.Fatal("expected", nil, "got", err)
+		}
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.Name, func(t *testing.T) {
+			cr := v1alpha1.KVMConfig{
+				ObjectMeta: metav1.ObjectMeta{
+					Annotations: tc.Annotations,
+				},
+				Spec: v1alpha1.KVMConfigSpec{
+					Cluster: v1alpha1.Cluster{
+						ID: "al9qy",
+						Masters: []v1alpha1.ClusterNode{
+							{},
+						},
+					},
+				},
+			}
+
+			pvcs, err := newResource.getDesiredMasterPVCs(cr)
+
+			switch {
+			case err == nil && tc.ErrorMatcher == nil:
+				// correct; carry on
+			case err != nil && tc.ErrorMatcher == nil:
+				t.Fatalf("error == %#v, want nil", err)
+			case err == nil && tc.ErrorMatcher != nil:
+				t.Fatalf("error == nil, want non-nil")
+			case !tc.ErrorMatcher(err):
+				t.Fatalf("error == %#v, want matching", err)
+			case tc.ErrorMatcher(err):
+				return
+			}
+
+			if len(pvcs) != 1 {
+				t.Fatalf("expected 1 PVC got %d", len(pvcs))
+			}
+			size := pvcs[0].Spec.Resources.Requests[corev1.ResourceStorage]
+			if size.String() != tc.ExpectedSize {
+				t.Fatalf("expected size %s got %s", tc.ExpectedSize, size.String())
+			}
+			if storageClassName(pvcs[0]) != tc.ExpectedStorageClass {
+				t.Fatalf("expected storage class %#q got %#q", tc.ExpectedStorageClass, storageClassName(pvcs[0]))
+			}
+		})
+	}
+}
+
+func Test_Resource_PVC_GetDesiredState_dataVolumes(t *testing.T) {
+	testCases := []struct {
+		Name          string
+		Annotation    string
+		HostVolumes   []v1alpha1.KVMConfigSpecKVMNodeHostVolumes
+		ExpectedNames []string
+		ErrorMatcher  func(error) bool
+	}{
+		{
+			Name:       "case 0: one data volume PVC per worker",
+			Annotation: `{"*":[{"mountTag":"data","hostPath":"/data","size":"100Gi","storageClass":"ceph-rbd"}]}`,
+			ExpectedNames: []string{
+				"pvc-worker-data-al9qy-a-data",
+				"pvc-worker-data-al9qy-b-data",
+			},
+		},
+		{
+			Name:       "case 1: data volumes of a single worker",
+			Annotation: `{"b":[{"mountTag":"scratch","hostPath":"/scratch","size":"10Gi","storageClass":"ceph-rbd"}]}`,
+			ExpectedNames: []string{
+				"pvc-worker-data-al9qy-b-scratch",
+			},
+		},
+		{
+			Name:       "case 2: data volumes must not reuse host volume mount tags",
+			Annotation: `{"*":[{"mountTag":"data","hostPath":"/data","size":"100Gi","storageClass":"ceph-rbd"}]}`,
+			HostVolumes: []v1alpha1.KVMConfigSpecKVMNodeHostVolumes{
+				{MountTag: "data", HostPath: "/data"},
+			},
+			ErrorMatcher: IsInvalidConfig,
+		},
+	}
+
+	var err error
+	var newResource *Resource
+	{
+		resourceConfig := DefaultConfig()
+		resourceConfig.G8sClient = apiextfake.NewSimpleClientset()
+		resourceConfig.K8sClient = fake.NewSimpleClientset(
+			&corev1.PersistentVolume{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "pv-data",
+					Labels: map[string]string{
+						key.LabelMountTag: "data",
+					},
+				},
+			},
+		)
+		resourceConfig.Logger = microloggertest.New()
+		newResource, err = New(resourceConfig)
+		if err != nil {
+			t.Fatal("expected", nil, "got", err)
+		}
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.Name, func(t *testing.T) {
+			cr := &v1alpha1.KVMConfig{
+				ObjectMeta: metav1.ObjectMeta{
+					Annotations: map[string]string{
+						key.AnnotationWorkerDataVolumes: tc.Annotation,
+					},
+				},
+				Spec: v1alpha1.KVMConfigSpec{
+					Cluster: v1alpha1.Cluster{
+						ID: "al9qy",
+						Workers: []v1alpha1.ClusterNode{
+							{ID: "a"},
+							{ID: "b"},
+						},
+					},
+					KVM: v1alpha1.KVMConfigSpecKVM{
+						Workers: []v1alpha1.KVMConfigSpecKVMNode{
+							{},
+							{HostVolumes: tc.HostVolumes},
+						},
+					},
+				},
+			}
+
+			result, err := newResource.GetDesiredState(context.TODO(), cr)
+
+			switch {
+			case err == nil && tc.ErrorMatcher == nil:
+				// correct; carry on
+			case err != nil && tc.ErrorMatcher == nil:
+				t.Fatalf("error == %#v, want nil", err)
+			case err == nil && tc.ErrorMatcher != nil:
+				t.Fatalf("error == nil, want non-nil")
+			case !tc.ErrorMatcher(err):
+				t.Fatalf("error == %#v, want matching", err)
+			case tc.ErrorMatcher(err):
+				return
+			}
+
+			pvcs, err := toPVCs(result)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if len(pvcs) != len(tc.ExpectedNames) {
+				t.Fatalf("expected %d PVCs got %d", len(tc.ExpectedNames), len(pvcs))
+			}
+			for i, pvc := range pvcs {
+				if pvc.Name != tc.ExpectedNames[i] {
+					t.Fatalf("expected PVC %#q got %#q", tc.ExpectedNames[i], pvc.Name)
+				}
+				if storageClassName(pvc) != "ceph-rbd" {
+					t.Fatalf("expected storage class %#q got %#q", "ceph-rbd", storageClassName(pvc))
+				}
+			}
+		})
+	}
+}
+
+func Test_Resource_PVC_GetDesiredState_persistentDisks(t *testing.T) {
+	var err error
+	var newResource *Resource
+	{
+		resourceConfig := DefaultConfig()
+		resourceConfig.G8sClient = apiextfake.NewSimpleClientset()
+		resourceConfig.K8sClient = fake.NewSimpleClientset()
+		resourceConfig.Logger = microloggertest.New()
+		newResource, err = New(resourceConfig)
+		if err != nil {
+			t.Fatal("expected", nil, "got", err)
+		}
+	}
+
+	cr := &v1alpha1.KVMConfig{
+		ObjectMeta: metav1.ObjectMeta{
+			Annotations: map[string]string{
+				key.AnnotationPersistentWorkerDisks:             "*",
+				key.AnnotationPersistentWorkerDisksStorageClass: "ceph-rbd",
+				key.AnnotationNodeDiskSizes:                     `{"a":{"kubeletVolumeSizeGB":20}}`,
+			},
+		},
+		Spec: v1alpha1.KVMConfigSpec{
+			Cluster: v1alpha1.Cluster{
+				ID: "al9qy",
+				Workers: []v1alpha1.ClusterNode{
+					{ID: "a"},
+				},
+			},
+			KVM: v1alpha1.KVMConfigSpecKVM{
+				Workers: []v1alpha1.KVMConfigSpecKVMNode{
+					{DockerVolumeSizeGB: 100},
+				},
+			},
+		},
+	}
+
+	result, err := newResource.GetDesiredState(context.TODO(), cr)
+	if err != nil {
+		t.Fatalf("expected %#v got %#v", nil, err)
+	}
+
+	pvcs, err := toPVCs(result)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expectedSizes := map[string]string{
+		key.PersistentDiskPVCName("al9qy", "a", key.PersistentDiskDocker):  "100G",
+		key.PersistentDiskPVCName("al9qy", "a", key.PersistentDiskKubelet): "20G",
+	}
+
+	if len(pvcs) != len(expectedSizes) {
+		t.Fatalf("expected %d PVCs got %d", len(expectedSizes), len(pvcs))
+	}
+	for _, pvc := range pvcs {
+		size := pvc.Spec.Resources.Requests[corev1.ResourceStorage]
+		if size.String() != expectedSizes[pvc.Name] {
+			t.Fatalf("expected PVC %#q to request %s got %s", pvc.Name, expectedSizes[pvc.Name], size.String())
+		}
+		if pvc.Spec.VolumeMode == nil || *pvc.Spec.VolumeMode != corev1.PersistentVolumeBlock {
+			t.Fatalf("expected PVC %#q to have volume mode %#q", pvc.Name, corev1.PersistentVolumeBlock)
+		}
+		if storageClassName(pvc) != "ceph-rbd" {
+			t.Fatalf("expected storage class %#q got %#q", "ceph-rbd", storageClassName(pvc))
+		}
+	}
+}
@@ -10,6 +10,7 @@ import (
 	"github.com/giantswarm/to"
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/labels"
 
@@ -17,6 +18,8 @@ import (
 	"github.com/giantswarm/kvm-operator/v4/service/controller/key"
 )
 
+var blockVolumeMode = corev1.PersistentVolumeBlock
+
 func (r *Resource) getDesiredWorkerPVCs(ctx context.Context, customObject v1alpha1.KVMConfig) ([]corev1.PersistentVolumeClaim, error) {
 	var persistentVolumeClaims []corev1.PersistentVolumeClaim
 	namespace := key.ClusterNamespace(customObject)
@@ -24,7 +27,14 @@ func (r *Resource) getDesiredWorkerPVCs(ctx context.Context, customObject v1alph
 	for i, workerKVM := range customObject.Spec.KVM.Workers {
 		workerCluster := customObject.Spec.Cluster.Workers[i]
 
-		if key.IsDeleted(&customObject) && len(workerKVM.HostVolumes) > 0 {
+		hasPersistentDisks := key.HasPersistentDisks(customObject, workerCluster.ID)
+
+		dataVolumes, err := key.WorkerDataVolumes(customObject, workerCluster.ID)
+		if err != nil {
+			return nil, microerror.Maskf(invalidConfigError, "%s", err)
+		}
+
+		if key.IsDeleted(&customObject) && (len(workerKVM.HostVolumes) > 0 || len(dataVolumes) > 0 || hasPersistentDisks) {
 			deploymentName := key.DeploymentName(key.WorkerID, workerCluster.ID)
 			_, err := r.k8sClient.AppsV1().Deployments(namespace).Get(ctx, deploymentName, metav1.GetOptions{})
 			if errors.IsNotFound(err) {
@@ -94,6 +104,95 @@ func (r *Resource) getDesiredWorkerPVCs(ctx context.Context, customObject v1alph
 
 			persistentVolumeClaims = append(persistentVolumeClaims, persistentVolumeClaim)
 		}
+
+		for _, dataVolume := range dataVolumes {
+			quantity, err := resource.ParseQuantity(dataVolume.Size)
+			if err != nil {
+				return nil, microerror.Mask(err)
+			}
+
+			persistentVolumeClaim := corev1.PersistentVolumeClaim{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: key.WorkerDataVolumePVCName(key.ClusterID(customObject), workerCluster.ID, dataVolume.MountTag),
+					Labels: map[string]string{
+						label.ManagedBy:        key.OperatorName,
+						key.LabelCustomer:      key.ClusterCustomer(customObject),
+						key.LabelApp:           key.WorkerID,
+						key.LabelCluster:       key.ClusterID(customObject),
+						key.LegacyLabelCluster: key.ClusterID(customObject),
+						key.LabelDataVolume:    dataVolume.MountTag,
+						key.LabelVersionBundle: key.OperatorVersion(customObject),
+						"node":                 workerCluster.ID,
+					},
+				},
+				Spec: corev1.PersistentVolumeClaimSpec{
+					AccessModes: []corev1.PersistentVolumeAccessMode{
+						corev1.ReadWriteOnce,
+					},
+					Resources: corev1.ResourceRequirements{
+						Requests: map[corev1.ResourceName]resource.Quantity{
+							corev1.ResourceStorage: quantity,
+						},
+					},
+				},
+			}
+			if dataVolume.StorageClass != "" {
+				persistentVolumeClaim.Spec.StorageClassName = to.StringP(dataVolume.StorageClass)
+			}
+
+			persistentVolumeClaims = append(persistentVolumeClaims, persistentVolumeClaim)
+		}
+
+		if hasPersistentDisks {
+			kubeletSize, err := key.KubeletVolumeSize(customObject, workerCluster.ID)
+			if err != nil {
+				return nil, microerror.Maskf(invalidConfigError, "%s", err)
+			}
+
+			disks := map[string]string{
+				key.PersistentDiskDocker:  key.DockerVolumeSizeFromNode(workerKVM),
+				key.PersistentDiskKubelet: kubeletSize,
+			}
+
+			for _, disk := range []string{key.PersistentDiskDocker, key.PersistentDiskKubelet} {
+				quantity, err := resource.ParseQuantity(disks[disk])
+				if err != nil {
+					return nil, microerror.Maskf(invalidConfigError, "%s size %#q of worker %#q must be a quantity: %s", disk, disks[disk], workerCluster.ID, err)
+				}
+
+				persistentVolumeClaim := corev1.PersistentVolumeClaim{
+					ObjectMeta: metav1.ObjectMeta{
+						Name: key.PersistentDiskPVCName(key.ClusterID(customObject), workerCluster.ID, disk),
+						Labels: map[string]string{
+							label.ManagedBy:         key.OperatorName,
+							key.LabelCustomer:       key.ClusterCustomer(customObject),
+							key.LabelApp:            key.WorkerID,
+							key.LabelCluster:        key.ClusterID(customObject),
+							key.LegacyLabelCluster:  key.ClusterID(customObject),
+							key.LabelPersistentDisk: disk,
+							key.LabelVersionBundle:  key.OperatorVersion(customObject),
+							"node":                  workerCluster.ID,
+						},
+					},
+					Spec: corev1.PersistentVolumeClaimSpec{
+						AccessModes: []corev1.PersistentVolumeAccessMode{
+							corev1.ReadWriteOnce,
+						},
+						Resources: corev1.ResourceRequirements{
+							Requests: map[corev1.ResourceName]resource.Quantity{
+								corev1.ResourceStorage: quantity,
+							},
+						},
+						VolumeMode: &blockVolumeMode,
+					},
+				}
+				if key.PersistentDisksStorageClass(customObject) != "" {
+					persistentVolumeClaim.Spec.StorageClassName = to.StringP(key.PersistentDisksStorageClass(customObject))
+				}
+
+				persistentVolumeClaims = append(persistentVolumeClaims, persistentVolumeClaim)
+			}
+		}
 	}
 
 	return persistentVolumeClaims, nil
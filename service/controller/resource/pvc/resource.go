@@ -1,6 +1,7 @@
 package pvc
 
 import (
+	"github.com/giantswarm/apiextensions/v3/pkg/clientset/versioned"
 	"github.com/giantswarm/microerror"
 	"github.com/giantswarm/micrologger"
 	corev1 "k8s.io/api/core/v1"
@@ -10,8 +11,9 @@ import (
 const (
 	// Name is the identifier of the resource.
 	Name = "pvc"
-	// EtcdStorageClass is the storage class annotation persistent volume claims are
-	// configured with.
+	// EtcdStorageClass is the default storage class etcd persistent volume
+	// claims are configured with. It can be overridden per cluster using the
+	// key.AnnotationEtcdStorageClass annotation.
 	EtcdStorageClass  = "g8s-storage"
 	LocalStorageClass = "local-storage"
 )
@@ -19,6 +21,7 @@ const (
 // Config represents the configuration used to create a new PVC resource.
 type Config struct {
 	// Dependencies.
+	G8sClient versioned.Interface
 	K8sClient kubernetes.Interface
 	Logger    micrologger.Logger
 }
@@ -28,6 +31,7 @@ type Config struct {
 func DefaultConfig() Config {
 	return Config{
 		// Dependencies.
+		G8sClient: nil,
 		K8sClient: nil,
 		Logger:    nil,
 	}
@@ -36,6 +40,7 @@ func DefaultConfig() Config {
 // Resource implements the PVC resource.
 type Resource struct {
 	// Dependencies.
+	g8sClient versioned.Interface
 	k8sClient kubernetes.Interface
 	logger    micrologger.Logger
 }
@@ -43,6 +48,9 @@ type Resource struct {
 // New creates a new configured PVC resource.
 func New(config Config) (*Resource, error) {
 	// Dependencies.
+	if config.G8sClient == nil {
+		return nil, microerror.Maskf(invalidConfigError, "config.G8sClient must not be empty")
+	}
 	if config.K8sClient == nil {
 		return nil, microerror.Maskf(invalidConfigError, "config.K8sClient must not be empty")
 	}
@@ -52,6 +60,7 @@ func New(config Config) (*Resource, error) {
 
 	newResource := &Resource{
 		// Dependencies.
+		g8sClient: config.G8sClient,
 		k8sClient: config.K8sClient,
 		logger:    config.Logger,
 	}
@@ -73,6 +82,26 @@ func containsPVC(list []corev1.PersistentVolumeClaim, item corev1.PersistentVolu
 	return false
 }
 
+func getPVCByName(list []corev1.PersistentVolumeClaim, name string) (corev1.PersistentVolumeClaim, bool) {
+	for _, l := range list {
+		if l.Name == name {
+			return l, true
+		}
+	}
+
+	return corev1.PersistentVolumeClaim{}, false
+}
+
+// storageClassName returns the storage class of the given PVC, considering the
+// beta annotation legacy etcd PVCs have been created with.
+func storageClassName(pvc corev1.PersistentVolumeClaim) string {
+	if pvc.Spec.StorageClassName != nil {
+		return *pvc.Spec.StorageClassName
+	}
+
+	return pvc.GetAnnotations()[corev1.BetaStorageClassAnnotation]
+}
+
 func toPVCs(v interface{}) ([]corev1.PersistentVolumeClaim, error) {
 	if v == nil {
 		return nil, nil
@@ -2,12 +2,88 @@ package pvc
 
 import (
 	"context"
+	"fmt"
 
+	"github.com/giantswarm/apiextensions/v3/pkg/apis/provider/v1alpha1"
 	"github.com/giantswarm/microerror"
 	"github.com/giantswarm/operatorkit/v5/pkg/resource/crud"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/giantswarm/kvm-operator/v4/service/controller/key"
+)
+
+const (
+	// conditionExpansionNotAllowed is true while a volume requests less
+	// storage than configured and its storage class does not allow volume
+	// expansion.
+	conditionExpansionNotAllowed = "ExpansionNotAllowed"
+	// conditionFileSystemResizePending is true while a volume has been
+	// expanded and its file system is resized once the VM pod using it is
+	// restarted.
+	conditionFileSystemResizePending = "FileSystemResizePending"
+	// conditionResizePending is true while the expansion of a volume has been
+	// requested but not yet picked up by the storage provisioner.
+	conditionResizePending = "ResizePending"
+	// conditionResizing is true while the storage provisioner expands a
+	// volume.
+	conditionResizing = "Resizing"
+	// conditionStorageClassMismatch is true while the storage class of a
+	// volume differs from the configured one. The storage class of existing
+	// volumes is immutable.
+	conditionStorageClassMismatch = "StorageClassMismatch"
 )
 
+// resizeConditions are the conditions reporting the resize progress of the
+// PVCs of a cluster.
+var resizeConditions = []string{
+	conditionResizePending,
+	conditionResizing,
+	conditionFileSystemResizePending,
+	conditionExpansionNotAllowed,
+	conditionStorageClassMismatch,
+}
+
+// ApplyUpdateChange grows the given PVCs to their desired size and reports
+// the resize progress of all PVCs of the cluster as conditions of the pvc
+// resource status. Every resize phase is a condition which is true while any
+// PVC of the cluster is in that phase. PVCs which cannot be resized as
+// configured are reported the same way.
 func (r *Resource) ApplyUpdateChange(ctx context.Context, obj, updateChange interface{}) error {
+	customObject, err := key.ToCustomObject(obj)
+	if err != nil {
+		return microerror.Mask(err)
+	}
+	pvcsToUpdate, err := toPVCs(updateChange)
+	if err != nil {
+		return microerror.Mask(err)
+	}
+
+	namespace := key.ClusterNamespace(customObject)
+
+	if len(pvcsToUpdate) != 0 {
+		r.logger.Debugf(ctx, "expanding the PVCs in the Kubernetes API")
+
+		for _, pvc := range pvcsToUpdate {
+			_, err := r.k8sClient.CoreV1().PersistentVolumeClaims(namespace).Update(ctx, pvc.DeepCopy(), metav1.UpdateOptions{})
+			if apierrors.IsConflict(err) || apierrors.IsNotFound(err) {
+				// fall through, retried during the next reconciliation
+			} else if err != nil {
+				return microerror.Mask(err)
+			}
+		}
+
+		r.logger.Debugf(ctx, "expanded the PVCs in the Kubernetes API")
+	} else {
+		r.logger.Debugf(ctx, "the PVCs do not need to be expanded in the Kubernetes API")
+	}
+
+	err = r.updateResizeStatus(ctx, customObject)
+	if err != nil {
+		return microerror.Mask(err)
+	}
+
 	return nil
 }
 
@@ -17,6 +93,11 @@ func (r *Resource) NewUpdatePatch(ctx context.Context, obj, currentState, desire
 		return nil, microerror.Mask(err)
 	}
 
+	orphaned, err := r.newOrphanedPVCsDeleteChange(ctx, obj, currentState, desiredState)
+	if err != nil {
+		return nil, microerror.Mask(err)
+	}
+
 	update, err := r.newUpdateChange(ctx, obj, currentState, desiredState)
 	if err != nil {
 		return nil, microerror.Mask(err)
@@ -24,11 +105,222 @@ func (r *Resource) NewUpdatePatch(ctx context.Context, obj, currentState, desire
 
 	patch := crud.NewPatch()
 	patch.SetCreateChange(create)
+	patch.SetDeleteChange(orphaned)
 	patch.SetUpdateChange(update)
 
 	return patch, nil
 }
 
+// newOrphanedPVCsDeleteChange returns the PVCs backing persistent worker disks
+// or worker data volumes which are not desired anymore, because their worker
+// has been removed or does not use the disk or data volume anymore. Deleting
+// them while the VM pod still uses them is safe, since Kubernetes defers the
+// removal of PVCs in use.
+func (r *Resource) newOrphanedPVCsDeleteChange(ctx context.Context, obj, currentState, desiredState interface{}) (interface{}, error) {
+	currentPVCs, err := toPVCs(currentState)
+	if err != nil {
+		return nil, microerror.Mask(err)
+	}
+	desiredPVCs, err := toPVCs(desiredState)
+	if err != nil {
+		return nil, microerror.Mask(err)
+	}
+
+	r.logger.Debugf(ctx, "finding out which persistent disk and data volume PVCs have to be deleted")
+
+	var pvcsToDelete []corev1.PersistentVolumeClaim
+	for _, currentPVC := range currentPVCs {
+		if currentPVC.GetLabels()[key.LabelPersistentDisk] == "" && currentPVC.GetLabels()[key.LabelDataVolume] == "" {
+			continue
+		}
+		if !containsPVC(desiredPVCs, currentPVC) {
+			pvcsToDelete = append(pvcsToDelete, currentPVC)
+		}
+	}
+
+	r.logger.Debugf(ctx, "found %d persistent disk and data volume PVCs that have to be deleted", len(pvcsToDelete))
+
+	return pvcsToDelete, nil
+}
+
+// newUpdateChange returns the existing PVCs requesting less storage than
+// desired, with their request set to the desired size. PVCs are never shrunk
+// and only expanded when their storage class allows volume expansion. The
+// storage class of existing PVCs is immutable and therefore never changed.
 func (r *Resource) newUpdateChange(ctx context.Context, obj, currentState, desiredState interface{}) (interface{}, error) {
-	return nil, nil
+	currentPVCs, err := toPVCs(currentState)
+	if err != nil {
+		return nil, microerror.Mask(err)
+	}
+	desiredPVCs, err := toPVCs(desiredState)
+	if err != nil {
+		return nil, microerror.Mask(err)
+	}
+
+	r.logger.Debugf(ctx, "finding out which PVCs have to be expanded")
+
+	var pvcsToUpdate []corev1.PersistentVolumeClaim
+
+	for _, desiredPVC := range desiredPVCs {
+		currentPVC, found := getPVCByName(currentPVCs, desiredPVC.Name)
+		if !found {
+			continue
+		}
+
+		desiredSize := desiredPVC.Spec.Resources.Requests[corev1.ResourceStorage]
+		currentSize := currentPVC.Spec.Resources.Requests[corev1.ResourceStorage]
+
+		if desiredSize.Cmp(currentSize) < 0 {
+			r.logger.Debugf(ctx, "not shrinking PVC %#q from %s to %s", currentPVC.Name, currentSize.String(), desiredSize.String())
+			continue
+		} else if desiredSize.Cmp(currentSize) == 0 {
+			continue
+		}
+
+		if storageClassName(desiredPVC) != "" && storageClassName(currentPVC) != storageClassName(desiredPVC) {
+			r.logger.Debugf(ctx, "not changing storage class of PVC %#q from %#q to %#q", currentPVC.Name, storageClassName(currentPVC), storageClassName(desiredPVC))
+		}
+
+		expandable, err := r.allowsVolumeExpansion(ctx, storageClassName(currentPVC))
+		if err != nil {
+			return nil, microerror.Mask(err)
+		}
+		if !expandable {
+			r.logger.Debugf(ctx, "not expanding PVC %#q because storage class %#q does not allow volume expansion", currentPVC.Name, storageClassName(currentPVC))
+			continue
+		}
+
+		r.logger.Debugf(ctx, "expanding PVC %#q from %s to %s", currentPVC.Name, currentSize.String(), desiredSize.String())
+
+		pvc := currentPVC.DeepCopy()
+		if pvc.Spec.Resources.Requests == nil {
+			pvc.Spec.Resources.Requests = corev1.ResourceList{}
+		}
+		pvc.Spec.Resources.Requests[corev1.ResourceStorage] = desiredSize
+		pvcsToUpdate = append(pvcsToUpdate, *pvc)
+	}
+
+	r.logger.Debugf(ctx, "found %d PVCs that have to be expanded", len(pvcsToUpdate))
+
+	return pvcsToUpdate, nil
+}
+
+func (r *Resource) allowsVolumeExpansion(ctx context.Context, name string) (bool, error) {
+	if name == "" {
+		return false, nil
+	}
+
+	storageClass, err := r.k8sClient.StorageV1().StorageClasses().Get(ctx, name, metav1.GetOptions{})
+	if apierrors.IsNotFound(err) {
+		return false, nil
+	} else if err != nil {
+		return false, microerror.Mask(err)
+	}
+
+	return storageClass.AllowVolumeExpansion != nil && *storageClass.AllowVolumeExpansion, nil
+}
+
+func (r *Resource) updateResizeStatus(ctx context.Context, customObject v1alpha1.KVMConfig) error {
+	pvcs, err := r.k8sClient.CoreV1().PersistentVolumeClaims(key.ClusterNamespace(customObject)).List(ctx, metav1.ListOptions{
+		LabelSelector: fmt.Sprintf("%s=%s", key.LegacyLabelCluster, key.ClusterID(customObject)),
+	})
+	if err != nil {
+		return microerror.Mask(err)
+	}
+
+	desiredState, err := r.GetDesiredState(ctx, &customObject)
+	if err != nil {
+		return microerror.Mask(err)
+	}
+	desiredPVCs, err := toPVCs(desiredState)
+	if err != nil {
+		return microerror.Mask(err)
+	}
+
+	inPhase := map[string]bool{}
+	for _, pvc := range pvcs.Items {
+		status := resizeStatus(pvc)
+		if status != "" {
+			r.logger.Debugf(ctx, "PVC %#q resize status is %#q", pvc.Name, status)
+
+			inPhase[status] = true
+		}
+
+		desiredPVC, found := getPVCByName(desiredPVCs, pvc.Name)
+		if !found {
+			continue
+		}
+
+		if storageClassName(desiredPVC) != "" && storageClassName(pvc) != storageClassName(desiredPVC) {
+			r.logger.Debugf(ctx, "PVC %#q uses storage class %#q instead of %#q", pvc.Name, storageClassName(pvc), storageClassName(desiredPVC))
+
+			inPhase[conditionStorageClassMismatch] = true
+		}
+
+		desiredSize := desiredPVC.Spec.Resources.Requests[corev1.ResourceStorage]
+		currentSize := pvc.Spec.Resources.Requests[corev1.ResourceStorage]
+		if desiredSize.Cmp(currentSize) > 0 {
+			expandable, err := r.allowsVolumeExpansion(ctx, storageClassName(pvc))
+			if err != nil {
+				return microerror.Mask(err)
+			}
+			if !expandable {
+				r.logger.Debugf(ctx, "PVC %#q cannot be expanded because storage class %#q does not allow volume expansion", pvc.Name, storageClassName(pvc))
+
+				inPhase[conditionExpansionNotAllowed] = true
+			}
+		}
+	}
+
+	var conditions []v1alpha1.StatusClusterResourceCondition
+	for _, c := range resizeConditions {
+		condition := v1alpha1.StatusClusterResourceCondition{
+			Status: string(corev1.ConditionFalse),
+			Type:   c,
+		}
+		if inPhase[c] {
+			condition.Status = string(corev1.ConditionTrue)
+		}
+
+		conditions = append(conditions, condition)
+	}
+
+	updated, err := key.UpdateResourceStatus(ctx, r.g8sClient, customObject, Name, conditions)
+	if err != nil {
+		return microerror.Mask(err)
+	}
+
+	if updated {
+		r.logger.Debugf(ctx, "updated status with PVC resize progress")
+	}
+
+	return nil
+}
+
+// resizeStatus returns the resize condition the given PVC is in or an empty
+// string in case the PVC is not being resized.
+func resizeStatus(pvc corev1.PersistentVolumeClaim) string {
+	for _, c := range pvc.Status.Conditions {
+		if c.Status != corev1.ConditionTrue {
+			continue
+		}
+		switch c.Type {
+		case corev1.PersistentVolumeClaimFileSystemResizePending:
+			return conditionFileSystemResizePending
+		case corev1.PersistentVolumeClaimResizing:
+			return conditionResizing
+		}
+	}
+
+	if pvc.Status.Phase != corev1.ClaimBound {
+		return ""
+	}
+
+	requested := pvc.Spec.Resources.Requests[corev1.ResourceStorage]
+	capacity := pvc.Status.Capacity[corev1.ResourceStorage]
+	if capacity.Cmp(requested) < 0 {
+		return conditionResizePending
+	}
+
+	return ""
 }
@@ -0,0 +1,403 @@
+package pvc
+
+import (
+	"context"
+	"testing"
+
+	"github.com/giantswarm/apiextensions/v3/pkg/apis/provider/v1alpha1"
+	apiextfake "github.com/giantswarm/apiextensions/v3/pkg/clientset/versioned/fake"
+	"github.com/giantswarm/micrologger/microloggertest"
+	"github.com/giantswarm/to"
+	corev1 "k8s.io/api/core/v1"
+	storagev1 "k8s.io/api/storage/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/fake"
+
+	"github.com/giantswarm/kvm-operator/v4/service/controller/key"
+)
+
+func Test_Resource_PVC_newUpdateChange(t *testing.T) {
+	testCases := []struct {
+		Name          string
+		CurrentState  []corev1.PersistentVolumeClaim
+		DesiredState  []corev1.PersistentVolumeClaim
+		ExpectedSizes map[string]string
+	}{
+		{
+			Name: "case 0: equal sizes are not updated",
+			CurrentState: []corev1.PersistentVolumeClaim{
+				testPVC("pvc-1", "expandable", "15Gi"),
+			},
+			DesiredState: []corev1.PersistentVolumeClaim{
+				testPVC("pvc-1", "expandable", "15Gi"),
+			},
+			ExpectedSizes: map[string]string{},
+		},
+		{
+			Name: "case 1: expandable PVCs are grown",
+			CurrentState: []corev1.PersistentVolumeClaim{
+				testPVC("pvc-1", "expandable", "15Gi"),
+			},
+			DesiredState: []corev1.PersistentVolumeClaim{
+				testPVC("pvc-1", "expandable", "30Gi"),
+			},
+			ExpectedSizes: map[string]string{
+				"pvc-1": "30Gi",
+			},
+		},
+		{
+			Name: "case 2: PVCs are never shrunk",
+			CurrentState: []corev1.PersistentVolumeClaim{
+				testPVC("pvc-1", "expandable", "30Gi"),
+			},
+			DesiredState: []corev1.PersistentVolumeClaim{
+				testPVC("pvc-1", "expandable", "15Gi"),
+			},
+			ExpectedSizes: map[string]string{},
+		},
+		{
+			Name: "case 3: PVCs of storage classes not allowing expansion are not grown",
+			CurrentState: []corev1.PersistentVolumeClaim{
+				testPVC("pvc-1", "fixed", "15Gi"),
+				testPVC("pvc-2", "missing", "15Gi"),
+			},
+			DesiredState: []corev1.PersistentVolumeClaim{
+				testPVC("pvc-1", "fixed", "30Gi"),
+				testPVC("pvc-2", "missing", "30Gi"),
+			},
+			ExpectedSizes: map[string]string{},
+		},
+		{
+			Name: "case 4: PVCs to be created are not updated",
+			CurrentState: []corev1.PersistentVolumeClaim{
+				testPVC("pvc-1", "expandable", "15Gi"),
+			},
+			DesiredState: []corev1.PersistentVolumeClaim{
+				testPVC("pvc-1", "expandable", "15Gi"),
+				testPVC("pvc-2", "expandable", "30Gi"),
+			},
+			ExpectedSizes: map[string]string{},
+		},
+	}
+
+	var err error
+	var newResource *Resource
+	{
+		resourceConfig := DefaultConfig()
+		resourceConfig.G8sClient = apiextfake.NewSimpleClientset()
+		resourceConfig.K8sClient = fake.NewSimpleClientset(
+			&storagev1.StorageClass{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "expandable",
+				},
+				AllowVolumeExpansion: to.BoolP(true),
+			},
+			&storagev1.StorageClass{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "fixed",
+				},
+			},
+		)
+		resourceConfig.Logger = microloggertest.New()
+		newResource, err = New(resourceConfig)
+		if err != nil {
+			t.Fatal("expected", nil, "got", err)
+		}
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.Name, func(t *testing.T) {
+			result, err := newResource.newUpdateChange(context.TODO(), &v1alpha1.KVMConfig{}, tc.CurrentState, tc.DesiredState)
+			if err != nil {
+				t.Fatalf("expected %#v got %#v", nil, err)
+			}
+
+			pvcs, err := toPVCs(result)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if len(pvcs) != len(tc.ExpectedSizes) {
+				t.Fatalf("expected %d PVCs got %d", len(tc.ExpectedSizes), len(pvcs))
+			}
+			for _, pvc := range pvcs {
+				size := pvc.Spec.Resources.Requests[corev1.ResourceStorage]
+				if size.String() != tc.ExpectedSizes[pvc.Name] {
+					t.Fatalf("expected PVC %#q to request %s got %s", pvc.Name, tc.ExpectedSizes[pvc.Name], size.String())
+				}
+			}
+		})
+	}
+}
+
+func Test_Resource_PVC_ApplyUpdateChange_resizeStatus(t *testing.T) {
+	newKVMConfig := func(annotations map[string]string, masters int) *v1alpha1.KVMConfig {
+		cr := &v1alpha1.KVMConfig{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:        "al9qy",
+				Namespace:   "default",
+				Annotations: annotations,
+			},
+			Spec: v1alpha1.KVMConfigSpec{
+				Cluster: v1alpha1.Cluster{
+					ID: "al9qy",
+				},
+			},
+		}
+		for i := 0; i < masters; i++ {
+			cr.Spec.Cluster.Masters = append(cr.Spec.Cluster.Masters, v1alpha1.ClusterNode{})
+		}
+		if masters > 0 {
+			cr.Spec.KVM.K8sKVM.StorageType = "persistentVolume"
+		}
+		return cr
+	}
+
+	resizing := testPVC("pvc-1", "expandable", "30Gi")
+	resizing.Status.Conditions = []corev1.PersistentVolumeClaimCondition{
+		{
+			Type:   corev1.PersistentVolumeClaimResizing,
+			Status: corev1.ConditionTrue,
+		},
+	}
+	pending := testPVC("pvc-2", "expandable", "30Gi")
+	pending.Status.Phase = corev1.ClaimBound
+	pending.Status.Capacity = corev1.ResourceList{
+		corev1.ResourceStorage: resource.MustParse("15Gi"),
+	}
+	resized := testPVC("pvc-3", "expandable", "30Gi")
+	resized.Status.Phase = corev1.ClaimBound
+	resized.Status.Capacity = corev1.ResourceList{
+		corev1.ResourceStorage: resource.MustParse("30Gi"),
+	}
+
+	testCases := []struct {
+		name               string
+		inputKVMConfig     *v1alpha1.KVMConfig
+		inputPVCs          []corev1.PersistentVolumeClaim
+		expectedConditions map[string]string
+	}{
+		{
+			name:           "case 0: resize phases of PVCs are reported",
+			inputKVMConfig: newKVMConfig(nil, 0),
+			inputPVCs: []corev1.PersistentVolumeClaim{
+				resizing,
+				pending,
+				resized,
+			},
+			expectedConditions: map[string]string{
+				conditionExpansionNotAllowed:     "False",
+				conditionFileSystemResizePending: "False",
+				conditionResizePending:           "True",
+				conditionResizing:                "True",
+				conditionStorageClassMismatch:    "False",
+			},
+		},
+		{
+			name: "case 1: PVCs which cannot be resized as configured are reported",
+			inputKVMConfig: newKVMConfig(map[string]string{
+				key.AnnotationEtcdStorageClass: "fixed",
+				key.AnnotationEtcdVolumeSize:   "30Gi",
+			}, 2),
+			inputPVCs: []corev1.PersistentVolumeClaim{
+				testPVC(key.EtcdPVCName("al9qy", key.VMNumber(0)), "fixed", "15Gi"),
+				testPVC(key.EtcdPVCName("al9qy", key.VMNumber(1)), "expandable", "30Gi"),
+			},
+			expectedConditions: map[string]string{
+				conditionExpansionNotAllowed:     "True",
+				conditionFileSystemResizePending: "False",
+				conditionResizePending:           "False",
+				conditionResizing:                "False",
+				conditionStorageClassMismatch:    "True",
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			objects := []runtime.Object{
+				&storagev1.StorageClass{
+					ObjectMeta: metav1.ObjectMeta{
+						Name: "expandable",
+					},
+					AllowVolumeExpansion: to.BoolP(true),
+				},
+				&storagev1.StorageClass{
+					ObjectMeta: metav1.ObjectMeta{
+						Name: "fixed",
+					},
+				},
+			}
+			for i := range tc.inputPVCs {
+				objects = append(objects, &tc.inputPVCs[i])
+			}
+
+			var err error
+			var newResource *Resource
+			{
+				resourceConfig := DefaultConfig()
+				resourceConfig.G8sClient = apiextfake.NewSimpleClientset(tc.inputKVMConfig)
+				resourceConfig.K8sClient = fake.NewSimpleClientset(objects...)
+				resourceConfig.Logger = microloggertest.New()
+				newResource, err = New(resourceConfig)
+				if err != nil {
+					t.Fatal("expected", nil, "got", err)
+				}
+			}
+
+			err = newResource.ApplyUpdateChange(context.TODO(), tc.inputKVMConfig, []corev1.PersistentVolumeClaim{})
+			if err != nil {
+				t.Fatalf("expected %#v got %#v", nil, err)
+			}
+
+			kvmConfig, err := newResource.g8sClient.ProviderV1alpha1().KVMConfigs(tc.inputKVMConfig.Namespace).Get(context.TODO(), tc.inputKVMConfig.Name, metav1.GetOptions{})
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			conditions := key.ResourceStatusConditions(*kvmConfig, Name)
+			if len(conditions) != len(tc.expectedConditions) {
+				t.Fatalf("expected %d conditions got %#v", len(tc.expectedConditions), conditions)
+			}
+			for _, c := range conditions {
+				if c.Status != tc.expectedConditions[c.Type] {
+					t.Fatalf("expected condition %#q status %#q got %#q", c.Type, tc.expectedConditions[c.Type], c.Status)
+				}
+			}
+		})
+	}
+}
+
+func testPVC(name, storageClass, size string) corev1.PersistentVolumeClaim {
+	return corev1.PersistentVolumeClaim{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: "al9qy",
+			Labels: map[string]string{
+				key.LegacyLabelCluster: "al9qy",
+			},
+		},
+		Spec: corev1.PersistentVolumeClaimSpec{
+			Resources: corev1.ResourceRequirements{
+				Requests: corev1.ResourceList{
+					corev1.ResourceStorage: resource.MustParse(size),
+				},
+			},
+			StorageClassName: to.StringP(storageClass),
+		},
+	}
+}
+
+func Test_Resource_PVC_newOrphanedPVCsDeleteChange(t *testing.T) {
+	dockerDisk := func(nodeID string) corev1.PersistentVolumeClaim {
+		pvc := testPVC(key.PersistentDiskPVCName("al9qy", nodeID, key.PersistentDiskDocker), "", "50G")
+		pvc.Labels[key.LabelPersistentDisk] = key.PersistentDiskDocker
+		return pvc
+	}
+	dataVolume := func(nodeID string, mountTag string) corev1.PersistentVolumeClaim {
+		pvc := testPVC(key.WorkerDataVolumePVCName("al9qy", nodeID, mountTag), "", "100Gi")
+		pvc.Labels[key.LabelDataVolume] = mountTag
+		return pvc
+	}
+
+	testCases := []struct {
+		Name          string
+		CurrentState  []corev1.PersistentVolumeClaim
+		DesiredState  []corev1.PersistentVolumeClaim
+		ExpectedNames []string
+	}{
+		{
+			Name: "case 0: desired disks are kept",
+			CurrentState: []corev1.PersistentVolumeClaim{
+				dockerDisk("a"),
+			},
+			DesiredState: []corev1.PersistentVolumeClaim{
+				dockerDisk("a"),
+			},
+		},
+		{
+			Name: "case 1: disks of removed workers are deleted",
+			CurrentState: []corev1.PersistentVolumeClaim{
+				dockerDisk("a"),
+				dockerDisk("b"),
+			},
+			DesiredState: []corev1.PersistentVolumeClaim{
+				dockerDisk("a"),
+			},
+			ExpectedNames: []string{
+				key.PersistentDiskPVCName("al9qy", "b", key.PersistentDiskDocker),
+			},
+		},
+		{
+			Name: "case 2: other PVCs are not deleted",
+			CurrentState: []corev1.PersistentVolumeClaim{
+				testPVC("pvc-master-etcd-al9qy-0", "g8s-storage", "15Gi"),
+			},
+			DesiredState: []corev1.PersistentVolumeClaim{},
+		},
+		{
+			Name: "case 3: data volumes of removed workers are deleted",
+			CurrentState: []corev1.PersistentVolumeClaim{
+				dataVolume("a", "data"),
+				dataVolume("b", "data"),
+			},
+			DesiredState: []corev1.PersistentVolumeClaim{
+				dataVolume("a", "data"),
+			},
+			ExpectedNames: []string{
+				key.WorkerDataVolumePVCName("al9qy", "b", "data"),
+			},
+		},
+		{
+			Name: "case 4: removed data volumes are deleted",
+			CurrentState: []corev1.PersistentVolumeClaim{
+				dataVolume("a", "data"),
+				dataVolume("a", "scratch"),
+			},
+			DesiredState: []corev1.PersistentVolumeClaim{
+				dataVolume("a", "data"),
+			},
+			ExpectedNames: []string{
+				key.WorkerDataVolumePVCName("al9qy", "a", "scratch"),
+			},
+		},
+	}
+
+	var err error
+	var newResource *Resource
+	{
+		resourceConfig := DefaultConfig()
+		resourceConfig.G8sClient = apiextfake.NewSimpleClientset()
+		resourceConfig.K8sClient = fake.NewSimpleClientset()
+		resourceConfig.Logger = microloggertest.New()
+		newResource, err = New(resourceConfig)
+		if err != nil {
+			t.Fatal("expected", nil, "got", err)
+		}
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.Name, func(t *testing.T) {
+			result, err := newResource.newOrphanedPVCsDeleteChange(context.TODO(), &v1alpha1.KVMConfig{}, tc.CurrentState, tc.DesiredState)
+			if err != nil {
+				t.Fatalf("expected %#v got %#v", nil, err)
+			}
+
+			pvcs, err := toPVCs(result)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if len(pvcs) != len(tc.ExpectedNames) {
+				t.Fatalf("expected %d PVCs got %d", len(tc.ExpectedNames), len(pvcs))
+			}
+			for i, pvc := range pvcs {
+				if pvc.Name != tc.ExpectedNames[i] {
+					t.Fatalf("expected PVC %#q got %#q", tc.ExpectedNames[i], pvc.Name)
+				}
+			}
+		})
+	}
+}
@@ -40,6 +40,19 @@ func (r *Resource) ApplyDeleteChange(ctx context.Context, obj, deleteChange inte
 		r.logger.Debugf(ctx, "the services do not need to be deleted from the Kubernetes API")
 	}
 
+	// The services are gone, so their node ports can be allocated to other
+	// clusters.
+	{
+		updated, err := key.UpdateStatusAnnotation(ctx, r.g8sClient, customObject, key.AnnotationAllocatedNodePorts, nil)
+		if err != nil {
+			return microerror.Mask(err)
+		}
+
+		if updated {
+			r.logger.Debugf(ctx, "released node ports")
+		}
+	}
+
 	return nil
 }
 
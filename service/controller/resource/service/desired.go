@@ -3,8 +3,10 @@ package service
 import (
 	"context"
 
+	"github.com/giantswarm/apiextensions/v3/pkg/apis/provider/v1alpha1"
 	"github.com/giantswarm/microerror"
 	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
 	"github.com/giantswarm/kvm-operator/v4/service/controller/key"
 )
@@ -20,9 +22,93 @@ func (r *Resource) GetDesiredState(ctx context.Context, obj interface{}) (interf
 	var services []*corev1.Service
 
 	services = append(services, newMasterService(customObject))
-	services = append(services, newWorkerService(customObject))
+
+	{
+		workerService, err := newWorkerService(customObject)
+		if err != nil {
+			return nil, microerror.Mask(err)
+		}
+
+		if !key.IsDeleted(&customObject) && workerService.Spec.Type != corev1.ServiceTypeClusterIP {
+			// Node ports are allocated and persisted by the nodeportstatus
+			// resource, which is executed before this resource.
+			allocated := key.AllocatedNodePorts(customObject)
+			for i, p := range workerService.Spec.Ports {
+				if p.NodePort == 0 {
+					workerService.Spec.Ports[i].NodePort = allocated[p.Name]
+				}
+			}
+
+			others, err := r.otherNodePortClusters(ctx, customObject)
+			if err != nil {
+				return nil, microerror.Mask(err)
+			}
+
+			err = validateNodePorts(customObject, others)
+			if err != nil {
+				return nil, microerror.Mask(err)
+			}
+		}
+
+		services = append(services, workerService)
+	}
 
 	r.logger.Debugf(ctx, "computed the %d new services", len(services))
 
 	return services, nil
 }
+
+// otherNodePortClusters returns all KVMConfigs of the management cluster except
+// the given one whose worker service uses node ports.
+func (r *Resource) otherNodePortClusters(ctx context.Context, customObject v1alpha1.KVMConfig) ([]v1alpha1.KVMConfig, error) {
+	list, err := r.g8sClient.ProviderV1alpha1().KVMConfigs(metav1.NamespaceAll).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, microerror.Mask(err)
+	}
+
+	var others []v1alpha1.KVMConfig
+	for _, other := range list.Items {
+		if key.ClusterID(other) == key.ClusterID(customObject) {
+			continue
+		}
+		if !key.WorkerServiceUsesNodePorts(other) {
+			continue
+		}
+
+		others = append(others, other)
+	}
+
+	return others, nil
+}
+
+// validateNodePorts ensures the node ports explicitly requested by the port
+// mappings of the given KVMConfig are neither requested twice nor requested by
+// or allocated to any other cluster on the management cluster. Node ports
+// allocated to another cluster are reallocated once it is reconciled again.
+func validateNodePorts(customObject v1alpha1.KVMConfig, others []v1alpha1.KVMConfig) error {
+	requested := map[int32]string{}
+	for _, p := range customObject.Spec.KVM.PortMappings {
+		if p.NodePort == 0 {
+			continue
+		}
+		if name, ok := requested[int32(p.NodePort)]; ok {
+			return microerror.Maskf(nodePortCollisionError, "node port %d is requested by port mappings %#q and %#q", p.NodePort, name, p.Name)
+		}
+		requested[int32(p.NodePort)] = p.Name
+	}
+
+	for _, other := range others {
+		for _, p := range other.Spec.KVM.PortMappings {
+			if _, ok := requested[int32(p.NodePort)]; ok {
+				return microerror.Maskf(nodePortCollisionError, "node port %d is already requested by cluster %#q", p.NodePort, key.ClusterID(other))
+			}
+		}
+		for _, port := range key.AllocatedNodePorts(other) {
+			if _, ok := requested[port]; ok {
+				return microerror.Maskf(nodePortCollisionError, "node port %d is already allocated to cluster %#q", port, key.ClusterID(other))
+			}
+		}
+	}
+
+	return nil
+}
@@ -2,12 +2,17 @@ package service
 
 import (
 	"context"
+	"reflect"
 	"testing"
 
 	"github.com/giantswarm/apiextensions/v3/pkg/apis/provider/v1alpha1"
+	apiextfake "github.com/giantswarm/apiextensions/v3/pkg/clientset/versioned/fake"
 	"github.com/giantswarm/micrologger/microloggertest"
 	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/client-go/kubernetes/fake"
+
+	"github.com/giantswarm/kvm-operator/v4/service/controller/key"
 )
 
 func Test_Resource_Service_GetDesiredState(t *testing.T) {
@@ -87,6 +92,7 @@ func Test_Resource_Service_GetDesiredState(t *testing.T) {
 	var newResource *Resource
 	{
 		resourceConfig := DefaultConfig()
+		resourceConfig.G8sClient = apiextfake.NewSimpleClientset()
 		resourceConfig.K8sClient = fake.NewSimpleClientset()
 		resourceConfig.Logger = microloggertest.New()
 		newResource, err = New(resourceConfig)
@@ -143,3 +149,223 @@ func testGetWorkerCount(services []*corev1.Service) int {
 
 	return count
 }
+
+func Test_Resource_Service_GetDesiredState_workerService(t *testing.T) {
+	otherCluster := &v1alpha1.KVMConfig{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "b3cde",
+			Namespace: "default",
+			Annotations: map[string]string{
+				key.AnnotationAllocatedNodePorts: `{"https":31002}`,
+			},
+		},
+		Spec: v1alpha1.KVMConfigSpec{
+			Cluster: v1alpha1.Cluster{
+				ID: "b3cde",
+			},
+			KVM: v1alpha1.KVMConfigSpecKVM{
+				PortMappings: []v1alpha1.KVMConfigSpecKVMPortMappings{
+					{Name: "http", NodePort: 31000, TargetPort: 30010},
+				},
+			},
+		},
+	}
+
+	testCases := []struct {
+		Name                string
+		Annotation          string
+		NodePort            int
+		ExpectedType        corev1.ServiceType
+		ExpectedNodePort    int32
+		ExpectedAnnotations map[string]string
+		ExpectedRanges      []string
+		ErrorMatcher        func(error) bool
+	}{
+		{
+			Name:             "NodePort by default",
+			NodePort:         31001,
+			ExpectedType:     corev1.ServiceTypeNodePort,
+			ExpectedNodePort: 31001,
+		},
+		{
+			Name:             "ClusterIP drops node ports",
+			Annotation:       `{"type":"ClusterIP"}`,
+			NodePort:         31000,
+			ExpectedType:     corev1.ServiceTypeClusterIP,
+			ExpectedNodePort: 0,
+		},
+		{
+			Name:             "LoadBalancer with annotations and source ranges",
+			Annotation:       `{"type":"LoadBalancer","annotations":{"metallb.universe.tf/address-pool":"ingress"},"loadBalancerSourceRanges":["10.0.0.0/8"]}`,
+			NodePort:         31001,
+			ExpectedType:     corev1.ServiceTypeLoadBalancer,
+			ExpectedNodePort: 31001,
+			ExpectedAnnotations: map[string]string{
+				"metallb.universe.tf/address-pool": "ingress",
+			},
+			ExpectedRanges: []string{"10.0.0.0/8"},
+		},
+		{
+			Name:         "node port of other cluster",
+			NodePort:     31000,
+			ErrorMatcher: IsNodePortCollision,
+		},
+		{
+			Name:         "node port allocated to other cluster",
+			NodePort:     31002,
+			ErrorMatcher: IsNodePortCollision,
+		},
+		{
+			Name:         "invalid service configuration",
+			Annotation:   `{"type":"ExternalName"}`,
+			ErrorMatcher: IsInvalidConfig,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.Name, func(t *testing.T) {
+			var err error
+			var newResource *Resource
+			{
+				resourceConfig := DefaultConfig()
+				resourceConfig.G8sClient = apiextfake.NewSimpleClientset(otherCluster)
+				resourceConfig.K8sClient = fake.NewSimpleClientset()
+				resourceConfig.Logger = microloggertest.New()
+				newResource, err = New(resourceConfig)
+				if err != nil {
+					t.Fatal("expected", nil, "got", err)
+				}
+			}
+
+			cr := &v1alpha1.KVMConfig{
+				ObjectMeta: metav1.ObjectMeta{
+					Annotations: map[string]string{},
+				},
+				Spec: v1alpha1.KVMConfigSpec{
+					Cluster: v1alpha1.Cluster{
+						ID: "al9qy",
+					},
+					KVM: v1alpha1.KVMConfigSpecKVM{
+						PortMappings: []v1alpha1.KVMConfigSpecKVMPortMappings{
+							{Name: "http", NodePort: tc.NodePort, TargetPort: 30010},
+						},
+					},
+				},
+			}
+			if tc.Annotation != "" {
+				cr.Annotations[key.AnnotationWorkerService] = tc.Annotation
+			}
+
+			result, err := newResource.GetDesiredState(context.TODO(), cr)
+			switch {
+			case err == nil && tc.ErrorMatcher == nil:
+				// correct; carry on
+			case err != nil && tc.ErrorMatcher == nil:
+				t.Fatalf("error == %#v, want nil", err)
+			case err == nil && tc.ErrorMatcher != nil:
+				t.Fatalf("error == nil, want non-nil")
+			case !tc.ErrorMatcher(err):
+				t.Fatalf("error == %#v, want matching", err)
+			case tc.ErrorMatcher(err):
+				return
+			}
+
+			services, err := toServices(result)
+			if err != nil {
+				t.Fatal(err)
+			}
+			worker, err := getServiceByName(services, key.WorkerID)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if worker.Spec.Type != tc.ExpectedType {
+				t.Fatalf("expected type %#q got %#q", tc.ExpectedType, worker.Spec.Type)
+			}
+			if worker.Spec.Ports[0].NodePort != tc.ExpectedNodePort {
+				t.Fatalf("expected node port %d got %d", tc.ExpectedNodePort, worker.Spec.Ports[0].NodePort)
+			}
+			for k, v := range tc.ExpectedAnnotations {
+				if worker.Annotations[k] != v {
+					t.Fatalf("expected annotation %#q to be %#q got %#q", k, v, worker.Annotations[k])
+				}
+			}
+			if !reflect.DeepEqual(worker.Spec.LoadBalancerSourceRanges, tc.ExpectedRanges) {
+				t.Fatalf("expected source ranges %#v got %#v", tc.ExpectedRanges, worker.Spec.LoadBalancerSourceRanges)
+			}
+		})
+	}
+}
+
+func Test_Resource_Service_GetDesiredState_nodePortAllocation(t *testing.T) {
+	cr := &v1alpha1.KVMConfig{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "b2bbb",
+			Namespace: "default",
+			Annotations: map[string]string{
+				key.AnnotationAllocatedNodePorts: `{"http":30101}`,
+			},
+		},
+		Spec: v1alpha1.KVMConfigSpec{
+			Cluster: v1alpha1.Cluster{
+				ID: "b2bbb",
+			},
+			KVM: v1alpha1.KVMConfigSpecKVM{
+				PortMappings: []v1alpha1.KVMConfigSpecKVMPortMappings{
+					{Name: "http", TargetPort: 30010},
+				},
+			},
+		},
+	}
+
+	g8sClient := apiextfake.NewSimpleClientset(cr)
+
+	var err error
+	var newResource *Resource
+	{
+		resourceConfig := DefaultConfig()
+		resourceConfig.G8sClient = g8sClient
+		resourceConfig.K8sClient = fake.NewSimpleClientset()
+		resourceConfig.Logger = microloggertest.New()
+		newResource, err = New(resourceConfig)
+		if err != nil {
+			t.Fatal("expected", nil, "got", err)
+		}
+	}
+
+	result, err := newResource.GetDesiredState(context.TODO(), cr)
+	if err != nil {
+		t.Fatalf("expected %#v got %#v", nil, err)
+	}
+
+	services, err := toServices(result)
+	if err != nil {
+		t.Fatal(err)
+	}
+	worker, err := getServiceByName(services, key.WorkerID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if worker.Spec.Ports[0].NodePort != 30101 {
+		t.Fatalf("expected node port %d got %d", 30101, worker.Spec.Ports[0].NodePort)
+	}
+
+	for _, a := range g8sClient.Actions() {
+		if a.GetVerb() != "list" {
+			t.Fatalf("expected computing the desired state to only list KVMConfigs got %s", a.GetVerb())
+		}
+	}
+
+	err = newResource.ApplyDeleteChange(context.TODO(), cr, nil)
+	if err != nil {
+		t.Fatalf("expected %#v got %#v", nil, err)
+	}
+
+	deleted, err := g8sClient.ProviderV1alpha1().KVMConfigs(cr.Namespace).Get(context.TODO(), cr.Name, metav1.GetOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(key.AllocatedNodePorts(*deleted)) != 0 {
+		t.Fatalf("expected node ports to be released got %#v", key.AllocatedNodePorts(*deleted))
+	}
+}
@@ -11,6 +11,15 @@ func IsInvalidConfig(err error) bool {
 	return microerror.Cause(err) == invalidConfigError
 }
 
+var nodePortCollisionError = &microerror.Error{
+	Kind: "nodePortCollisionError",
+}
+
+// IsNodePortCollision asserts nodePortCollisionError.
+func IsNodePortCollision(err error) bool {
+	return microerror.Cause(err) == nodePortCollisionError
+}
+
 var notFoundError = &microerror.Error{
 	Kind: "notFoundError",
 }
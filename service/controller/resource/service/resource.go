@@ -3,6 +3,7 @@ package service
 import (
 	"reflect"
 
+	"github.com/giantswarm/apiextensions/v3/pkg/clientset/versioned"
 	"github.com/giantswarm/microerror"
 	"github.com/giantswarm/micrologger"
 	corev1 "k8s.io/api/core/v1"
@@ -17,6 +18,7 @@ const (
 // Config represents the configuration used to create a new service resource.
 type Config struct {
 	// Dependencies.
+	G8sClient versioned.Interface
 	K8sClient kubernetes.Interface
 	Logger    micrologger.Logger
 }
@@ -26,6 +28,7 @@ type Config struct {
 func DefaultConfig() Config {
 	return Config{
 		// Dependencies.
+		G8sClient: nil,
 		K8sClient: nil,
 		Logger:    nil,
 	}
@@ -34,6 +37,7 @@ func DefaultConfig() Config {
 // Resource implements the service resource.
 type Resource struct {
 	// Dependencies.
+	g8sClient versioned.Interface
 	k8sClient kubernetes.Interface
 	logger    micrologger.Logger
 }
@@ -41,6 +45,9 @@ type Resource struct {
 // New creates a new configured service resource.
 func New(config Config) (*Resource, error) {
 	// Dependencies.
+	if config.G8sClient == nil {
+		return nil, microerror.Maskf(invalidConfigError, "config.G8sClient must not be empty")
+	}
 	if config.K8sClient == nil {
 		return nil, microerror.Maskf(invalidConfigError, "config.K8sClient must not be empty")
 	}
@@ -50,6 +57,7 @@ func New(config Config) (*Resource, error) {
 
 	newService := &Resource{
 		// Dependencies.
+		g8sClient: config.G8sClient,
 		k8sClient: config.K8sClient,
 		logger:    config.Logger,
 	}
@@ -105,6 +113,10 @@ func isServiceModified(a, b *corev1.Service) bool {
 		return true
 	}
 
+	if !reflect.DeepEqual(a.Spec.LoadBalancerSourceRanges, b.Spec.LoadBalancerSourceRanges) {
+		return true
+	}
+
 	return false
 }
 
@@ -143,6 +155,11 @@ func portsEqual(a, b *corev1.Service) bool {
 		if !reflect.DeepEqual(portA.Protocol, portB.Protocol) {
 			return false
 		}
+		// Node ports chosen by the Kubernetes API are not part of the desired
+		// state.
+		if portA.NodePort != 0 && portA.NodePort != portB.NodePort {
+			return false
+		}
 	}
 	return true
 }
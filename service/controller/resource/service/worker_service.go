@@ -2,13 +2,19 @@ package service
 
 import (
 	"github.com/giantswarm/apiextensions/v3/pkg/apis/provider/v1alpha1"
+	"github.com/giantswarm/microerror"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
 	"github.com/giantswarm/kvm-operator/v4/service/controller/key"
 )
 
-func newWorkerService(customObject v1alpha1.KVMConfig) *corev1.Service {
+func newWorkerService(customObject v1alpha1.KVMConfig) (*corev1.Service, error) {
+	config, err := key.WorkerService(customObject)
+	if err != nil {
+		return nil, microerror.Maskf(invalidConfigError, "%s", err)
+	}
+
 	service := &corev1.Service{
 		TypeMeta: metav1.TypeMeta{
 			Kind:       "service",
@@ -33,7 +39,7 @@ func newWorkerService(customObject v1alpha1.KVMConfig) *corev1.Service {
 			},
 		},
 		Spec: corev1.ServiceSpec{
-			Type:  corev1.ServiceTypeNodePort,
+			Type:  config.Type,
 			Ports: key.PortMappings(customObject),
 			Selector: map[string]string{
 				key.LabelApp:     key.WorkerID,
@@ -42,5 +48,18 @@ func newWorkerService(customObject v1alpha1.KVMConfig) *corev1.Service {
 		},
 	}
 
-	return service
+	switch config.Type {
+	case corev1.ServiceTypeClusterIP:
+		// Node ports must not be set for ClusterIP services.
+		for i := range service.Spec.Ports {
+			service.Spec.Ports[i].NodePort = 0
+		}
+	case corev1.ServiceTypeLoadBalancer:
+		for k, v := range config.Annotations {
+			service.Annotations[k] = v
+		}
+		service.Spec.LoadBalancerSourceRanges = config.LoadBalancerSourceRanges
+	}
+
+	return service, nil
 }
@@ -0,0 +1,100 @@
+package shardstatus
+
+import (
+	"context"
+
+	"github.com/giantswarm/apiextensions/v3/pkg/apis/provider/v1alpha1"
+	"github.com/giantswarm/microerror"
+	"github.com/giantswarm/operatorkit/v5/pkg/controller/context/reconciliationcanceledcontext"
+	corev1 "k8s.io/api/core/v1"
+
+	"github.com/giantswarm/kvm-operator/v4/pkg/shard"
+	"github.com/giantswarm/kvm-operator/v4/service/controller/key"
+)
+
+const (
+	// conditionClusterLabelInvalid is true while the KVMConfig is not labeled
+	// with its cluster ID and clusters are distributed across shards.
+	conditionClusterLabelInvalid = "ClusterLabelInvalid"
+
+	eventReasonClusterLabelInvalid = "ClusterLabelInvalid"
+)
+
+// EnsureCreated cancels the reconciliation of KVMConfigs which are not
+// labeled with their cluster ID while clusters are distributed across shards.
+func (r *Resource) EnsureCreated(ctx context.Context, obj interface{}) error {
+	cr, err := key.ToCustomObject(obj)
+	if err != nil {
+		return microerror.Mask(err)
+	}
+
+	valid, err := r.validate(ctx, cr)
+	if err != nil {
+		return microerror.Mask(err)
+	}
+
+	if !valid {
+		r.logger.Debugf(ctx, "canceling reconciliation")
+		reconciliationcanceledcontext.SetCanceled(ctx)
+
+		return nil
+	}
+
+	return nil
+}
+
+// validate returns whether the given KVMConfig can be assigned to a shard.
+// The result is recorded as condition of the shardstatus resource status and
+// a warning event is emitted when the KVMConfig becomes invalid.
+func (r *Resource) validate(ctx context.Context, cr v1alpha1.KVMConfig) (bool, error) {
+	err := r.shard.ValidateCluster(cr)
+	if shard.IsInvalidCluster(err) {
+		r.logger.Debugf(ctx, "KVMConfig is invalid: %s", err)
+
+		if !conditionTrue(cr, conditionClusterLabelInvalid) {
+			r.eventRecorder.Eventf(&cr, corev1.EventTypeWarning, eventReasonClusterLabelInvalid, "KVMConfig is not reconciled: %s", err)
+		}
+	} else if err != nil {
+		return false, microerror.Mask(err)
+	}
+
+	{
+		conditions := []v1alpha1.StatusClusterResourceCondition{
+			{
+				Status: conditionStatus(err != nil),
+				Type:   conditionClusterLabelInvalid,
+			},
+		}
+
+		updated, err := key.UpdateResourceStatus(ctx, r.g8sClient, cr, Name, conditions)
+		if err != nil {
+			return false, microerror.Mask(err)
+		}
+
+		if updated {
+			r.logger.Debugf(ctx, "updated status with cluster label validation")
+		}
+	}
+
+	return err == nil, nil
+}
+
+// conditionTrue returns whether the given condition of the shardstatus
+// resource status is true. Missing conditions are considered false.
+func conditionTrue(cr v1alpha1.KVMConfig, conditionType string) bool {
+	for _, c := range key.ResourceStatusConditions(cr, Name) {
+		if c.Type == conditionType {
+			return c.Status == string(corev1.ConditionTrue)
+		}
+	}
+
+	return false
+}
+
+func conditionStatus(b bool) string {
+	if b {
+		return string(corev1.ConditionTrue)
+	}
+
+	return string(corev1.ConditionFalse)
+}
@@ -0,0 +1,147 @@
+package shardstatus
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/giantswarm/apiextensions/v3/pkg/apis/provider/v1alpha1"
+	"github.com/giantswarm/apiextensions/v3/pkg/clientset/versioned/fake"
+	"github.com/giantswarm/micrologger/microloggertest"
+	"github.com/giantswarm/operatorkit/v5/pkg/controller/context/reconciliationcanceledcontext"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
+
+	"github.com/giantswarm/kvm-operator/v4/pkg/label"
+	"github.com/giantswarm/kvm-operator/v4/pkg/shard"
+	"github.com/giantswarm/kvm-operator/v4/service/controller/key"
+)
+
+func Test_EnsureCreated(t *testing.T) {
+	testCases := []struct {
+		name              string
+		shard             shard.Shard
+		labels            map[string]string
+		currentCondition  string
+		expectedCondition string
+		expectedCanceled  bool
+		expectedEvents    int
+	}{
+		{
+			name:              "case 0: labeled clusters are reconciled",
+			shard:             shard.Shard{Index: 0, Count: 2},
+			labels:            map[string]string{label.Cluster: "al9qy"},
+			expectedCondition: "False",
+		},
+		{
+			name:              "case 1: unlabeled clusters are reconciled without sharding",
+			shard:             shard.Shard{},
+			expectedCondition: "False",
+		},
+		{
+			name:              "case 2: unlabeled clusters are skipped and reported",
+			shard:             shard.Shard{Index: 0, Count: 2},
+			expectedCondition: "True",
+			expectedCanceled:  true,
+			expectedEvents:    1,
+		},
+		{
+			name:              "case 3: clusters labeled with another cluster ID are skipped and reported",
+			shard:             shard.Shard{Index: 0, Count: 2},
+			labels:            map[string]string{label.Cluster: "b2bbb"},
+			expectedCondition: "True",
+			expectedCanceled:  true,
+			expectedEvents:    1,
+		},
+		{
+			name:              "case 4: clusters still unlabeled are skipped without events",
+			shard:             shard.Shard{Index: 0, Count: 2},
+			currentCondition:  "True",
+			expectedCondition: "True",
+			expectedCanceled:  true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			cr := &v1alpha1.KVMConfig{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "al9qy",
+					Namespace: "default",
+					Labels:    tc.labels,
+				},
+				Spec: v1alpha1.KVMConfigSpec{
+					Cluster: v1alpha1.Cluster{
+						ID: "al9qy",
+					},
+				},
+			}
+			if tc.currentCondition != "" {
+				cr.Status.Cluster.Resources = []v1alpha1.StatusClusterResource{
+					{
+						Conditions: []v1alpha1.StatusClusterResourceCondition{
+							{
+								Status: tc.currentCondition,
+								Type:   conditionClusterLabelInvalid,
+							},
+						},
+						Name: Name,
+					},
+				}
+			}
+
+			recorder := record.NewFakeRecorder(10)
+
+			var r *Resource
+			{
+				c := Config{
+					EventRecorder: recorder,
+					G8sClient:     fake.NewSimpleClientset(cr),
+					Logger:        microloggertest.New(),
+
+					Shard: tc.shard,
+				}
+
+				var err error
+				r, err = New(c)
+				if err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			ctx := reconciliationcanceledcontext.NewContext(context.Background(), make(chan struct{}))
+
+			err := r.EnsureCreated(ctx, cr)
+			if err != nil {
+				t.Fatalf("error == %#v, want nil", err)
+			}
+
+			if reconciliationcanceledcontext.IsCanceled(ctx) != tc.expectedCanceled {
+				t.Fatalf("expected canceled %t got %t", tc.expectedCanceled, reconciliationcanceledcontext.IsCanceled(ctx))
+			}
+
+			kvmConfig, err := r.g8sClient.ProviderV1alpha1().KVMConfigs(cr.GetNamespace()).Get(context.Background(), cr.GetName(), metav1.GetOptions{})
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			conditions := key.ResourceStatusConditions(*kvmConfig, Name)
+			if len(conditions) != 1 || conditions[0].Type != conditionClusterLabelInvalid || conditions[0].Status != tc.expectedCondition {
+				t.Fatalf("expected condition %#q status %#q got %#v", conditionClusterLabelInvalid, tc.expectedCondition, conditions)
+			}
+
+			var events []string
+			for len(recorder.Events) > 0 {
+				events = append(events, <-recorder.Events)
+			}
+			if len(events) != tc.expectedEvents {
+				t.Fatalf("expected %d events got %v", tc.expectedEvents, events)
+			}
+			for _, e := range events {
+				if !strings.Contains(e, eventReasonClusterLabelInvalid) {
+					t.Fatalf("expected event with reason %#q got %#q", eventReasonClusterLabelInvalid, e)
+				}
+			}
+		})
+	}
+}
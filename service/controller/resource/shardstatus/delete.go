@@ -0,0 +1,38 @@
+package shardstatus
+
+import (
+	"context"
+
+	"github.com/giantswarm/microerror"
+	"github.com/giantswarm/operatorkit/v5/pkg/controller/context/finalizerskeptcontext"
+	"github.com/giantswarm/operatorkit/v5/pkg/controller/context/reconciliationcanceledcontext"
+
+	"github.com/giantswarm/kvm-operator/v4/service/controller/key"
+)
+
+// EnsureDeleted keeps the finalizers of invalid KVMConfigs, so that their
+// resources are not left behind when the instance reconciling their VM pods
+// cannot clean them up.
+func (r *Resource) EnsureDeleted(ctx context.Context, obj interface{}) error {
+	cr, err := key.ToCustomObject(obj)
+	if err != nil {
+		return microerror.Mask(err)
+	}
+
+	valid, err := r.validate(ctx, cr)
+	if err != nil {
+		return microerror.Mask(err)
+	}
+
+	if !valid {
+		r.logger.Debugf(ctx, "keeping finalizers")
+		finalizerskeptcontext.SetKept(ctx)
+
+		r.logger.Debugf(ctx, "canceling reconciliation")
+		reconciliationcanceledcontext.SetCanceled(ctx)
+
+		return nil
+	}
+
+	return nil
+}
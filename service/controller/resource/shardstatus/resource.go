@@ -0,0 +1,61 @@
+package shardstatus
+
+import (
+	"github.com/giantswarm/apiextensions/v3/pkg/clientset/versioned"
+	"github.com/giantswarm/microerror"
+	"github.com/giantswarm/micrologger"
+	"k8s.io/client-go/tools/record"
+
+	"github.com/giantswarm/kvm-operator/v4/pkg/shard"
+)
+
+const (
+	Name = "shardstatus"
+)
+
+type Config struct {
+	EventRecorder record.EventRecorder
+	G8sClient     versioned.Interface
+	Logger        micrologger.Logger
+
+	Shard shard.Shard
+}
+
+// Resource validates that KVMConfigs can be assigned to a shard while
+// clusters are distributed across operator instances. The reconciliation of
+// invalid KVMConfigs is canceled and reported via a condition of the
+// shardstatus resource status and a warning event, so that the other
+// clusters of the shard are still reconciled.
+type Resource struct {
+	eventRecorder record.EventRecorder
+	g8sClient     versioned.Interface
+	logger        micrologger.Logger
+
+	shard shard.Shard
+}
+
+func New(config Config) (*Resource, error) {
+	if config.EventRecorder == nil {
+		return nil, microerror.Maskf(invalidConfigError, "%T.EventRecorder must not be empty", config)
+	}
+	if config.G8sClient == nil {
+		return nil, microerror.Maskf(invalidConfigError, "%T.G8sClient must not be empty", config)
+	}
+	if config.Logger == nil {
+		return nil, microerror.Maskf(invalidConfigError, "%T.Logger must not be empty", config)
+	}
+
+	r := &Resource{
+		eventRecorder: config.EventRecorder,
+		g8sClient:     config.G8sClient,
+		logger:        config.Logger,
+
+		shard: config.Shard,
+	}
+
+	return r, nil
+}
+
+func (r *Resource) Name() string {
+	return Name
+}
@@ -11,6 +11,7 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	"github.com/giantswarm/kvm-operator/v4/service/controller/key"
+	"github.com/giantswarm/kvm-operator/v4/service/metric"
 )
 
 const (
@@ -67,6 +68,7 @@ func (r *Resource) EnsureCreated(ctx context.Context, obj interface{}) error {
 			if err != nil {
 				return microerror.Mask(err)
 			}
+			metric.UnhealthyNodeTerminationsCounter.WithLabelValues(key.ClusterID(customResource), key.ClusterCustomer(customResource)).Inc()
 		}
 
 		// reset tick counters on all nodes in cluster to have a graceful period after terminating nodes
@@ -0,0 +1,61 @@
+package tlsroute
+
+import (
+	"context"
+
+	"github.com/giantswarm/microerror"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+func (r *Resource) ApplyCreateChange(ctx context.Context, obj, createChange interface{}) error {
+	routesToCreate, err := toRoutes(createChange)
+	if err != nil {
+		return microerror.Mask(err)
+	}
+
+	if len(routesToCreate) != 0 {
+		r.logger.Debugf(ctx, "creating the TLS routes in the Kubernetes API")
+
+		for _, route := range routesToCreate {
+			_, err := r.dynClient.Resource(tlsRouteResource).Namespace(route.GetNamespace()).Create(ctx, route, metav1.CreateOptions{})
+			if apierrors.IsAlreadyExists(err) {
+				// fall through
+			} else if err != nil {
+				return microerror.Mask(err)
+			}
+		}
+
+		r.logger.Debugf(ctx, "created the TLS routes in the Kubernetes API")
+	} else {
+		r.logger.Debugf(ctx, "the TLS routes do not need to be created in the Kubernetes API")
+	}
+
+	return nil
+}
+
+func (r *Resource) newCreateChange(ctx context.Context, obj, currentState, desiredState interface{}) (interface{}, error) {
+	currentRoutes, err := toRoutes(currentState)
+	if err != nil {
+		return nil, microerror.Mask(err)
+	}
+	desiredRoutes, err := toRoutes(desiredState)
+	if err != nil {
+		return nil, microerror.Mask(err)
+	}
+
+	r.logger.Debugf(ctx, "finding out which TLS routes have to be created")
+
+	var routesToCreate []*unstructured.Unstructured
+
+	for _, desiredRoute := range desiredRoutes {
+		if !containsRoute(currentRoutes, desiredRoute) {
+			routesToCreate = append(routesToCreate, desiredRoute)
+		}
+	}
+
+	r.logger.Debugf(ctx, "found %d TLS routes that have to be created", len(routesToCreate))
+
+	return routesToCreate, nil
+}
@@ -0,0 +1,66 @@
+package tlsroute
+
+import (
+	"context"
+
+	"github.com/giantswarm/microerror"
+	"github.com/giantswarm/operatorkit/v5/pkg/controller/context/finalizerskeptcontext"
+	"github.com/giantswarm/operatorkit/v5/pkg/controller/context/resourcecanceledcontext"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	"github.com/giantswarm/kvm-operator/v4/service/controller/key"
+)
+
+func (r *Resource) GetCurrentState(ctx context.Context, obj interface{}) (interface{}, error) {
+	customObject, err := key.ToCustomObject(obj)
+	if err != nil {
+		return nil, microerror.Mask(err)
+	}
+
+	r.logger.Debugf(ctx, "looking for TLS routes in the Kubernetes API")
+
+	var routes []*unstructured.Unstructured
+
+	namespace := key.ClusterNamespace(customObject)
+	routeNames := []string{
+		APIID,
+		EtcdID,
+	}
+
+	for _, name := range routeNames {
+		route, err := r.dynClient.Resource(tlsRouteResource).Namespace(namespace).Get(ctx, name, metav1.GetOptions{})
+		if apierrors.IsNotFound(err) {
+			r.logger.Debugf(ctx, "did not find TLS route %#q in the Kubernetes API", name)
+			// fall through
+		} else if err != nil {
+			return nil, microerror.Mask(err)
+		} else {
+			r.logger.Debugf(ctx, "found TLS route %#q in the Kubernetes API", name)
+			routes = append(routes, route)
+		}
+	}
+
+	r.logger.Debugf(ctx, "found %d TLS routes in the Kubernetes API", len(routes))
+
+	// In case a cluster deletion happens, the TLS routes are still needed to
+	// reach the workload cluster API while draining its nodes. As long as pods
+	// are there we delay the deletion of the routes, just like for ingresses.
+	if key.IsDeleted(&customObject) {
+		list, err := r.k8sClient.CoreV1().Pods(namespace).List(ctx, metav1.ListOptions{})
+		if err != nil {
+			return nil, microerror.Mask(err)
+		}
+		if len(list.Items) != 0 {
+			r.logger.Debugf(ctx, "cannot finish deletion of TLS routes due to existing pods")
+			resourcecanceledcontext.SetCanceled(ctx)
+			finalizerskeptcontext.SetKept(ctx)
+			r.logger.Debugf(ctx, "canceling resource")
+
+			return nil, nil
+		}
+	}
+
+	return routes, nil
+}
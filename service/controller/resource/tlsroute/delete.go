@@ -0,0 +1,74 @@
+package tlsroute
+
+import (
+	"context"
+
+	"github.com/giantswarm/microerror"
+	"github.com/giantswarm/operatorkit/v5/pkg/resource/crud"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+func (r *Resource) ApplyDeleteChange(ctx context.Context, obj, deleteChange interface{}) error {
+	routesToDelete, err := toRoutes(deleteChange)
+	if err != nil {
+		return microerror.Mask(err)
+	}
+
+	if len(routesToDelete) != 0 {
+		r.logger.Debugf(ctx, "deleting the TLS routes in the Kubernetes API")
+
+		for _, route := range routesToDelete {
+			err := r.dynClient.Resource(tlsRouteResource).Namespace(route.GetNamespace()).Delete(ctx, route.GetName(), metav1.DeleteOptions{})
+			if apierrors.IsNotFound(err) {
+				// fall through
+			} else if err != nil {
+				return microerror.Mask(err)
+			}
+		}
+
+		r.logger.Debugf(ctx, "deleted the TLS routes in the Kubernetes API")
+	} else {
+		r.logger.Debugf(ctx, "the TLS routes do not need to be deleted from the Kubernetes API")
+	}
+
+	return nil
+}
+
+func (r *Resource) NewDeletePatch(ctx context.Context, obj, currentState, desiredState interface{}) (*crud.Patch, error) {
+	delete, err := r.newDeleteChange(ctx, obj, currentState, desiredState)
+	if err != nil {
+		return nil, microerror.Mask(err)
+	}
+
+	patch := crud.NewPatch()
+	patch.SetDeleteChange(delete)
+
+	return patch, nil
+}
+
+func (r *Resource) newDeleteChange(ctx context.Context, obj, currentState, desiredState interface{}) (interface{}, error) {
+	currentRoutes, err := toRoutes(currentState)
+	if err != nil {
+		return nil, microerror.Mask(err)
+	}
+	desiredRoutes, err := toRoutes(desiredState)
+	if err != nil {
+		return nil, microerror.Mask(err)
+	}
+
+	r.logger.Debugf(ctx, "finding out which TLS routes have to be deleted")
+
+	var routesToDelete []*unstructured.Unstructured
+
+	for _, currentRoute := range currentRoutes {
+		if containsRoute(desiredRoutes, currentRoute) {
+			routesToDelete = append(routesToDelete, currentRoute)
+		}
+	}
+
+	r.logger.Debugf(ctx, "found %d TLS routes that have to be deleted", len(routesToDelete))
+
+	return routesToDelete, nil
+}
@@ -0,0 +1,83 @@
+package tlsroute
+
+import (
+	"context"
+
+	"github.com/giantswarm/apiextensions/v3/pkg/apis/provider/v1alpha1"
+	"github.com/giantswarm/microerror"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	"github.com/giantswarm/kvm-operator/v4/service/controller/key"
+)
+
+func (r *Resource) GetDesiredState(ctx context.Context, obj interface{}) (interface{}, error) {
+	customObject, err := key.ToCustomObject(obj)
+	if err != nil {
+		return nil, microerror.Mask(err)
+	}
+
+	r.logger.Debugf(ctx, "computing the new TLS routes")
+
+	routes := []*unstructured.Unstructured{
+		r.newRoute(customObject, APIID, key.ClusterAPIEndpoint(customObject), customObject.Spec.Cluster.Kubernetes.API.SecurePort),
+		r.newRoute(customObject, EtcdID, customObject.Spec.Cluster.Etcd.Domain, 2379),
+	}
+
+	r.logger.Debugf(ctx, "computed the %d new TLS routes", len(routes))
+
+	return routes, nil
+}
+
+// newRoute returns a TLSRoute passing TLS connections for the given host
+// through to the given port of the master service. Fields defaulted by the
+// Gateway API are set explicitly so that the desired and current state can be
+// compared.
+func (r *Resource) newRoute(customObject v1alpha1.KVMConfig, name string, host string, port int) *unstructured.Unstructured {
+	parentRef := map[string]interface{}{
+		"group":     tlsRouteResource.Group,
+		"kind":      "Gateway",
+		"name":      r.gatewayName,
+		"namespace": r.gatewayNamespace,
+	}
+	if r.gatewaySectionName != "" {
+		parentRef["sectionName"] = r.gatewaySectionName
+	}
+
+	route := &unstructured.Unstructured{
+		Object: map[string]interface{}{
+			"spec": map[string]interface{}{
+				"parentRefs": []interface{}{
+					parentRef,
+				},
+				"hostnames": []interface{}{
+					host,
+				},
+				"rules": []interface{}{
+					map[string]interface{}{
+						"backendRefs": []interface{}{
+							map[string]interface{}{
+								"group":  "",
+								"kind":   "Service",
+								"name":   key.MasterID,
+								"port":   int64(port),
+								"weight": int64(1),
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+
+	route.SetAPIVersion(tlsRouteResource.GroupVersion().String())
+	route.SetKind("TLSRoute")
+	route.SetName(name)
+	route.SetNamespace(key.ClusterNamespace(customObject))
+	route.SetLabels(map[string]string{
+		"cluster":  key.ClusterID(customObject),
+		"customer": key.ClusterCustomer(customObject),
+		"app":      key.MasterID,
+	})
+
+	return route
+}
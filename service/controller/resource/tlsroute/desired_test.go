@@ -0,0 +1,154 @@
+package tlsroute
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"github.com/giantswarm/apiextensions/v3/pkg/apis/provider/v1alpha1"
+	apiextfake "github.com/giantswarm/apiextensions/v3/pkg/clientset/versioned/fake"
+	"github.com/giantswarm/micrologger/microloggertest"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func testKVMConfig() *v1alpha1.KVMConfig {
+	return &v1alpha1.KVMConfig{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "al9qy",
+			Namespace: "default",
+		},
+		Spec: v1alpha1.KVMConfigSpec{
+			Cluster: v1alpha1.Cluster{
+				ID: "al9qy",
+				Customer: v1alpha1.ClusterCustomer{
+					ID: "test-customer",
+				},
+				Etcd: v1alpha1.ClusterEtcd{
+					Domain: "etcd.al9qy.example.com",
+				},
+				Kubernetes: v1alpha1.ClusterKubernetes{
+					API: v1alpha1.ClusterKubernetesAPI{
+						Domain:     "api.al9qy.example.com",
+						SecurePort: 443,
+					},
+				},
+			},
+		},
+	}
+}
+
+func testResource(t *testing.T, sectionName string, customObject *v1alpha1.KVMConfig, routes ...runtime.Object) *Resource {
+	c := Config{
+		DynClient: dynamicfake.NewSimpleDynamicClient(runtime.NewScheme(), routes...),
+		G8sClient: apiextfake.NewSimpleClientset(customObject),
+		K8sClient: fake.NewSimpleClientset(),
+		Logger:    microloggertest.New(),
+
+		GatewayName:        "workload",
+		GatewayNamespace:   "gateway-system",
+		GatewaySectionName: sectionName,
+	}
+
+	r, err := New(c)
+	if err != nil {
+		t.Fatalf("expected nil, got %#v", err)
+	}
+
+	return r
+}
+
+func Test_Resource_TLSRoute_GetDesiredState(t *testing.T) {
+	testCases := []struct {
+		name              string
+		sectionName       string
+		expectedHostnames map[string]string
+		expectedPorts     map[string]int64
+		expectedParentRef map[string]interface{}
+	}{
+		{
+			name: "case 0: routes are attached to the gateway",
+			expectedHostnames: map[string]string{
+				APIID:  "api.al9qy.example.com",
+				EtcdID: "etcd.al9qy.example.com",
+			},
+			expectedPorts: map[string]int64{
+				APIID:  443,
+				EtcdID: 2379,
+			},
+			expectedParentRef: map[string]interface{}{
+				"group":     "gateway.networking.k8s.io",
+				"kind":      "Gateway",
+				"name":      "workload",
+				"namespace": "gateway-system",
+			},
+		},
+		{
+			name:        "case 1: routes are attached to the gateway listener",
+			sectionName: "tls-passthrough",
+			expectedHostnames: map[string]string{
+				APIID:  "api.al9qy.example.com",
+				EtcdID: "etcd.al9qy.example.com",
+			},
+			expectedPorts: map[string]int64{
+				APIID:  443,
+				EtcdID: 2379,
+			},
+			expectedParentRef: map[string]interface{}{
+				"group":       "gateway.networking.k8s.io",
+				"kind":        "Gateway",
+				"name":        "workload",
+				"namespace":   "gateway-system",
+				"sectionName": "tls-passthrough",
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			customObject := testKVMConfig()
+			r := testResource(t, tc.sectionName, customObject)
+
+			result, err := r.GetDesiredState(context.TODO(), customObject)
+			if err != nil {
+				t.Fatalf("expected nil, got %#v", err)
+			}
+			routes, err := toRoutes(result)
+			if err != nil {
+				t.Fatalf("expected nil, got %#v", err)
+			}
+			if len(routes) != 2 {
+				t.Fatalf("expected 2 routes, got %d", len(routes))
+			}
+
+			for _, route := range routes {
+				if route.GetNamespace() != "al9qy" {
+					t.Fatalf("expected namespace %#q, got %#q", "al9qy", route.GetNamespace())
+				}
+
+				hostnames, _, _ := unstructured.NestedStringSlice(route.Object, "spec", "hostnames")
+				if !reflect.DeepEqual(hostnames, []string{tc.expectedHostnames[route.GetName()]}) {
+					t.Fatalf("expected route %#q hostnames %v, got %v", route.GetName(), tc.expectedHostnames[route.GetName()], hostnames)
+				}
+
+				parentRefs, _, _ := unstructured.NestedSlice(route.Object, "spec", "parentRefs")
+				if !reflect.DeepEqual(parentRefs, []interface{}{tc.expectedParentRef}) {
+					t.Fatalf("expected route %#q parent refs %v, got %v", route.GetName(), tc.expectedParentRef, parentRefs)
+				}
+
+				rules, _, _ := unstructured.NestedSlice(route.Object, "spec", "rules")
+				backendRefs, _, _ := unstructured.NestedSlice(rules[0].(map[string]interface{}), "backendRefs")
+				backendRef := backendRefs[0].(map[string]interface{})
+				if backendRef["name"] != "master" {
+					t.Fatalf("expected route %#q backend %#q, got %#q", route.GetName(), "master", backendRef["name"])
+				}
+				if backendRef["port"] != tc.expectedPorts[route.GetName()] {
+					t.Fatalf("expected route %#q backend port %d, got %v", route.GetName(), tc.expectedPorts[route.GetName()], backendRef["port"])
+				}
+			}
+		})
+	}
+}
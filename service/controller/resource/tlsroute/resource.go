@@ -0,0 +1,145 @@
+package tlsroute
+
+import (
+	"reflect"
+
+	"github.com/giantswarm/apiextensions/v3/pkg/clientset/versioned"
+	"github.com/giantswarm/microerror"
+	"github.com/giantswarm/micrologger"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/kubernetes"
+)
+
+const (
+	APIID  = "api"
+	EtcdID = "etcd"
+	// Name is the identifier of the resource.
+	Name = "tlsroute"
+)
+
+// The Gateway API types are not vendored, so TLSRoutes are managed through the
+// dynamic client.
+var tlsRouteResource = schema.GroupVersionResource{
+	Group:    "gateway.networking.k8s.io",
+	Version:  "v1alpha2",
+	Resource: "tlsroutes",
+}
+
+// Config represents the configuration used to create a new tlsroute resource.
+type Config struct {
+	// Dependencies.
+	DynClient dynamic.Interface
+	G8sClient versioned.Interface
+	K8sClient kubernetes.Interface
+	Logger    micrologger.Logger
+
+	// Settings.
+	GatewayName        string
+	GatewayNamespace   string
+	GatewaySectionName string
+}
+
+// Resource implements the tlsroute resource.
+type Resource struct {
+	// Dependencies.
+	dynClient dynamic.Interface
+	g8sClient versioned.Interface
+	k8sClient kubernetes.Interface
+	logger    micrologger.Logger
+
+	// Settings.
+	gatewayName        string
+	gatewayNamespace   string
+	gatewaySectionName string
+}
+
+// New creates a new configured tlsroute resource.
+func New(config Config) (*Resource, error) {
+	// Dependencies.
+	if config.DynClient == nil {
+		return nil, microerror.Maskf(invalidConfigError, "%T.DynClient must not be empty", config)
+	}
+	if config.G8sClient == nil {
+		return nil, microerror.Maskf(invalidConfigError, "%T.G8sClient must not be empty", config)
+	}
+	if config.K8sClient == nil {
+		return nil, microerror.Maskf(invalidConfigError, "%T.K8sClient must not be empty", config)
+	}
+	if config.Logger == nil {
+		return nil, microerror.Maskf(invalidConfigError, "%T.Logger must not be empty", config)
+	}
+
+	// Settings.
+	if config.GatewayName == "" {
+		return nil, microerror.Maskf(invalidConfigError, "%T.GatewayName must not be empty", config)
+	}
+	if config.GatewayNamespace == "" {
+		return nil, microerror.Maskf(invalidConfigError, "%T.GatewayNamespace must not be empty", config)
+	}
+
+	r := &Resource{
+		// Dependencies.
+		dynClient: config.DynClient,
+		g8sClient: config.G8sClient,
+		k8sClient: config.K8sClient,
+		logger:    config.Logger,
+
+		// Settings.
+		gatewayName:        config.GatewayName,
+		gatewayNamespace:   config.GatewayNamespace,
+		gatewaySectionName: config.GatewaySectionName,
+	}
+
+	return r, nil
+}
+
+func (r *Resource) Name() string {
+	return Name
+}
+
+func containsRoute(list []*unstructured.Unstructured, item *unstructured.Unstructured) bool {
+	for _, l := range list {
+		if l.GetName() == item.GetName() {
+			return true
+		}
+	}
+
+	return false
+}
+
+func getRouteByName(list []*unstructured.Unstructured, name string) (*unstructured.Unstructured, error) {
+	for _, l := range list {
+		if l.GetName() == name {
+			return l, nil
+		}
+	}
+
+	return nil, microerror.Mask(notFoundError)
+}
+
+func isRouteModified(a, b *unstructured.Unstructured) bool {
+	if !reflect.DeepEqual(a.GetLabels(), b.GetLabels()) {
+		return true
+	}
+
+	if !reflect.DeepEqual(a.Object["spec"], b.Object["spec"]) {
+		return true
+	}
+
+	return false
+}
+
+func toRoutes(v interface{}) ([]*unstructured.Unstructured, error) {
+	if v == nil {
+		return nil, nil
+	}
+
+	routes, ok := v.([]*unstructured.Unstructured)
+	if !ok {
+		return nil, microerror.Maskf(wrongTypeError, "expected '%T', got '%T'", []*unstructured.Unstructured{}, v)
+	}
+
+	return routes, nil
+}
@@ -0,0 +1,102 @@
+package tlsroute
+
+import (
+	"context"
+
+	"github.com/giantswarm/apiextensions/v3/pkg/apis/provider/v1alpha1"
+	"github.com/giantswarm/microerror"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	"github.com/giantswarm/kvm-operator/v4/service/controller/key"
+)
+
+const (
+	// conditionAPIRouteAccepted is the condition type recording whether the
+	// gateway accepted the TLS route of the workload cluster API.
+	conditionAPIRouteAccepted = "APIRouteAccepted"
+	// conditionEtcdRouteAccepted is the condition type recording whether the
+	// gateway accepted the TLS route of the workload cluster etcd.
+	conditionEtcdRouteAccepted = "EtcdRouteAccepted"
+)
+
+// routeConditions maps the TLS routes to the condition types recording their
+// acceptance.
+var routeConditions = []struct {
+	name          string
+	conditionType string
+}{
+	{name: APIID, conditionType: conditionAPIRouteAccepted},
+	{name: EtcdID, conditionType: conditionEtcdRouteAccepted},
+}
+
+// updateAcceptedStatus records whether the configured gateway accepted the
+// TLS routes of the given cluster in its status. The condition status is
+// Unknown as long as the gateway did not report on a route.
+func (r *Resource) updateAcceptedStatus(ctx context.Context, customObject v1alpha1.KVMConfig) error {
+	var conditions []v1alpha1.StatusClusterResourceCondition
+	for _, rc := range routeConditions {
+		route, err := r.dynClient.Resource(tlsRouteResource).Namespace(key.ClusterNamespace(customObject)).Get(ctx, rc.name, metav1.GetOptions{})
+		if apierrors.IsNotFound(err) {
+			continue
+		} else if err != nil {
+			return microerror.Mask(err)
+		}
+
+		status := r.routeStatus(route)
+
+		r.logger.Debugf(ctx, "TLS route %#q accepted status is %#q", rc.name, status)
+
+		conditions = append(conditions, v1alpha1.StatusClusterResourceCondition{
+			Status: string(status),
+			Type:   rc.conditionType,
+		})
+	}
+
+	updated, err := key.UpdateResourceStatus(ctx, r.g8sClient, customObject, Name, conditions)
+	if err != nil {
+		return microerror.Mask(err)
+	}
+
+	if updated {
+		r.logger.Debugf(ctx, "updated status with TLS route acceptance")
+	}
+
+	return nil
+}
+
+// routeStatus returns whether the configured gateway accepted the given route
+// according to the route's status.
+func (r *Resource) routeStatus(route *unstructured.Unstructured) corev1.ConditionStatus {
+	parents, _, _ := unstructured.NestedSlice(route.Object, "status", "parents")
+
+	for _, p := range parents {
+		parent, ok := p.(map[string]interface{})
+		if !ok {
+			continue
+		}
+
+		name, _, _ := unstructured.NestedString(parent, "parentRef", "name")
+		namespace, _, _ := unstructured.NestedString(parent, "parentRef", "namespace")
+		if name != r.gatewayName || (namespace != "" && namespace != r.gatewayNamespace) {
+			continue
+		}
+
+		conditions, _, _ := unstructured.NestedSlice(parent, "conditions")
+		for _, c := range conditions {
+			condition, ok := c.(map[string]interface{})
+			if !ok || condition["type"] != "Accepted" {
+				continue
+			}
+
+			if condition["status"] == string(metav1.ConditionTrue) {
+				return corev1.ConditionTrue
+			}
+			return corev1.ConditionFalse
+		}
+	}
+
+	return corev1.ConditionUnknown
+}
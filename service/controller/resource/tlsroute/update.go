@@ -0,0 +1,104 @@
+package tlsroute
+
+import (
+	"context"
+
+	"github.com/giantswarm/microerror"
+	"github.com/giantswarm/operatorkit/v5/pkg/resource/crud"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	"github.com/giantswarm/kvm-operator/v4/service/controller/key"
+)
+
+func (r *Resource) ApplyUpdateChange(ctx context.Context, obj, updateChange interface{}) error {
+	customObject, err := key.ToCustomObject(obj)
+	if err != nil {
+		return microerror.Mask(err)
+	}
+	routesToUpdate, err := toRoutes(updateChange)
+	if err != nil {
+		return microerror.Mask(err)
+	}
+
+	if len(routesToUpdate) != 0 {
+		r.logger.Debugf(ctx, "updating the TLS routes in the Kubernetes API")
+
+		for _, route := range routesToUpdate {
+			_, err := r.dynClient.Resource(tlsRouteResource).Namespace(route.GetNamespace()).Update(ctx, route, metav1.UpdateOptions{})
+			if apierrors.IsConflict(err) || apierrors.IsNotFound(err) {
+				// fall through, the route is updated with the next reconciliation
+			} else if err != nil {
+				return microerror.Mask(err)
+			}
+		}
+
+		r.logger.Debugf(ctx, "updated the TLS routes in the Kubernetes API")
+	} else {
+		r.logger.Debugf(ctx, "the TLS routes do not need to be updated in the Kubernetes API")
+	}
+
+	err = r.updateAcceptedStatus(ctx, customObject)
+	if err != nil {
+		return microerror.Mask(err)
+	}
+
+	return nil
+}
+
+func (r *Resource) NewUpdatePatch(ctx context.Context, obj, currentState, desiredState interface{}) (*crud.Patch, error) {
+	create, err := r.newCreateChange(ctx, obj, currentState, desiredState)
+	if err != nil {
+		return nil, microerror.Mask(err)
+	}
+
+	update, err := r.newUpdateChange(ctx, obj, currentState, desiredState)
+	if err != nil {
+		return nil, microerror.Mask(err)
+	}
+
+	patch := crud.NewPatch()
+	patch.SetCreateChange(create)
+	patch.SetUpdateChange(update)
+
+	return patch, nil
+}
+
+func (r *Resource) newUpdateChange(ctx context.Context, obj, currentState, desiredState interface{}) (interface{}, error) {
+	currentRoutes, err := toRoutes(currentState)
+	if err != nil {
+		return nil, microerror.Mask(err)
+	}
+	desiredRoutes, err := toRoutes(desiredState)
+	if err != nil {
+		return nil, microerror.Mask(err)
+	}
+
+	r.logger.Debugf(ctx, "finding out which TLS routes have to be updated")
+
+	var routesToUpdate []*unstructured.Unstructured
+
+	for _, currentRoute := range currentRoutes {
+		desiredRoute, err := getRouteByName(desiredRoutes, currentRoute.GetName())
+		if IsNotFound(err) {
+			continue
+		} else if err != nil {
+			return nil, microerror.Mask(err)
+		}
+
+		if !isRouteModified(desiredRoute, currentRoute) {
+			continue
+		}
+
+		routeToUpdate := desiredRoute.DeepCopy()
+		routeToUpdate.SetResourceVersion(currentRoute.GetResourceVersion())
+		routesToUpdate = append(routesToUpdate, routeToUpdate)
+
+		r.logger.Debugf(ctx, "found TLS route %#q that has to be updated", routeToUpdate.GetName())
+	}
+
+	r.logger.Debugf(ctx, "found %d TLS routes that have to be updated", len(routesToUpdate))
+
+	return routesToUpdate, nil
+}
@@ -0,0 +1,151 @@
+package tlsroute
+
+import (
+	"context"
+	"testing"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	"github.com/giantswarm/kvm-operator/v4/service/controller/key"
+)
+
+// testRoute returns the given desired route as reported by the Kubernetes API
+// with the given status parents.
+func testRoute(route *unstructured.Unstructured, parents ...interface{}) *unstructured.Unstructured {
+	route = route.DeepCopy()
+	route.SetResourceVersion("1")
+	if len(parents) != 0 {
+		route.Object["status"] = map[string]interface{}{
+			"parents": parents,
+		}
+	}
+
+	return route
+}
+
+func testParent(name, namespace, accepted string) interface{} {
+	return map[string]interface{}{
+		"controllerName": "example.com/gateway-controller",
+		"parentRef": map[string]interface{}{
+			"name":      name,
+			"namespace": namespace,
+		},
+		"conditions": []interface{}{
+			map[string]interface{}{
+				"type":   "Accepted",
+				"status": accepted,
+			},
+		},
+	}
+}
+
+func Test_Resource_TLSRoute_updateStatus(t *testing.T) {
+	testCases := []struct {
+		name             string
+		apiParents       []interface{}
+		etcdParents      []interface{}
+		expectedStatuses map[string]string
+	}{
+		{
+			name: "case 0: routes without status are pending",
+			expectedStatuses: map[string]string{
+				conditionAPIRouteAccepted:  "Unknown",
+				conditionEtcdRouteAccepted: "Unknown",
+			},
+		},
+		{
+			name: "case 1: routes accepted and rejected by the gateway",
+			apiParents: []interface{}{
+				testParent("workload", "gateway-system", "True"),
+			},
+			etcdParents: []interface{}{
+				testParent("workload", "gateway-system", "False"),
+			},
+			expectedStatuses: map[string]string{
+				conditionAPIRouteAccepted:  "True",
+				conditionEtcdRouteAccepted: "False",
+			},
+		},
+		{
+			name: "case 2: status of other gateways is ignored",
+			apiParents: []interface{}{
+				testParent("other", "gateway-system", "True"),
+			},
+			etcdParents: []interface{}{
+				testParent("workload", "other-system", "True"),
+				testParent("workload", "gateway-system", "True"),
+			},
+			expectedStatuses: map[string]string{
+				conditionAPIRouteAccepted:  "Unknown",
+				conditionEtcdRouteAccepted: "True",
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			customObject := testKVMConfig()
+
+			var routes []runtime.Object
+			{
+				r := testResource(t, "", customObject)
+				desired, err := r.GetDesiredState(context.TODO(), customObject)
+				if err != nil {
+					t.Fatalf("expected nil, got %#v", err)
+				}
+				desiredRoutes, err := toRoutes(desired)
+				if err != nil {
+					t.Fatalf("expected nil, got %#v", err)
+				}
+
+				routes = append(routes, testRoute(desiredRoutes[0], tc.apiParents...))
+				routes = append(routes, testRoute(desiredRoutes[1], tc.etcdParents...))
+			}
+
+			r := testResource(t, "", customObject, routes...)
+
+			current, err := r.GetCurrentState(context.TODO(), customObject)
+			if err != nil {
+				t.Fatalf("expected nil, got %#v", err)
+			}
+			desired, err := r.GetDesiredState(context.TODO(), customObject)
+			if err != nil {
+				t.Fatalf("expected nil, got %#v", err)
+			}
+			updateChange, err := r.newUpdateChange(context.TODO(), customObject, current, desired)
+			if err != nil {
+				t.Fatalf("expected nil, got %#v", err)
+			}
+			routesToUpdate, err := toRoutes(updateChange)
+			if err != nil {
+				t.Fatalf("expected nil, got %#v", err)
+			}
+			if len(routesToUpdate) != 0 {
+				t.Fatalf("expected 0 routes to update, got %d", len(routesToUpdate))
+			}
+
+			err = r.ApplyUpdateChange(context.TODO(), customObject, updateChange)
+			if err != nil {
+				t.Fatalf("expected nil, got %#v", err)
+			}
+
+			updated, err := r.g8sClient.ProviderV1alpha1().KVMConfigs(customObject.GetNamespace()).Get(context.TODO(), customObject.GetName(), metav1.GetOptions{})
+			if err != nil {
+				t.Fatalf("expected nil, got %#v", err)
+			}
+
+			conditions := key.ResourceStatusConditions(*updated, Name)
+			if len(conditions) != len(tc.expectedStatuses) {
+				t.Fatalf("expected %d conditions, got %d", len(tc.expectedStatuses), len(conditions))
+			}
+			for _, c := range conditions {
+				if c.Status != tc.expectedStatuses[c.Type] {
+					t.Fatalf("expected condition %#q status %#q, got %#q", c.Type, tc.expectedStatuses[c.Type], c.Status)
+				}
+			}
+
+		})
+	}
+}
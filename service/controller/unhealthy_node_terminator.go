@@ -7,11 +7,11 @@ import (
 	"github.com/giantswarm/micrologger"
 	"github.com/giantswarm/operatorkit/v5/pkg/controller"
 	workloadcluster "github.com/giantswarm/tenantcluster/v4/pkg/tenantcluster"
-	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/runtime"
 
 	"github.com/giantswarm/kvm-operator/v4/pkg/label"
 	"github.com/giantswarm/kvm-operator/v4/pkg/project"
+	"github.com/giantswarm/kvm-operator/v4/pkg/shard"
 )
 
 type UnhealthyNodeTerminatorConfig struct {
@@ -20,6 +20,7 @@ type UnhealthyNodeTerminatorConfig struct {
 	WorkloadCluster workloadcluster.Interface
 
 	ProjectName             string
+	Shard                   shard.Shard
 	TerminateUnhealthyNodes bool
 }
 
@@ -46,7 +47,7 @@ func NewUnhealthyNodeTerminator(config UnhealthyNodeTerminatorConfig) (*Unhealth
 			NewRuntimeObjectFunc: func() runtime.Object {
 				return new(v1alpha1.KVMConfig)
 			},
-			Selector: labels.SelectorFromSet(map[string]string{
+			Selector: config.Shard.Selector(map[string]string{
 				label.OperatorVersion: project.Version(),
 			}),
 
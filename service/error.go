@@ -10,3 +10,12 @@ var invalidConfigError = &microerror.Error{
 func IsInvalidConfig(err error) bool {
 	return microerror.Cause(err) == invalidConfigError
 }
+
+var leaderElectionLostError = &microerror.Error{
+	Kind: "leaderElectionLostError",
+}
+
+// IsLeaderElectionLost asserts leaderElectionLostError.
+func IsLeaderElectionLost(err error) bool {
+	return microerror.Cause(err) == leaderElectionLostError
+}
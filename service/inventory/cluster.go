@@ -0,0 +1,181 @@
+package inventory
+
+import (
+	"sort"
+
+	"github.com/giantswarm/apiextensions/v3/pkg/apis/provider/v1alpha1"
+	releasev1alpha1 "github.com/giantswarm/apiextensions/v3/pkg/apis/release/v1alpha1"
+	corev1 "k8s.io/api/core/v1"
+
+	"github.com/giantswarm/kvm-operator/v4/service/controller/key"
+)
+
+const (
+	// RolloutStateCompleted means all desired nodes of a cluster are backed by
+	// ready VM pods running the component versions of the cluster release.
+	RolloutStateCompleted = "completed"
+	// RolloutStateInProgress means VM pods of a cluster are missing, not ready
+	// or still running outdated component versions.
+	RolloutStateInProgress = "in_progress"
+)
+
+// Cluster is the inventory of a single cluster managed by this operator
+// version.
+type Cluster struct {
+	ID             string            `json:"id"`
+	Organization   string            `json:"organization"`
+	ReleaseVersion string            `json:"release_version"`
+	Components     map[string]string `json:"components"`
+	NodeInformer   string            `json:"node_informer"`
+	Rollout        Rollout           `json:"rollout"`
+	Nodes          []Node            `json:"nodes"`
+}
+
+// Node describes a master or worker node of a cluster and the VM pod backing
+// it, if any.
+type Node struct {
+	ID    string `json:"id"`
+	Role  string `json:"role"`
+	Index *int   `json:"index,omitempty"`
+
+	CPUs   int     `json:"cpus"`
+	Memory string  `json:"memory"`
+	Disk   float64 `json:"disk"`
+
+	Pod        string            `json:"pod"`
+	Host       string            `json:"host"`
+	Phase      string            `json:"phase"`
+	Ready      bool              `json:"ready"`
+	Outdated   bool              `json:"outdated"`
+	Components map[string]string `json:"components"`
+}
+
+// Rollout summarizes how far the VM pods of a cluster converged towards the
+// desired nodes and the component versions of the cluster release.
+type Rollout struct {
+	State    string `json:"state"`
+	Desired  int    `json:"desired"`
+	Ready    int    `json:"ready"`
+	Outdated int    `json:"outdated"`
+}
+
+// newCluster computes the inventory of the given cluster from its KVMConfig,
+// its release and the VM pods in its namespace. The release is nil in case
+// it is not known, which leaves the component versions empty.
+func newCluster(cr v1alpha1.KVMConfig, release *releasev1alpha1.Release, pods []*corev1.Pod) Cluster {
+	cluster := Cluster{
+		ID:             key.ClusterID(cr),
+		Organization:   key.ClusterCustomer(cr),
+		ReleaseVersion: key.ReleaseVersion(cr),
+		Components:     map[string]string{},
+		NodeInformer:   key.NodeInformerStatus(cr),
+		Nodes:          []Node{},
+	}
+
+	if release != nil {
+		for _, component := range release.Spec.Components {
+			cluster.Components[component.Name] = component.Version
+		}
+	}
+
+	for i, n := range cr.Spec.Cluster.Masters {
+		var capabilities v1alpha1.KVMConfigSpecKVMNode
+		if i < len(cr.Spec.KVM.Masters) {
+			capabilities = cr.Spec.KVM.Masters[i]
+		}
+		cluster.Nodes = append(cluster.Nodes, newNode(cr, release, pods, key.MasterID, n.ID, capabilities))
+	}
+	for i, n := range cr.Spec.Cluster.Workers {
+		var capabilities v1alpha1.KVMConfigSpecKVMNode
+		if i < len(cr.Spec.KVM.Workers) {
+			capabilities = cr.Spec.KVM.Workers[i]
+		}
+		cluster.Nodes = append(cluster.Nodes, newNode(cr, release, pods, key.WorkerID, n.ID, capabilities))
+	}
+
+	cluster.Rollout.Desired = len(cluster.Nodes)
+	for _, n := range cluster.Nodes {
+		if n.Ready {
+			cluster.Rollout.Ready++
+		}
+		if n.Outdated {
+			cluster.Rollout.Outdated++
+		}
+	}
+
+	if cluster.Rollout.Ready == cluster.Rollout.Desired && cluster.Rollout.Outdated == 0 {
+		cluster.Rollout.State = RolloutStateCompleted
+	} else {
+		cluster.Rollout.State = RolloutStateInProgress
+	}
+
+	return cluster
+}
+
+func newNode(cr v1alpha1.KVMConfig, release *releasev1alpha1.Release, pods []*corev1.Pod, role, nodeID string, capabilities v1alpha1.KVMConfigSpecKVMNode) Node {
+	node := Node{
+		ID:   nodeID,
+		Role: role,
+
+		CPUs:   capabilities.CPUs,
+		Memory: capabilities.Memory,
+		Disk:   capabilities.Disk.Value,
+
+		Components: map[string]string{},
+	}
+
+	if idx, ok := key.NodeIndex(cr, nodeID); ok {
+		node.Index = &idx
+	}
+
+	pod := nodePod(pods, nodeID)
+	if pod == nil {
+		return node
+	}
+
+	node.Pod = pod.GetName()
+	node.Host = pod.Spec.NodeName
+	node.Phase = string(pod.Status.Phase)
+	node.Ready = key.PodIsReady(*pod)
+
+	for _, component := range key.CoreComponents {
+		version, ok := pod.GetAnnotations()[key.AnnotationComponentVersionPrefix+"-"+component]
+		if ok {
+			node.Components[component] = version
+		}
+
+		if release != nil {
+			desired := key.ReleaseComponentVersion(release, component)
+			if desired != "" && desired != version {
+				node.Outdated = true
+			}
+		}
+	}
+
+	return node
+}
+
+// nodePod returns the VM pod of the given node. During rollouts a node may be
+// backed by an old pod being deleted and its replacement, in which case the
+// newest pod not being deleted is preferred.
+func nodePod(pods []*corev1.Pod, nodeID string) *corev1.Pod {
+	var candidates []*corev1.Pod
+	for _, p := range pods {
+		if p.GetLabels()["node"] == nodeID {
+			candidates = append(candidates, p)
+		}
+	}
+	if len(candidates) == 0 {
+		return nil
+	}
+
+	sort.Slice(candidates, func(i, j int) bool {
+		if key.IsDeleted(candidates[i]) != key.IsDeleted(candidates[j]) {
+			return !key.IsDeleted(candidates[i])
+		}
+		ci, cj := candidates[i].GetCreationTimestamp(), candidates[j].GetCreationTimestamp()
+		return cj.Before(&ci)
+	})
+
+	return candidates[0]
+}
@@ -0,0 +1,220 @@
+package inventory
+
+import (
+	"testing"
+	"time"
+
+	"github.com/giantswarm/apiextensions/v3/pkg/apis/provider/v1alpha1"
+	releasev1alpha1 "github.com/giantswarm/apiextensions/v3/pkg/apis/release/v1alpha1"
+	"github.com/google/go-cmp/cmp"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/giantswarm/kvm-operator/v4/pkg/label"
+	"github.com/giantswarm/kvm-operator/v4/service/controller/key"
+)
+
+func Test_newCluster(t *testing.T) {
+	created := time.Date(2021, 6, 1, 12, 0, 0, 0, time.UTC)
+	deleted := metav1.NewTime(created.Add(time.Hour))
+
+	cr := v1alpha1.KVMConfig{
+		ObjectMeta: metav1.ObjectMeta{
+			Labels: map[string]string{
+				label.ReleaseVersion: "14.1.0",
+			},
+		},
+		Spec: v1alpha1.KVMConfigSpec{
+			Cluster: v1alpha1.Cluster{
+				ID: "al9qy",
+				Customer: v1alpha1.ClusterCustomer{
+					ID: "acme",
+				},
+				Masters: []v1alpha1.ClusterNode{
+					{ID: "m1"},
+				},
+				Workers: []v1alpha1.ClusterNode{
+					{ID: "w1"},
+				},
+			},
+			KVM: v1alpha1.KVMConfigSpecKVM{
+				Masters: []v1alpha1.KVMConfigSpecKVMNode{
+					{CPUs: 2, Memory: "4G"},
+				},
+				Workers: []v1alpha1.KVMConfigSpecKVMNode{
+					{CPUs: 4, Memory: "8G"},
+				},
+			},
+		},
+		Status: v1alpha1.KVMConfigStatus{
+			Cluster: v1alpha1.StatusCluster{
+				Resources: []v1alpha1.StatusClusterResource{
+					{
+						Name: key.NodeControllerResourceName,
+						Conditions: []v1alpha1.StatusClusterResourceCondition{
+							{Type: key.NodeInformerCondition + "Healthy", Status: "True"},
+						},
+					},
+				},
+			},
+			KVM: v1alpha1.KVMConfigStatusKVM{
+				NodeIndexes: map[string]int{
+					"m1": 1,
+					"w1": 2,
+				},
+			},
+		},
+	}
+
+	release := &releasev1alpha1.Release{
+		Spec: releasev1alpha1.ReleaseSpec{
+			Components: []releasev1alpha1.ReleaseSpecComponent{
+				{Name: "kubernetes", Version: "1.19.9"},
+			},
+		},
+	}
+
+	newPod := func(name, nodeID, host, kubernetes string, ready bool, age time.Duration, deletion *metav1.Time) *corev1.Pod {
+		status := corev1.ConditionFalse
+		if ready {
+			status = corev1.ConditionTrue
+		}
+		return &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:              name,
+				CreationTimestamp: metav1.NewTime(created.Add(-age)),
+				DeletionTimestamp: deletion,
+				Labels: map[string]string{
+					"node": nodeID,
+				},
+				Annotations: map[string]string{
+					key.AnnotationComponentVersionPrefix + "-kubernetes": kubernetes,
+				},
+			},
+			Spec: corev1.PodSpec{
+				NodeName: host,
+			},
+			Status: corev1.PodStatus{
+				Phase: corev1.PodRunning,
+				Conditions: []corev1.PodCondition{
+					{Type: corev1.PodReady, Status: status},
+				},
+			},
+		}
+	}
+
+	one, two := 1, 2
+
+	testCases := []struct {
+		name     string
+		release  *releasev1alpha1.Release
+		pods     []*corev1.Pod
+		expected Cluster
+	}{
+		{
+			name:    "case 0: all nodes ready and current",
+			release: release,
+			pods: []*corev1.Pod{
+				newPod("master-m1", "m1", "host-1", "1.19.9", true, time.Hour, nil),
+				newPod("worker-w1", "w1", "host-2", "1.19.9", true, time.Hour, nil),
+			},
+			expected: Cluster{
+				ID:             "al9qy",
+				Organization:   "acme",
+				ReleaseVersion: "14.1.0",
+				NodeInformer:   "Healthy",
+				Components:     map[string]string{"kubernetes": "1.19.9"},
+				Rollout: Rollout{
+					State:   RolloutStateCompleted,
+					Desired: 2,
+					Ready:   2,
+				},
+				Nodes: []Node{
+					{ID: "m1", Role: "master", Index: &one, CPUs: 2, Memory: "4G", Pod: "master-m1", Host: "host-1", Phase: "Running", Ready: true, Components: map[string]string{"kubernetes": "1.19.9"}},
+					{ID: "w1", Role: "worker", Index: &two, CPUs: 4, Memory: "8G", Pod: "worker-w1", Host: "host-2", Phase: "Running", Ready: true, Components: map[string]string{"kubernetes": "1.19.9"}},
+				},
+			},
+		},
+		{
+			name:    "case 1: worker replaced during rollout and master missing",
+			release: release,
+			pods: []*corev1.Pod{
+				newPod("worker-w1-old", "w1", "host-2", "1.18.18", true, 2*time.Hour, &deleted),
+				newPod("worker-w1-new", "w1", "host-3", "1.19.9", false, time.Minute, nil),
+			},
+			expected: Cluster{
+				ID:             "al9qy",
+				Organization:   "acme",
+				ReleaseVersion: "14.1.0",
+				NodeInformer:   "Healthy",
+				Components:     map[string]string{"kubernetes": "1.19.9"},
+				Rollout: Rollout{
+					State:   RolloutStateInProgress,
+					Desired: 2,
+				},
+				Nodes: []Node{
+					{ID: "m1", Role: "master", Index: &one, CPUs: 2, Memory: "4G", Components: map[string]string{}},
+					{ID: "w1", Role: "worker", Index: &two, CPUs: 4, Memory: "8G", Pod: "worker-w1-new", Host: "host-3", Phase: "Running", Components: map[string]string{"kubernetes": "1.19.9"}},
+				},
+			},
+		},
+		{
+			name:    "case 2: outdated nodes",
+			release: release,
+			pods: []*corev1.Pod{
+				newPod("master-m1", "m1", "host-1", "1.18.18", true, time.Hour, nil),
+				newPod("worker-w1", "w1", "host-2", "1.19.9", true, time.Hour, nil),
+			},
+			expected: Cluster{
+				ID:             "al9qy",
+				Organization:   "acme",
+				ReleaseVersion: "14.1.0",
+				NodeInformer:   "Healthy",
+				Components:     map[string]string{"kubernetes": "1.19.9"},
+				Rollout: Rollout{
+					State:    RolloutStateInProgress,
+					Desired:  2,
+					Ready:    2,
+					Outdated: 1,
+				},
+				Nodes: []Node{
+					{ID: "m1", Role: "master", Index: &one, CPUs: 2, Memory: "4G", Pod: "master-m1", Host: "host-1", Phase: "Running", Ready: true, Outdated: true, Components: map[string]string{"kubernetes": "1.18.18"}},
+					{ID: "w1", Role: "worker", Index: &two, CPUs: 4, Memory: "8G", Pod: "worker-w1", Host: "host-2", Phase: "Running", Ready: true, Components: map[string]string{"kubernetes": "1.19.9"}},
+				},
+			},
+		},
+		{
+			name: "case 3: unknown release",
+			pods: []*corev1.Pod{
+				newPod("master-m1", "m1", "host-1", "1.18.18", true, time.Hour, nil),
+				newPod("worker-w1", "w1", "host-2", "1.19.9", true, time.Hour, nil),
+			},
+			expected: Cluster{
+				ID:             "al9qy",
+				Organization:   "acme",
+				ReleaseVersion: "14.1.0",
+				NodeInformer:   "Healthy",
+				Components:     map[string]string{},
+				Rollout: Rollout{
+					State:   RolloutStateCompleted,
+					Desired: 2,
+					Ready:   2,
+				},
+				Nodes: []Node{
+					{ID: "m1", Role: "master", Index: &one, CPUs: 2, Memory: "4G", Pod: "master-m1", Host: "host-1", Phase: "Running", Ready: true, Components: map[string]string{"kubernetes": "1.18.18"}},
+					{ID: "w1", Role: "worker", Index: &two, CPUs: 4, Memory: "8G", Pod: "worker-w1", Host: "host-2", Phase: "Running", Ready: true, Components: map[string]string{"kubernetes": "1.19.9"}},
+				},
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			result := newCluster(cr, tc.release, tc.pods)
+
+			if !cmp.Equal(result, tc.expected) {
+				t.Fatalf("\n\n%s\n", cmp.Diff(tc.expected, result))
+			}
+		})
+	}
+}
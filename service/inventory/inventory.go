@@ -0,0 +1,363 @@
+// Package inventory provides a read-only view of the clusters managed by this
+// operator version, their nodes and the VM pods backing them, as well as the
+// management cluster nodes VM pods are scheduled to. It is computed from
+// informer caches, so serving it and collecting metrics from it does not put
+// load on the Kubernetes API.
+package inventory
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"github.com/giantswarm/apiextensions/v3/pkg/apis/provider/v1alpha1"
+	releasev1alpha1 "github.com/giantswarm/apiextensions/v3/pkg/apis/release/v1alpha1"
+	"github.com/giantswarm/apiextensions/v3/pkg/clientset/versioned"
+	"github.com/giantswarm/microerror"
+	"github.com/giantswarm/micrologger"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/selection"
+	"k8s.io/apimachinery/pkg/watch"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/tools/cache"
+
+	"github.com/giantswarm/kvm-operator/v4/pkg/label"
+	"github.com/giantswarm/kvm-operator/v4/pkg/project"
+	"github.com/giantswarm/kvm-operator/v4/pkg/shard"
+	"github.com/giantswarm/kvm-operator/v4/service/controller/key"
+)
+
+const (
+	resyncPeriod = 5 * time.Minute
+
+	// labelRole is the label of the management cluster nodes the master and
+	// worker deployments select to schedule VM pods.
+	labelRole = "role"
+
+	podNamespaceIndex = "namespace"
+	podNodeNameIndex  = "nodeName"
+)
+
+type Config struct {
+	G8sClient versioned.Interface
+	K8sClient kubernetes.Interface
+	Logger    micrologger.Logger
+
+	Shard shard.Shard
+}
+
+// Service serves the inventory of all clusters reconciled by this operator
+// instance.
+type Service struct {
+	logger micrologger.Logger
+	shard  shard.Shard
+
+	clusters cache.SharedIndexInformer
+	hostPods cache.SharedIndexInformer
+	hosts    cache.SharedIndexInformer
+	pods     cache.SharedIndexInformer
+	releases cache.SharedIndexInformer
+}
+
+func New(config Config) (*Service, error) {
+	if config.G8sClient == nil {
+		return nil, microerror.Maskf(invalidConfigError, "%T.G8sClient must not be empty", config)
+	}
+	if config.K8sClient == nil {
+		return nil, microerror.Maskf(invalidConfigError, "%T.K8sClient must not be empty", config)
+	}
+	if config.Logger == nil {
+		return nil, microerror.Maskf(invalidConfigError, "%T.Logger must not be empty", config)
+	}
+
+	var clusters cache.SharedIndexInformer
+	{
+		selector := label.OperatorVersion + "=" + project.Version()
+		lw := &cache.ListWatch{
+			ListFunc: func(options metav1.ListOptions) (runtime.Object, error) {
+				options.LabelSelector = selector
+				return config.G8sClient.ProviderV1alpha1().KVMConfigs(metav1.NamespaceAll).List(context.Background(), options)
+			},
+			WatchFunc: func(options metav1.ListOptions) (watch.Interface, error) {
+				options.LabelSelector = selector
+				return config.G8sClient.ProviderV1alpha1().KVMConfigs(metav1.NamespaceAll).Watch(context.Background(), options)
+			},
+		}
+		clusters = cache.NewSharedIndexInformer(lw, &v1alpha1.KVMConfig{}, resyncPeriod, cache.Indexers{})
+	}
+
+	var hosts cache.SharedIndexInformer
+	{
+		r, err := labels.NewRequirement(labelRole, selection.In, []string{key.MasterID, key.WorkerID})
+		if err != nil {
+			return nil, microerror.Mask(err)
+		}
+		selector := labels.NewSelector().Add(*r).String()
+		lw := &cache.ListWatch{
+			ListFunc: func(options metav1.ListOptions) (runtime.Object, error) {
+				options.LabelSelector = selector
+				return config.K8sClient.CoreV1().Nodes().List(context.Background(), options)
+			},
+			WatchFunc: func(options metav1.ListOptions) (watch.Interface, error) {
+				options.LabelSelector = selector
+				return config.K8sClient.CoreV1().Nodes().Watch(context.Background(), options)
+			},
+		}
+		hosts = cache.NewSharedIndexInformer(lw, &corev1.Node{}, resyncPeriod, cache.Indexers{})
+	}
+
+	// All scheduled and non-terminated pods are cached, not only VM pods,
+	// since all of them count towards the requested resources of the hosts.
+	var hostPods cache.SharedIndexInformer
+	{
+		selector := "spec.nodeName!=,status.phase!=" + string(corev1.PodSucceeded) + ",status.phase!=" + string(corev1.PodFailed)
+		lw := &cache.ListWatch{
+			ListFunc: func(options metav1.ListOptions) (runtime.Object, error) {
+				options.FieldSelector = selector
+				return config.K8sClient.CoreV1().Pods(metav1.NamespaceAll).List(context.Background(), options)
+			},
+			WatchFunc: func(options metav1.ListOptions) (watch.Interface, error) {
+				options.FieldSelector = selector
+				return config.K8sClient.CoreV1().Pods(metav1.NamespaceAll).Watch(context.Background(), options)
+			},
+		}
+		hostPods = cache.NewSharedIndexInformer(lw, &corev1.Pod{}, resyncPeriod, cache.Indexers{
+			podNodeNameIndex: podNodeNameIndexFunc,
+		})
+	}
+
+	var pods cache.SharedIndexInformer
+	{
+		selector := key.PodWatcherLabel + "=" + key.OperatorName
+		lw := &cache.ListWatch{
+			ListFunc: func(options metav1.ListOptions) (runtime.Object, error) {
+				options.LabelSelector = selector
+				return config.K8sClient.CoreV1().Pods(metav1.NamespaceAll).List(context.Background(), options)
+			},
+			WatchFunc: func(options metav1.ListOptions) (watch.Interface, error) {
+				options.LabelSelector = selector
+				return config.K8sClient.CoreV1().Pods(metav1.NamespaceAll).Watch(context.Background(), options)
+			},
+		}
+		pods = cache.NewSharedIndexInformer(lw, &corev1.Pod{}, resyncPeriod, cache.Indexers{
+			podNamespaceIndex: cache.MetaNamespaceIndexFunc,
+		})
+	}
+
+	var releases cache.SharedIndexInformer
+	{
+		lw := &cache.ListWatch{
+			ListFunc: func(options metav1.ListOptions) (runtime.Object, error) {
+				return config.G8sClient.ReleaseV1alpha1().Releases().List(context.Background(), options)
+			},
+			WatchFunc: func(options metav1.ListOptions) (watch.Interface, error) {
+				return config.G8sClient.ReleaseV1alpha1().Releases().Watch(context.Background(), options)
+			},
+		}
+		releases = cache.NewSharedIndexInformer(lw, &releasev1alpha1.Release{}, resyncPeriod, cache.Indexers{})
+	}
+
+	s := &Service{
+		logger: config.Logger,
+		shard:  config.Shard,
+
+		clusters: clusters,
+		hostPods: hostPods,
+		hosts:    hosts,
+		pods:     pods,
+		releases: releases,
+	}
+
+	return s, nil
+}
+
+// Boot runs the informers backing the inventory until the given context is
+// done.
+func (s *Service) Boot(ctx context.Context) {
+	go s.clusters.Run(ctx.Done())
+	go s.hostPods.Run(ctx.Done())
+	go s.hosts.Run(ctx.Done())
+	go s.pods.Run(ctx.Done())
+	go s.releases.Run(ctx.Done())
+
+	if cache.WaitForCacheSync(ctx.Done(), s.HasSynced) {
+		s.logger.Debugf(ctx, "inventory caches synced")
+	}
+}
+
+// HasSynced returns true once the informers backing the inventory have
+// synced.
+func (s *Service) HasSynced() bool {
+	return s.clusters.HasSynced() && s.hostPods.HasSynced() && s.hosts.HasSynced() && s.pods.HasSynced() && s.releases.HasSynced()
+}
+
+// Clusters returns the inventory of all clusters sorted by cluster ID.
+func (s *Service) Clusters() ([]Cluster, error) {
+	crs, err := s.KVMConfigs()
+	if err != nil {
+		return nil, microerror.Mask(err)
+	}
+
+	clusters := []Cluster{}
+	for _, cr := range crs {
+		cluster, err := s.newCluster(*cr)
+		if err != nil {
+			return nil, microerror.Mask(err)
+		}
+		clusters = append(clusters, cluster)
+	}
+
+	sort.Slice(clusters, func(i, j int) bool {
+		return clusters[i].ID < clusters[j].ID
+	})
+
+	return clusters, nil
+}
+
+// Cluster returns the inventory of the cluster with the given ID.
+func (s *Service) Cluster(id string) (Cluster, error) {
+	crs, err := s.KVMConfigs()
+	if err != nil {
+		return Cluster{}, microerror.Mask(err)
+	}
+
+	for _, cr := range crs {
+		if key.ClusterID(*cr) != id {
+			continue
+		}
+
+		cluster, err := s.newCluster(*cr)
+		if err != nil {
+			return Cluster{}, microerror.Mask(err)
+		}
+
+		return cluster, nil
+	}
+
+	return Cluster{}, microerror.Maskf(notFoundError, "cluster %#q", id)
+}
+
+// KVMConfigs returns the cached KVMConfigs of all clusters reconciled by this
+// operator instance.
+func (s *Service) KVMConfigs() ([]*v1alpha1.KVMConfig, error) {
+	if !s.HasSynced() {
+		return nil, microerror.Maskf(notYetAvailableError, "inventory caches not synced yet")
+	}
+
+	var crs []*v1alpha1.KVMConfig
+	for _, obj := range s.clusters.GetStore().List() {
+		cr, ok := obj.(*v1alpha1.KVMConfig)
+		if !ok || !s.shard.Matches(cr.GetLabels()) {
+			continue
+		}
+		crs = append(crs, cr)
+	}
+
+	return crs, nil
+}
+
+// Release returns the cached release with the given name or nil in case it
+// does not exist.
+func (s *Service) Release(name string) (*releasev1alpha1.Release, error) {
+	if !s.HasSynced() {
+		return nil, microerror.Maskf(notYetAvailableError, "inventory caches not synced yet")
+	}
+
+	obj, exists, err := s.releases.GetStore().GetByKey(name)
+	if err != nil {
+		return nil, microerror.Mask(err)
+	}
+	if !exists {
+		return nil, nil
+	}
+
+	release, _ := obj.(*releasev1alpha1.Release)
+
+	return release, nil
+}
+
+// ClusterPods returns the cached VM pods of the given cluster.
+func (s *Service) ClusterPods(cr v1alpha1.KVMConfig) ([]*corev1.Pod, error) {
+	if !s.HasSynced() {
+		return nil, microerror.Maskf(notYetAvailableError, "inventory caches not synced yet")
+	}
+
+	objs, err := s.pods.GetIndexer().ByIndex(podNamespaceIndex, key.ClusterNamespace(cr))
+	if err != nil {
+		return nil, microerror.Mask(err)
+	}
+
+	var pods []*corev1.Pod
+	for _, obj := range objs {
+		if p, ok := obj.(*corev1.Pod); ok {
+			pods = append(pods, p)
+		}
+	}
+
+	return pods, nil
+}
+
+// HostPods returns the cached non-terminated pods scheduled to the management
+// cluster node with the given name. These are the VM pods of all clusters,
+// including the ones of other shards, and any other pods.
+func (s *Service) HostPods(name string) ([]*corev1.Pod, error) {
+	if !s.HasSynced() {
+		return nil, microerror.Maskf(notYetAvailableError, "inventory caches not synced yet")
+	}
+
+	objs, err := s.hostPods.GetIndexer().ByIndex(podNodeNameIndex, name)
+	if err != nil {
+		return nil, microerror.Mask(err)
+	}
+
+	var pods []*corev1.Pod
+	for _, obj := range objs {
+		if p, ok := obj.(*corev1.Pod); ok {
+			pods = append(pods, p)
+		}
+	}
+
+	return pods, nil
+}
+
+// Hosts returns the cached management cluster nodes VM pods are scheduled to.
+func (s *Service) Hosts() ([]*corev1.Node, error) {
+	if !s.HasSynced() {
+		return nil, microerror.Maskf(notYetAvailableError, "inventory caches not synced yet")
+	}
+
+	var nodes []*corev1.Node
+	for _, obj := range s.hosts.GetStore().List() {
+		if n, ok := obj.(*corev1.Node); ok {
+			nodes = append(nodes, n)
+		}
+	}
+
+	return nodes, nil
+}
+
+func (s *Service) newCluster(cr v1alpha1.KVMConfig) (Cluster, error) {
+	release, err := s.Release(key.ReleaseName(cr))
+	if err != nil {
+		return Cluster{}, microerror.Mask(err)
+	}
+
+	pods, err := s.ClusterPods(cr)
+	if err != nil {
+		return Cluster{}, microerror.Mask(err)
+	}
+
+	return newCluster(cr, release, pods), nil
+}
+
+func podNodeNameIndexFunc(obj interface{}) ([]string, error) {
+	p, ok := obj.(*corev1.Pod)
+	if !ok || p.Spec.NodeName == "" {
+		return nil, nil
+	}
+
+	return []string{p.Spec.NodeName}, nil
+}
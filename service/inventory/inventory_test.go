@@ -0,0 +1,125 @@
+package inventory
+
+import (
+	"context"
+	"testing"
+
+	"github.com/giantswarm/apiextensions/v3/pkg/apis/provider/v1alpha1"
+	apiextfake "github.com/giantswarm/apiextensions/v3/pkg/clientset/versioned/fake"
+	"github.com/giantswarm/micrologger/microloggertest"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+
+	"github.com/giantswarm/kvm-operator/v4/pkg/label"
+	"github.com/giantswarm/kvm-operator/v4/pkg/project"
+	"github.com/giantswarm/kvm-operator/v4/service/controller/key"
+)
+
+func Test_Service_caches(t *testing.T) {
+	newHost := func(name, role string) *corev1.Node {
+		return &corev1.Node{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: name,
+				Labels: map[string]string{
+					labelRole: role,
+				},
+			},
+		}
+	}
+	newPod := func(name, namespace, host string, l map[string]string) *corev1.Pod {
+		return &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      name,
+				Namespace: namespace,
+				Labels:    l,
+			},
+			Spec: corev1.PodSpec{
+				NodeName: host,
+			},
+		}
+	}
+	vmPodLabels := map[string]string{
+		key.PodWatcherLabel: key.OperatorName,
+	}
+
+	cr := &v1alpha1.KVMConfig{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "al9qy",
+			Namespace: metav1.NamespaceDefault,
+			Labels: map[string]string{
+				label.OperatorVersion: project.Version(),
+			},
+		},
+		Spec: v1alpha1.KVMConfigSpec{
+			Cluster: v1alpha1.Cluster{
+				ID: "al9qy",
+			},
+		},
+	}
+
+	s, err := New(Config{
+		G8sClient: apiextfake.NewSimpleClientset(cr),
+		K8sClient: fake.NewSimpleClientset(
+			newHost("host-a", key.WorkerID),
+			newHost("host-b", key.MasterID),
+			newHost("host-c", "storage"),
+			newPod("master-m1", "al9qy", "host-b", vmPodLabels),
+			newPod("worker-w1", "b2bbb", "host-a", vmPodLabels),
+			newPod("node-exporter", "monitoring", "host-a", nil),
+		),
+		Logger: microloggertest.New(),
+	})
+	if err != nil {
+		t.Fatalf("expected nil, got %#v", err)
+	}
+
+	_, err = s.KVMConfigs()
+	if !IsNotYetAvailable(err) {
+		t.Fatalf("expected not yet available error, got %#v", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	s.Boot(ctx)
+
+	crs, err := s.KVMConfigs()
+	if err != nil {
+		t.Fatalf("expected nil, got %#v", err)
+	}
+	if len(crs) != 1 {
+		t.Fatalf("expected 1 KVMConfig, got %d", len(crs))
+	}
+
+	hosts, err := s.Hosts()
+	if err != nil {
+		t.Fatalf("expected nil, got %#v", err)
+	}
+	if len(hosts) != 2 {
+		t.Fatalf("expected 2 hosts, got %d", len(hosts))
+	}
+
+	pods, err := s.ClusterPods(*cr)
+	if err != nil {
+		t.Fatalf("expected nil, got %#v", err)
+	}
+	if len(pods) != 1 || pods[0].GetName() != "master-m1" {
+		t.Fatalf("expected VM pod %#q, got %v", "master-m1", pods)
+	}
+
+	pods, err = s.HostPods("host-a")
+	if err != nil {
+		t.Fatalf("expected nil, got %#v", err)
+	}
+	if len(pods) != 2 {
+		t.Fatalf("expected 2 pods on host %#q, got %d", "host-a", len(pods))
+	}
+
+	release, err := s.Release(key.ReleaseName(*cr))
+	if err != nil {
+		t.Fatalf("expected nil, got %#v", err)
+	}
+	if release != nil {
+		t.Fatalf("expected nil release, got %#v", release)
+	}
+}
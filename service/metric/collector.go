@@ -0,0 +1,170 @@
+package metric
+
+import (
+	"context"
+
+	"github.com/giantswarm/apiextensions/v3/pkg/apis/provider/v1alpha1"
+	releasev1alpha1 "github.com/giantswarm/apiextensions/v3/pkg/apis/release/v1alpha1"
+	"github.com/giantswarm/microerror"
+	"github.com/giantswarm/micrologger"
+	"github.com/prometheus/client_golang/prometheus"
+	corev1 "k8s.io/api/core/v1"
+
+	"github.com/giantswarm/kvm-operator/v4/service/controller/key"
+	"github.com/giantswarm/kvm-operator/v4/service/inventory"
+)
+
+var (
+	clusterNodesDesc = prometheus.NewDesc(
+		prometheus.BuildFQName(prometheusNamespace, "cluster", "nodes"),
+		"Number of desired and ready master and worker nodes of the cluster.",
+		[]string{labelClusterID, labelOrganization, "role", "state"},
+		nil,
+	)
+	clusterPodsPendingDesc = prometheus.NewDesc(
+		prometheus.BuildFQName(prometheusNamespace, "cluster", "pods_pending"),
+		"Number of pending VM pods of the cluster.",
+		[]string{labelClusterID, labelOrganization},
+		nil,
+	)
+	clusterUpgradeNodesDesc = prometheus.NewDesc(
+		prometheus.BuildFQName(prometheusNamespace, "cluster", "upgrade_nodes"),
+		"Number of VM pods of the cluster running the component versions of the current release or outdated ones.",
+		[]string{labelClusterID, labelOrganization, "state"},
+		nil,
+	)
+)
+
+// Inventory provides the cached objects the metrics are computed from, so
+// that scrapes do not put load on the Kubernetes API. It is implemented by
+// inventory.Service.
+type Inventory interface {
+	ClusterPods(cr v1alpha1.KVMConfig) ([]*corev1.Pod, error)
+	HostPods(name string) ([]*corev1.Pod, error)
+	Hosts() ([]*corev1.Node, error)
+	KVMConfigs() ([]*v1alpha1.KVMConfig, error)
+	Release(name string) (*releasev1alpha1.Release, error)
+}
+
+type CollectorConfig struct {
+	Inventory Inventory
+	Logger    micrologger.Logger
+}
+
+// Collector implements prometheus.Collector and exposes the VM and node
+// lifecycle state of all clusters reconciled by this operator instance at
+// scrape time.
+type Collector struct {
+	inventory Inventory
+	logger    micrologger.Logger
+}
+
+func NewCollector(config CollectorConfig) (*Collector, error) {
+	if config.Inventory == nil {
+		return nil, microerror.Maskf(invalidConfigError, "%T.Inventory must not be empty", config)
+	}
+	if config.Logger == nil {
+		return nil, microerror.Maskf(invalidConfigError, "%T.Logger must not be empty", config)
+	}
+
+	c := &Collector{
+		inventory: config.Inventory,
+		logger:    config.Logger,
+	}
+
+	return c, nil
+}
+
+func (c *Collector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- clusterNodesDesc
+	ch <- clusterPodsPendingDesc
+	ch <- clusterUpgradeNodesDesc
+}
+
+func (c *Collector) Collect(ch chan<- prometheus.Metric) {
+	ctx := context.Background()
+
+	err := c.collect(ch)
+	if inventory.IsNotYetAvailable(err) {
+		c.logger.Debugf(ctx, "not collecting cluster metrics: %s", err)
+	} else if err != nil {
+		c.logger.Errorf(ctx, err, "failed to collect cluster metrics")
+	}
+}
+
+func (c *Collector) collect(ch chan<- prometheus.Metric) error {
+	clusters, err := c.inventory.KVMConfigs()
+	if err != nil {
+		return microerror.Mask(err)
+	}
+
+	for _, cr := range clusters {
+		clusterID := key.ClusterID(*cr)
+		organization := key.ClusterCustomer(*cr)
+
+		clusterPods, err := c.inventory.ClusterPods(*cr)
+		if err != nil {
+			return microerror.Mask(err)
+		}
+
+		desired := map[string]int{
+			key.MasterID: len(cr.Spec.Cluster.Masters),
+			key.WorkerID: len(cr.Spec.Cluster.Workers),
+		}
+		ready := map[string]int{}
+		pending := 0
+		for _, p := range clusterPods {
+			if key.PodIsReady(*p) {
+				ready[p.GetLabels()[key.LabelApp]]++
+			}
+			if p.Status.Phase == corev1.PodPending {
+				pending++
+			}
+		}
+
+		for _, role := range []string{key.MasterID, key.WorkerID} {
+			ch <- prometheus.MustNewConstMetric(clusterNodesDesc, prometheus.GaugeValue, float64(desired[role]), clusterID, organization, role, "desired")
+			ch <- prometheus.MustNewConstMetric(clusterNodesDesc, prometheus.GaugeValue, float64(ready[role]), clusterID, organization, role, "ready")
+		}
+		ch <- prometheus.MustNewConstMetric(clusterPodsPendingDesc, prometheus.GaugeValue, float64(pending), clusterID, organization)
+
+		release, err := c.inventory.Release(key.ReleaseName(*cr))
+		if err != nil {
+			return microerror.Mask(err)
+		}
+		if release == nil {
+			continue
+		}
+
+		current, outdated := 0, 0
+		for _, p := range clusterPods {
+			if isPodOutdated(*p, release) {
+				outdated++
+			} else {
+				current++
+			}
+		}
+		ch <- prometheus.MustNewConstMetric(clusterUpgradeNodesDesc, prometheus.GaugeValue, float64(current), clusterID, organization, "current")
+		ch <- prometheus.MustNewConstMetric(clusterUpgradeNodesDesc, prometheus.GaugeValue, float64(outdated), clusterID, organization, "outdated")
+	}
+
+	return nil
+}
+
+// isPodOutdated returns true in case any core component version annotated on
+// the given pod differs from the version of the component in the given
+// release.
+func isPodOutdated(p corev1.Pod, release *releasev1alpha1.Release) bool {
+	for _, component := range key.CoreComponents {
+		version := key.ReleaseComponentVersion(release, component)
+		if version == "" {
+			continue
+		}
+
+		if p.GetAnnotations()[key.AnnotationComponentVersionPrefix+"-"+component] != version {
+			return true
+		}
+	}
+
+	return false
+}
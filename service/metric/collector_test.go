@@ -0,0 +1,161 @@
+package metric
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/giantswarm/apiextensions/v3/pkg/apis/provider/v1alpha1"
+	releasev1alpha1 "github.com/giantswarm/apiextensions/v3/pkg/apis/release/v1alpha1"
+	"github.com/giantswarm/micrologger/microloggertest"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/giantswarm/kvm-operator/v4/pkg/label"
+	"github.com/giantswarm/kvm-operator/v4/pkg/project"
+	"github.com/giantswarm/kvm-operator/v4/service/controller/key"
+)
+
+type testInventory struct {
+	clusters []*v1alpha1.KVMConfig
+	hosts    []*corev1.Node
+	pods     []*corev1.Pod
+	releases []*releasev1alpha1.Release
+}
+
+func (i testInventory) ClusterPods(cr v1alpha1.KVMConfig) ([]*corev1.Pod, error) {
+	var pods []*corev1.Pod
+	for _, p := range i.pods {
+		if p.GetNamespace() == key.ClusterNamespace(cr) {
+			pods = append(pods, p)
+		}
+	}
+
+	return pods, nil
+}
+
+func (i testInventory) HostPods(name string) ([]*corev1.Pod, error) {
+	var pods []*corev1.Pod
+	for _, p := range i.pods {
+		if p.Spec.NodeName == name {
+			pods = append(pods, p)
+		}
+	}
+
+	return pods, nil
+}
+
+func (i testInventory) Hosts() ([]*corev1.Node, error) {
+	return i.hosts, nil
+}
+
+func (i testInventory) KVMConfigs() ([]*v1alpha1.KVMConfig, error) {
+	return i.clusters, nil
+}
+
+func (i testInventory) Release(name string) (*releasev1alpha1.Release, error) {
+	for _, r := range i.releases {
+		if r.GetName() == name {
+			return r, nil
+		}
+	}
+
+	return nil, nil
+}
+
+func testPod(name string, role string, phase corev1.PodPhase, ready bool, kubernetesVersion string) *corev1.Pod {
+	p := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: "al9qy",
+			Annotations: map[string]string{
+				key.AnnotationComponentVersionPrefix + "-kubernetes": kubernetesVersion,
+			},
+			Labels: map[string]string{
+				key.LabelApp:        role,
+				key.PodWatcherLabel: key.OperatorName,
+			},
+		},
+		Status: corev1.PodStatus{
+			Phase: phase,
+		},
+	}
+	if ready {
+		p.Status.Conditions = []corev1.PodCondition{
+			{Type: corev1.PodReady, Status: corev1.ConditionTrue},
+		}
+	}
+
+	return p
+}
+
+func Test_Collector(t *testing.T) {
+	cr := &v1alpha1.KVMConfig{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "al9qy",
+			Namespace: metav1.NamespaceDefault,
+			Labels: map[string]string{
+				label.OperatorVersion: project.Version(),
+				label.ReleaseVersion:  "14.1.0",
+			},
+		},
+		Spec: v1alpha1.KVMConfigSpec{
+			Cluster: v1alpha1.Cluster{
+				ID: "al9qy",
+				Customer: v1alpha1.ClusterCustomer{
+					ID: "acme",
+				},
+				Masters: []v1alpha1.ClusterNode{{ID: "m1"}},
+				Workers: []v1alpha1.ClusterNode{{ID: "w1"}, {ID: "w2"}, {ID: "w3"}},
+			},
+		},
+	}
+	release := &releasev1alpha1.Release{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "v14.1.0",
+		},
+		Spec: releasev1alpha1.ReleaseSpec{
+			Components: []releasev1alpha1.ReleaseSpecComponent{
+				{Name: "kubernetes", Version: "1.20.0"},
+			},
+		},
+	}
+
+	c, err := NewCollector(CollectorConfig{
+		Inventory: testInventory{
+			clusters: []*v1alpha1.KVMConfig{cr},
+			pods: []*corev1.Pod{
+				testPod("master-m1", key.MasterID, corev1.PodRunning, true, "1.20.0"),
+				testPod("worker-w1", key.WorkerID, corev1.PodRunning, true, "1.19.0"),
+				testPod("worker-w2", key.WorkerID, corev1.PodRunning, false, "1.20.0"),
+				testPod("worker-w3", key.WorkerID, corev1.PodPending, false, "1.20.0"),
+			},
+			releases: []*releasev1alpha1.Release{release},
+		},
+		Logger: microloggertest.New(),
+	})
+	if err != nil {
+		t.Fatalf("expected nil, got %#v", err)
+	}
+
+	expected := `
+# HELP kvm_operator_cluster_nodes Number of desired and ready master and worker nodes of the cluster.
+# TYPE kvm_operator_cluster_nodes gauge
+kvm_operator_cluster_nodes{cluster_id="al9qy",organization="acme",role="master",state="desired"} 1
+kvm_operator_cluster_nodes{cluster_id="al9qy",organization="acme",role="master",state="ready"} 1
+kvm_operator_cluster_nodes{cluster_id="al9qy",organization="acme",role="worker",state="desired"} 3
+kvm_operator_cluster_nodes{cluster_id="al9qy",organization="acme",role="worker",state="ready"} 1
+# HELP kvm_operator_cluster_pods_pending Number of pending VM pods of the cluster.
+# TYPE kvm_operator_cluster_pods_pending gauge
+kvm_operator_cluster_pods_pending{cluster_id="al9qy",organization="acme"} 1
+# HELP kvm_operator_cluster_upgrade_nodes Number of VM pods of the cluster running the component versions of the current release or outdated ones.
+# TYPE kvm_operator_cluster_upgrade_nodes gauge
+kvm_operator_cluster_upgrade_nodes{cluster_id="al9qy",organization="acme",state="current"} 3
+kvm_operator_cluster_upgrade_nodes{cluster_id="al9qy",organization="acme",state="outdated"} 1
+`
+
+	err = testutil.CollectAndCompare(c, strings.NewReader(expected))
+	if err != nil {
+		t.Fatalf("expected nil, got %s", err)
+	}
+}
@@ -0,0 +1,278 @@
+package metric
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/giantswarm/apiextensions/v3/pkg/apis/provider/v1alpha1"
+	"github.com/giantswarm/microerror"
+	"github.com/giantswarm/micrologger"
+	"github.com/prometheus/client_golang/prometheus"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+
+	"github.com/giantswarm/kvm-operator/v4/service/controller/key"
+	"github.com/giantswarm/kvm-operator/v4/service/inventory"
+)
+
+const (
+	labelNode     = "node"
+	labelResource = "resource"
+	labelRole     = "role"
+
+	resourceCPU    = "cpu"
+	resourceMemory = "memory"
+
+	gigabyte = 1000 * 1000 * 1000
+)
+
+var (
+	hostAllocatableDesc = prometheus.NewDesc(
+		prometheus.BuildFQName(prometheusNamespace, "host", "allocatable"),
+		"Allocatable CPU cores and memory bytes of the management cluster node.",
+		[]string{labelNode, labelRole, labelResource},
+		nil,
+	)
+	hostRequestedDesc = prometheus.NewDesc(
+		prometheus.BuildFQName(prometheusNamespace, "host", "requested"),
+		"CPU cores and memory bytes requested by all non-terminated pods scheduled to the management cluster node.",
+		[]string{labelNode, labelRole, labelResource},
+		nil,
+	)
+	hostVMRequestedDesc = prometheus.NewDesc(
+		prometheus.BuildFQName(prometheusNamespace, "host", "vm_requested"),
+		"CPU cores and memory bytes requested by the VM pods scheduled to the management cluster node.",
+		[]string{labelNode, labelRole, labelResource},
+		nil,
+	)
+	hostVMsDesc = prometheus.NewDesc(
+		prometheus.BuildFQName(prometheusNamespace, "host", "vms"),
+		"Number of VM pods scheduled to the management cluster node.",
+		[]string{labelNode, labelRole},
+		nil,
+	)
+	hostLargestVMDesc = prometheus.NewDesc(
+		prometheus.BuildFQName(prometheusNamespace, "host", "largest_vm"),
+		"CPU cores and memory bytes of the largest VM of the role which still fits on any management cluster node.",
+		[]string{labelRole, labelResource},
+		nil,
+	)
+)
+
+type HostCollectorConfig struct {
+	Inventory Inventory
+	Logger    micrologger.Logger
+}
+
+// HostCollector implements prometheus.Collector and exposes the capacity and
+// VM density of the management cluster nodes VM pods are scheduled to.
+type HostCollector struct {
+	inventory Inventory
+	logger    micrologger.Logger
+}
+
+func NewHostCollector(config HostCollectorConfig) (*HostCollector, error) {
+	if config.Inventory == nil {
+		return nil, microerror.Maskf(invalidConfigError, "%T.Inventory must not be empty", config)
+	}
+	if config.Logger == nil {
+		return nil, microerror.Maskf(invalidConfigError, "%T.Logger must not be empty", config)
+	}
+
+	c := &HostCollector{
+		inventory: config.Inventory,
+		logger:    config.Logger,
+	}
+
+	return c, nil
+}
+
+func (c *HostCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- hostAllocatableDesc
+	ch <- hostRequestedDesc
+	ch <- hostVMRequestedDesc
+	ch <- hostVMsDesc
+	ch <- hostLargestVMDesc
+}
+
+func (c *HostCollector) Collect(ch chan<- prometheus.Metric) {
+	ctx := context.Background()
+
+	err := c.collect(ch)
+	if inventory.IsNotYetAvailable(err) {
+		c.logger.Debugf(ctx, "not collecting host metrics: %s", err)
+	} else if err != nil {
+		c.logger.Errorf(ctx, err, "failed to collect host metrics")
+	}
+}
+
+// host is the capacity of a management cluster node and the resources
+// requested by the pods scheduled to it. The requests of all pods count
+// towards whether another VM fits, while the requests of the VM pods are
+// tracked separately.
+type host struct {
+	name string
+	role string
+
+	allocatableCPU    resource.Quantity
+	allocatableMemory resource.Quantity
+	requestedCPU      resource.Quantity
+	requestedMemory   resource.Quantity
+	vmRequestedCPU    resource.Quantity
+	vmRequestedMemory resource.Quantity
+	vms               int
+}
+
+func (c *HostCollector) collect(ch chan<- prometheus.Metric) error {
+	var hosts []*host
+	{
+		nodes, err := c.inventory.Hosts()
+		if err != nil {
+			return microerror.Mask(err)
+		}
+
+		for _, n := range nodes {
+			hosts = append(hosts, &host{
+				name:              n.GetName(),
+				role:              n.GetLabels()[labelRole],
+				allocatableCPU:    n.Status.Allocatable[corev1.ResourceCPU],
+				allocatableMemory: n.Status.Allocatable[corev1.ResourceMemory],
+			})
+		}
+	}
+
+	for _, h := range hosts {
+		pods, err := c.inventory.HostPods(h.name)
+		if err != nil {
+			return microerror.Mask(err)
+		}
+
+		h.addPods(pods)
+	}
+
+	for _, h := range hosts {
+		ch <- prometheus.MustNewConstMetric(hostAllocatableDesc, prometheus.GaugeValue, float64(h.allocatableCPU.MilliValue())/1000, h.name, h.role, resourceCPU)
+		ch <- prometheus.MustNewConstMetric(hostAllocatableDesc, prometheus.GaugeValue, float64(h.allocatableMemory.Value()), h.name, h.role, resourceMemory)
+		ch <- prometheus.MustNewConstMetric(hostRequestedDesc, prometheus.GaugeValue, float64(h.requestedCPU.MilliValue())/1000, h.name, h.role, resourceCPU)
+		ch <- prometheus.MustNewConstMetric(hostRequestedDesc, prometheus.GaugeValue, float64(h.requestedMemory.Value()), h.name, h.role, resourceMemory)
+		ch <- prometheus.MustNewConstMetric(hostVMRequestedDesc, prometheus.GaugeValue, float64(h.vmRequestedCPU.MilliValue())/1000, h.name, h.role, resourceCPU)
+		ch <- prometheus.MustNewConstMetric(hostVMRequestedDesc, prometheus.GaugeValue, float64(h.vmRequestedMemory.Value()), h.name, h.role, resourceMemory)
+		ch <- prometheus.MustNewConstMetric(hostVMsDesc, prometheus.GaugeValue, float64(h.vms), h.name, h.role)
+	}
+
+	for _, role := range []string{key.MasterID, key.WorkerID} {
+		cpus, memory, err := largestVM(hosts, role)
+		if err != nil {
+			return microerror.Mask(err)
+		}
+
+		ch <- prometheus.MustNewConstMetric(hostLargestVMDesc, prometheus.GaugeValue, float64(cpus), role, resourceCPU)
+		ch <- prometheus.MustNewConstMetric(hostLargestVMDesc, prometheus.GaugeValue, float64(memory), role, resourceMemory)
+	}
+
+	return nil
+}
+
+// addPods adds the requests of the given pods scheduled to the host. Pods
+// which are terminated are ignored, since they do not hold resources anymore.
+func (h *host) addPods(pods []*corev1.Pod) {
+	for _, p := range pods {
+		if p.Spec.NodeName != h.name {
+			continue
+		}
+		if p.Status.Phase == corev1.PodSucceeded || p.Status.Phase == corev1.PodFailed {
+			continue
+		}
+
+		cpu, memory := podRequests(p)
+
+		h.requestedCPU.Add(cpu)
+		h.requestedMemory.Add(memory)
+
+		if p.GetLabels()[key.PodWatcherLabel] == key.OperatorName {
+			h.vmRequestedCPU.Add(cpu)
+			h.vmRequestedMemory.Add(memory)
+			h.vms++
+		}
+	}
+}
+
+// podRequests returns the CPU and memory requests of the given pod the way
+// the scheduler accounts for them. These are the sum of the requests of all
+// containers or the largest requests of any init container, whichever is
+// higher, plus the pod overhead.
+func podRequests(p *corev1.Pod) (resource.Quantity, resource.Quantity) {
+	var cpu, memory resource.Quantity
+	for _, container := range p.Spec.Containers {
+		cpu.Add(container.Resources.Requests[corev1.ResourceCPU])
+		memory.Add(container.Resources.Requests[corev1.ResourceMemory])
+	}
+
+	for _, container := range p.Spec.InitContainers {
+		if q := container.Resources.Requests[corev1.ResourceCPU]; q.Cmp(cpu) > 0 {
+			cpu = q.DeepCopy()
+		}
+		if q := container.Resources.Requests[corev1.ResourceMemory]; q.Cmp(memory) > 0 {
+			memory = q.DeepCopy()
+		}
+	}
+
+	cpu.Add(p.Spec.Overhead[corev1.ResourceCPU])
+	memory.Add(p.Spec.Overhead[corev1.ResourceMemory])
+
+	return cpu, memory
+}
+
+// largestVM returns the CPUs and memory bytes of the largest VM of the given
+// role which fits on any host of that role. The memory overhead of the VM pod
+// is accounted for like in the deployments of the role. VMs are compared by
+// memory first and by CPUs second, memory is searched in steps of 1G.
+func largestVM(hosts []*host, role string) (int64, int64, error) {
+	var bestCPUs, bestMemory int64
+	for _, h := range hosts {
+		if h.role != role {
+			continue
+		}
+
+		freeCPU := h.allocatableCPU.MilliValue() - h.requestedCPU.MilliValue()
+		freeMemory := h.allocatableMemory.Value() - h.requestedMemory.Value()
+
+		cpus := freeCPU / 1000
+		if cpus < 1 {
+			continue
+		}
+
+		var memory int64
+		for g := int64(1); g*gigabyte <= freeMemory; g++ {
+			n := v1alpha1.KVMConfigSpecKVMNode{
+				Memory: fmt.Sprintf("%dG", g),
+			}
+
+			var q resource.Quantity
+			var err error
+			if role == key.MasterID {
+				q, err = key.MemoryQuantityMaster(n)
+			} else {
+				q, err = key.MemoryQuantityWorker(n)
+			}
+			if err != nil {
+				return 0, 0, microerror.Mask(err)
+			}
+
+			if q.Value() > freeMemory {
+				break
+			}
+			memory = g * gigabyte
+		}
+		if memory == 0 {
+			continue
+		}
+
+		if memory > bestMemory || (memory == bestMemory && cpus > bestCPUs) {
+			bestCPUs = cpus
+			bestMemory = memory
+		}
+	}
+
+	return bestCPUs, bestMemory, nil
+}
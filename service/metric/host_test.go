@@ -0,0 +1,145 @@
+package metric
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/giantswarm/micrologger/microloggertest"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/giantswarm/kvm-operator/v4/service/controller/key"
+)
+
+func testHost(name string, role string, cpu string, memory string) *corev1.Node {
+	return &corev1.Node{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: name,
+			Labels: map[string]string{
+				"role": role,
+			},
+		},
+		Status: corev1.NodeStatus{
+			Allocatable: corev1.ResourceList{
+				corev1.ResourceCPU:    resource.MustParse(cpu),
+				corev1.ResourceMemory: resource.MustParse(memory),
+			},
+		},
+	}
+}
+
+func testVMPod(name string, host string, phase corev1.PodPhase, cpu string, memory string) *corev1.Pod {
+	return &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: "al9qy",
+			Labels: map[string]string{
+				key.PodWatcherLabel: key.OperatorName,
+			},
+		},
+		Spec: corev1.PodSpec{
+			NodeName: host,
+			Containers: []corev1.Container{
+				{
+					Resources: corev1.ResourceRequirements{
+						Requests: corev1.ResourceList{
+							corev1.ResourceCPU:    resource.MustParse(cpu),
+							corev1.ResourceMemory: resource.MustParse(memory),
+						},
+					},
+				},
+			},
+		},
+		Status: corev1.PodStatus{
+			Phase: phase,
+		},
+	}
+}
+
+func Test_HostCollector(t *testing.T) {
+	// Pods other than VM pods count towards the requested resources of the
+	// host, with init containers accounted for like by the scheduler.
+	daemon := testVMPod("node-exporter", "host-b", corev1.PodRunning, "2", "4G")
+	daemon.Namespace = "monitoring"
+	daemon.Labels = nil
+	daemon.Spec.InitContainers = []corev1.Container{
+		{
+			Resources: corev1.ResourceRequirements{
+				Requests: corev1.ResourceList{
+					corev1.ResourceMemory: resource.MustParse("8G"),
+				},
+			},
+		},
+	}
+
+	c, err := NewHostCollector(HostCollectorConfig{
+		Inventory: testInventory{
+			hosts: []*corev1.Node{
+				testHost("host-a", key.WorkerID, "16", "64G"),
+				testHost("host-b", key.WorkerID, "8", "32G"),
+				testHost("host-c", key.MasterID, "4", "8G"),
+			},
+			pods: []*corev1.Pod{
+				testVMPod("worker-a", "host-a", corev1.PodRunning, "4", "20G"),
+				testVMPod("worker-b", "host-a", corev1.PodRunning, "4", "20G"),
+				testVMPod("worker-c", "host-b", corev1.PodFailed, "4", "20G"),
+				testVMPod("worker-d", "", corev1.PodPending, "4", "20G"),
+				testVMPod("master-a", "host-c", corev1.PodRunning, "3", "5G"),
+				daemon,
+			},
+		},
+		Logger: microloggertest.New(),
+	})
+	if err != nil {
+		t.Fatalf("expected nil, got %#v", err)
+	}
+
+	// host-a and host-b have 24G free, which fits a worker VM with 20G memory.
+	// host-b would fit a worker VM with 27G memory without the requests of the
+	// node-exporter. host-c has 1 CPU and 3G free, which fits a master VM with
+	// 1G memory.
+	expected := `
+# HELP kvm_operator_host_allocatable Allocatable CPU cores and memory bytes of the management cluster node.
+# TYPE kvm_operator_host_allocatable gauge
+kvm_operator_host_allocatable{node="host-a",resource="cpu",role="worker"} 16
+kvm_operator_host_allocatable{node="host-a",resource="memory",role="worker"} 6.4e+10
+kvm_operator_host_allocatable{node="host-b",resource="cpu",role="worker"} 8
+kvm_operator_host_allocatable{node="host-b",resource="memory",role="worker"} 3.2e+10
+kvm_operator_host_allocatable{node="host-c",resource="cpu",role="master"} 4
+kvm_operator_host_allocatable{node="host-c",resource="memory",role="master"} 8e+09
+# HELP kvm_operator_host_largest_vm CPU cores and memory bytes of the largest VM of the role which still fits on any management cluster node.
+# TYPE kvm_operator_host_largest_vm gauge
+kvm_operator_host_largest_vm{resource="cpu",role="master"} 1
+kvm_operator_host_largest_vm{resource="cpu",role="worker"} 8
+kvm_operator_host_largest_vm{resource="memory",role="master"} 1e+09
+kvm_operator_host_largest_vm{resource="memory",role="worker"} 2e+10
+# HELP kvm_operator_host_requested CPU cores and memory bytes requested by all non-terminated pods scheduled to the management cluster node.
+# TYPE kvm_operator_host_requested gauge
+kvm_operator_host_requested{node="host-a",resource="cpu",role="worker"} 8
+kvm_operator_host_requested{node="host-a",resource="memory",role="worker"} 4e+10
+kvm_operator_host_requested{node="host-b",resource="cpu",role="worker"} 2
+kvm_operator_host_requested{node="host-b",resource="memory",role="worker"} 8e+09
+kvm_operator_host_requested{node="host-c",resource="cpu",role="master"} 3
+kvm_operator_host_requested{node="host-c",resource="memory",role="master"} 5e+09
+# HELP kvm_operator_host_vm_requested CPU cores and memory bytes requested by the VM pods scheduled to the management cluster node.
+# TYPE kvm_operator_host_vm_requested gauge
+kvm_operator_host_vm_requested{node="host-a",resource="cpu",role="worker"} 8
+kvm_operator_host_vm_requested{node="host-a",resource="memory",role="worker"} 4e+10
+kvm_operator_host_vm_requested{node="host-b",resource="cpu",role="worker"} 0
+kvm_operator_host_vm_requested{node="host-b",resource="memory",role="worker"} 0
+kvm_operator_host_vm_requested{node="host-c",resource="cpu",role="master"} 3
+kvm_operator_host_vm_requested{node="host-c",resource="memory",role="master"} 5e+09
+# HELP kvm_operator_host_vms Number of VM pods scheduled to the management cluster node.
+# TYPE kvm_operator_host_vms gauge
+kvm_operator_host_vms{node="host-a",role="worker"} 2
+kvm_operator_host_vms{node="host-b",role="worker"} 0
+kvm_operator_host_vms{node="host-c",role="master"} 1
+`
+
+	err = testutil.CollectAndCompare(c, strings.NewReader(expected))
+	if err != nil {
+		t.Fatalf("expected nil, got %s", err)
+	}
+}
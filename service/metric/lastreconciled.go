@@ -0,0 +1,39 @@
+package metric
+
+import (
+	"math"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	dto "github.com/prometheus/client_model/go"
+)
+
+// LatestClusterReconciliation returns the most recent time of the last
+// reconciliations recorded in ClusterLastReconciledGauge. False is returned in
+// case no cluster has been reconciled yet.
+func LatestClusterReconciliation() (time.Time, bool) {
+	ch := make(chan prometheus.Metric)
+	go func() {
+		ClusterLastReconciledGauge.Collect(ch)
+		close(ch)
+	}()
+
+	var latest float64
+	for m := range ch {
+		var d dto.Metric
+		err := m.Write(&d)
+		if err != nil {
+			continue
+		}
+
+		latest = math.Max(latest, d.GetGauge().GetValue())
+	}
+
+	if latest == 0 {
+		return time.Time{}, false
+	}
+
+	seconds, fraction := math.Modf(latest)
+
+	return time.Unix(int64(seconds), int64(fraction*1e9)), true
+}
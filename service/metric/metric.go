@@ -9,6 +9,11 @@ const (
 	prometheusSubsystem = "deployment_resource"
 )
 
+const (
+	labelClusterID    = "cluster_id"
+	labelOrganization = "organization"
+)
+
 var VersionBundleVersionGauge = prometheus.NewGaugeVec(
 	prometheus.GaugeOpts{
 		Namespace: prometheusNamespace,
@@ -19,6 +24,59 @@ var VersionBundleVersionGauge = prometheus.NewGaugeVec(
 	[]string{"major", "minor", "patch"},
 )
 
+// ClusterLastReconciledGauge is set to the time of the last reconciliation of
+// a cluster which ran through all resources.
+var ClusterLastReconciledGauge = prometheus.NewGaugeVec(
+	prometheus.GaugeOpts{
+		Namespace: prometheusNamespace,
+		Subsystem: "cluster",
+		Name:      "last_reconciled_timestamp_seconds",
+		Help:      "Unix timestamp of the last successful reconciliation of the cluster.",
+	},
+	[]string{labelClusterID, labelOrganization},
+)
+
+// DrainDurationHistogram observes the time from the deletion of a VM pod until
+// its node is treated as drained.
+var DrainDurationHistogram = prometheus.NewHistogramVec(
+	prometheus.HistogramOpts{
+		Namespace: prometheusNamespace,
+		Subsystem: "drainer",
+		Name:      "drain_duration_seconds",
+		Help:      "Duration of draining workload cluster nodes labeled by how draining finished.",
+		Buckets:   prometheus.ExponentialBuckets(15, 2, 8),
+	},
+	[]string{labelClusterID, labelOrganization, "result"},
+)
+
+// NodeControllerStateGauge is 1 for the current state of the node informer of
+// a cluster and 0 for all other states.
+var NodeControllerStateGauge = prometheus.NewGaugeVec(
+	prometheus.GaugeOpts{
+		Namespace: prometheusNamespace,
+		Subsystem: "node_controller",
+		Name:      "state",
+		Help:      "State of the node controller and its connection to the workload cluster API.",
+	},
+	[]string{labelClusterID, labelOrganization, "state"},
+)
+
+// UnhealthyNodeTerminationsCounter counts the VM pods deleted because their
+// workload cluster node was unhealthy.
+var UnhealthyNodeTerminationsCounter = prometheus.NewCounterVec(
+	prometheus.CounterOpts{
+		Namespace: prometheusNamespace,
+		Subsystem: "unhealthy_node_terminator",
+		Name:      "terminations_total",
+		Help:      "Number of unhealthy workload cluster nodes terminated.",
+	},
+	[]string{labelClusterID, labelOrganization},
+)
+
 func init() {
 	prometheus.MustRegister(VersionBundleVersionGauge)
+	prometheus.MustRegister(ClusterLastReconciledGauge)
+	prometheus.MustRegister(DrainDurationHistogram)
+	prometheus.MustRegister(NodeControllerStateGauge)
+	prometheus.MustRegister(UnhealthyNodeTerminationsCounter)
 }
@@ -0,0 +1,278 @@
+// Package readiness aggregates the state of the operator's controllers and
+// caches into per-check readiness results.
+package readiness
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/giantswarm/microerror"
+	"github.com/giantswarm/micrologger"
+
+	"github.com/giantswarm/kvm-operator/v4/service/inventory"
+	"github.com/giantswarm/kvm-operator/v4/service/metric"
+)
+
+const (
+	// StatusOK is the status of passing checks and of the overall readiness
+	// in case all checks pass.
+	StatusOK = "ok"
+	// StatusFailed is the status of failing checks and of the overall
+	// readiness in case any check fails.
+	StatusFailed = "failed"
+
+	checkInformers      = "informers"
+	checkLastReconcile  = "lastReconcile"
+	checkLeaderElection = "leaderElection"
+	checkNodeInformers  = "nodeInformers"
+
+	// nodeInformerPanicked is the node informer status persisted by the
+	// nodecontroller resource in case the node controller of a workload
+	// cluster panicked.
+	nodeInformerPanicked = "Panicked"
+)
+
+// Component is a part of the operator which is ready once its booted channel
+// is closed, e.g. an operatorkit controller.
+type Component struct {
+	Name   string
+	Booted <-chan struct{}
+	// Leader is true for components which are only booted in the replica
+	// holding the leader election lease.
+	Leader bool
+}
+
+// Inventory provides the cached clusters the readiness checks are computed
+// from. It is implemented by inventory.Service.
+type Inventory interface {
+	Clusters() ([]inventory.Cluster, error)
+	HasSynced() bool
+}
+
+type Config struct {
+	Components []Component
+	Inventory  Inventory
+	// Leading is closed once this replica acquired the leader election lease.
+	// It is nil in case leader election is disabled, which means this replica
+	// is always leading.
+	Leading <-chan struct{}
+	Logger  micrologger.Logger
+
+	// MaxReconcileAge is the maximum duration since the last successful
+	// reconciliation of any cluster after which the operator is not ready
+	// anymore.
+	MaxReconcileAge time.Duration
+}
+
+// Check is the result of a single readiness check.
+type Check struct {
+	Name    string `json:"name"`
+	Status  string `json:"status"`
+	Message string `json:"message,omitempty"`
+}
+
+// Response is the result of all readiness checks. Its status is StatusOK in
+// case all checks pass.
+type Response struct {
+	Status string  `json:"status"`
+	Checks []Check `json:"checks"`
+}
+
+type Service struct {
+	components []Component
+	inventory  Inventory
+	leading    <-chan struct{}
+	logger     micrologger.Logger
+
+	lastReconciled  func() (time.Time, bool)
+	maxReconcileAge time.Duration
+	started         time.Time
+}
+
+func New(config Config) (*Service, error) {
+	if config.Inventory == nil {
+		return nil, microerror.Maskf(invalidConfigError, "%T.Inventory must not be empty", config)
+	}
+	if config.Logger == nil {
+		return nil, microerror.Maskf(invalidConfigError, "%T.Logger must not be empty", config)
+	}
+
+	if config.MaxReconcileAge == 0 {
+		return nil, microerror.Maskf(invalidConfigError, "%T.MaxReconcileAge must not be empty", config)
+	}
+
+	s := &Service{
+		components: config.Components,
+		inventory:  config.Inventory,
+		leading:    config.Leading,
+		logger:     config.Logger,
+
+		lastReconciled:  metric.LatestClusterReconciliation,
+		maxReconcileAge: config.MaxReconcileAge,
+		started:         time.Now(),
+	}
+
+	return s, nil
+}
+
+// Check runs all readiness checks.
+func (s *Service) Check() Response {
+	return s.check(time.Now())
+}
+
+func (s *Service) check(now time.Time) Response {
+	leading := s.isLeading()
+
+	var checks []Check
+	if s.leading != nil {
+		checks = append(checks, checkLeader(leading))
+	}
+	for _, c := range s.components {
+		checks = append(checks, checkComponent(c, leading))
+	}
+	checks = append(checks, s.checkInformers(), s.checkNodeInformers())
+	if leading {
+		checks = append(checks, s.checkLastReconcile(now))
+	} else {
+		checks = append(checks, Check{Name: checkLastReconcile, Status: StatusOK, Message: "standby"})
+	}
+
+	response := Response{
+		Status: StatusOK,
+		Checks: checks,
+	}
+	for _, c := range checks {
+		if c.Status != StatusOK {
+			response.Status = StatusFailed
+		}
+	}
+
+	return response
+}
+
+func (s *Service) isLeading() bool {
+	if s.leading == nil {
+		return true
+	}
+
+	select {
+	case <-s.leading:
+		return true
+	default:
+		return false
+	}
+}
+
+// checkLeader reports whether this replica is leading. Standby
+// replicas are ready, so that they can take over at any time.
+func checkLeader(leading bool) Check {
+	if leading {
+		return Check{Name: checkLeaderElection, Status: StatusOK, Message: "leading"}
+	}
+
+	return Check{Name: checkLeaderElection, Status: StatusOK, Message: "standby"}
+}
+
+func checkComponent(c Component, leading bool) Check {
+	if c.Leader && !leading {
+		return Check{Name: c.Name, Status: StatusOK, Message: "standby"}
+	}
+
+	select {
+	case <-c.Booted:
+		return Check{Name: c.Name, Status: StatusOK}
+	default:
+		return Check{Name: c.Name, Status: StatusFailed, Message: "not booted"}
+	}
+}
+
+func (s *Service) checkInformers() Check {
+	if !s.inventory.HasSynced() {
+		return Check{Name: checkInformers, Status: StatusFailed, Message: "caches not synced"}
+	}
+
+	return Check{Name: checkInformers, Status: StatusOK}
+}
+
+// checkNodeInformers fails in case the node controller of any workload
+// cluster panicked. Workload clusters the operator cannot talk to are
+// reported, but do not fail the check, since they are no issue of the
+// operator itself.
+func (s *Service) checkNodeInformers() Check {
+	if !s.inventory.HasSynced() {
+		return Check{Name: checkNodeInformers, Status: StatusFailed, Message: "caches not synced"}
+	}
+
+	clusters, err := s.inventory.Clusters()
+	if err != nil {
+		return Check{Name: checkNodeInformers, Status: StatusFailed, Message: err.Error()}
+	}
+
+	counts := map[string]int{}
+	var panicked []string
+	for _, c := range clusters {
+		status := c.NodeInformer
+		if status == "" {
+			status = "Unknown"
+		}
+		counts[status]++
+
+		if status == nodeInformerPanicked {
+			panicked = append(panicked, c.ID)
+		}
+	}
+
+	var summary []string
+	for status, count := range counts {
+		summary = append(summary, fmt.Sprintf("%d %s", count, status))
+	}
+	sort.Strings(summary)
+
+	check := Check{
+		Name:    checkNodeInformers,
+		Status:  StatusOK,
+		Message: strings.Join(summary, ", "),
+	}
+	if len(panicked) > 0 {
+		check.Status = StatusFailed
+		check.Message = fmt.Sprintf("node controller panicked for clusters %s", strings.Join(panicked, ", "))
+	}
+
+	return check
+}
+
+// checkLastReconcile fails in case no cluster has been reconciled
+// successfully within the configured maximum age. Without any successful
+// reconciliation yet the age is measured from the start of the operator.
+func (s *Service) checkLastReconcile(now time.Time) Check {
+	if !s.inventory.HasSynced() {
+		return Check{Name: checkLastReconcile, Status: StatusFailed, Message: "caches not synced"}
+	}
+
+	clusters, err := s.inventory.Clusters()
+	if err != nil {
+		return Check{Name: checkLastReconcile, Status: StatusFailed, Message: err.Error()}
+	}
+
+	if len(clusters) == 0 {
+		return Check{Name: checkLastReconcile, Status: StatusOK, Message: "no clusters"}
+	}
+
+	var age time.Duration
+	var message string
+	if last, ok := s.lastReconciled(); ok {
+		age = now.Sub(last)
+		message = fmt.Sprintf("last successful reconciliation %s ago", age.Round(time.Second))
+	} else {
+		age = now.Sub(s.started)
+		message = fmt.Sprintf("no successful reconciliation since start %s ago", age.Round(time.Second))
+	}
+
+	if age > s.maxReconcileAge {
+		return Check{Name: checkLastReconcile, Status: StatusFailed, Message: message}
+	}
+
+	return Check{Name: checkLastReconcile, Status: StatusOK, Message: message}
+}
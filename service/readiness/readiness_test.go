@@ -0,0 +1,198 @@
+package readiness
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/giantswarm/kvm-operator/v4/service/inventory"
+)
+
+type testInventory struct {
+	clusters []inventory.Cluster
+	synced   bool
+}
+
+func (i testInventory) Clusters() ([]inventory.Cluster, error) {
+	return i.clusters, nil
+}
+
+func (i testInventory) HasSynced() bool {
+	return i.synced
+}
+
+func Test_Service_check(t *testing.T) {
+	now := time.Date(2021, 6, 1, 12, 0, 0, 0, time.UTC)
+
+	booted := make(chan struct{})
+	close(booted)
+
+	testCases := []struct {
+		name           string
+		components     []Component
+		inventory      testInventory
+		leading        chan struct{}
+		lastReconciled time.Time
+		expected       Response
+	}{
+		{
+			name: "case 0: all checks pass",
+			components: []Component{
+				{Name: "clusterController", Booted: booted},
+			},
+			inventory: testInventory{
+				clusters: []inventory.Cluster{
+					{ID: "al9qy", NodeInformer: "Healthy"},
+					{ID: "f9k2x", NodeInformer: "Unhealthy"},
+				},
+				synced: true,
+			},
+			lastReconciled: now.Add(-time.Minute),
+			expected: Response{
+				Status: StatusOK,
+				Checks: []Check{
+					{Name: "clusterController", Status: StatusOK},
+					{Name: "informers", Status: StatusOK},
+					{Name: "nodeInformers", Status: StatusOK, Message: "1 Healthy, 1 Unhealthy"},
+					{Name: "lastReconcile", Status: StatusOK, Message: "last successful reconciliation 1m0s ago"},
+				},
+			},
+		},
+		{
+			name: "case 1: controller not booted and caches not synced",
+			components: []Component{
+				{Name: "clusterController", Booted: make(chan struct{})},
+			},
+			inventory: testInventory{},
+			expected: Response{
+				Status: StatusFailed,
+				Checks: []Check{
+					{Name: "clusterController", Status: StatusFailed, Message: "not booted"},
+					{Name: "informers", Status: StatusFailed, Message: "caches not synced"},
+					{Name: "nodeInformers", Status: StatusFailed, Message: "caches not synced"},
+					{Name: "lastReconcile", Status: StatusFailed, Message: "caches not synced"},
+				},
+			},
+		},
+		{
+			name: "case 2: node controller panicked and reconciliation stale",
+			inventory: testInventory{
+				clusters: []inventory.Cluster{
+					{ID: "al9qy", NodeInformer: "Panicked"},
+					{ID: "f9k2x"},
+				},
+				synced: true,
+			},
+			lastReconciled: now.Add(-time.Hour),
+			expected: Response{
+				Status: StatusFailed,
+				Checks: []Check{
+					{Name: "informers", Status: StatusOK},
+					{Name: "nodeInformers", Status: StatusFailed, Message: "node controller panicked for clusters al9qy"},
+					{Name: "lastReconcile", Status: StatusFailed, Message: "last successful reconciliation 1h0m0s ago"},
+				},
+			},
+		},
+		{
+			name: "case 3: no successful reconciliation since start",
+			inventory: testInventory{
+				clusters: []inventory.Cluster{
+					{ID: "al9qy", NodeInformer: "Healthy"},
+				},
+				synced: true,
+			},
+			expected: Response{
+				Status: StatusOK,
+				Checks: []Check{
+					{Name: "informers", Status: StatusOK},
+					{Name: "nodeInformers", Status: StatusOK, Message: "1 Healthy"},
+					{Name: "lastReconcile", Status: StatusOK, Message: "no successful reconciliation since start 5m0s ago"},
+				},
+			},
+		},
+		{
+			name: "case 4: no clusters",
+			inventory: testInventory{
+				synced: true,
+			},
+			expected: Response{
+				Status: StatusOK,
+				Checks: []Check{
+					{Name: "informers", Status: StatusOK},
+					{Name: "nodeInformers", Status: StatusOK},
+					{Name: "lastReconcile", Status: StatusOK, Message: "no clusters"},
+				},
+			},
+		},
+		{
+			name: "case 5: leading replica",
+			components: []Component{
+				{Name: "clusterController", Booted: booted, Leader: true},
+				{Name: "statusResourceCollector", Booted: booted},
+			},
+			inventory: testInventory{
+				synced: true,
+			},
+			leading: booted,
+			expected: Response{
+				Status: StatusOK,
+				Checks: []Check{
+					{Name: "leaderElection", Status: StatusOK, Message: "leading"},
+					{Name: "clusterController", Status: StatusOK},
+					{Name: "statusResourceCollector", Status: StatusOK},
+					{Name: "informers", Status: StatusOK},
+					{Name: "nodeInformers", Status: StatusOK},
+					{Name: "lastReconcile", Status: StatusOK, Message: "no clusters"},
+				},
+			},
+		},
+		{
+			name: "case 6: standby replica",
+			components: []Component{
+				{Name: "clusterController", Booted: make(chan struct{}), Leader: true},
+				{Name: "statusResourceCollector", Booted: make(chan struct{})},
+			},
+			inventory: testInventory{
+				clusters: []inventory.Cluster{
+					{ID: "al9qy", NodeInformer: "Healthy"},
+				},
+				synced: true,
+			},
+			leading: make(chan struct{}),
+			expected: Response{
+				Status: StatusFailed,
+				Checks: []Check{
+					{Name: "leaderElection", Status: StatusOK, Message: "standby"},
+					{Name: "clusterController", Status: StatusOK, Message: "standby"},
+					{Name: "statusResourceCollector", Status: StatusFailed, Message: "not booted"},
+					{Name: "informers", Status: StatusOK},
+					{Name: "nodeInformers", Status: StatusOK, Message: "1 Healthy"},
+					{Name: "lastReconcile", Status: StatusOK, Message: "standby"},
+				},
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			s := &Service{
+				components: tc.components,
+				inventory:  tc.inventory,
+				leading:    tc.leading,
+
+				lastReconciled: func() (time.Time, bool) {
+					return tc.lastReconciled, !tc.lastReconciled.IsZero()
+				},
+				maxReconcileAge: 15 * time.Minute,
+				started:         now.Add(-5 * time.Minute),
+			}
+
+			result := s.check(now)
+
+			if !cmp.Equal(result, tc.expected) {
+				t.Fatalf("\n\n%s\n", cmp.Diff(tc.expected, result))
+			}
+		})
+	}
+}
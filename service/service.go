@@ -2,6 +2,8 @@ package service
 
 import (
 	"context"
+	"fmt"
+	"os"
 	"sync"
 	"time"
 
@@ -12,15 +14,26 @@ import (
 	"github.com/giantswarm/microendpoint/service/version"
 	"github.com/giantswarm/microerror"
 	"github.com/giantswarm/micrologger"
+	operatorkitcontroller "github.com/giantswarm/operatorkit/v5/pkg/controller"
 	"github.com/giantswarm/statusresource/v3"
 	workloadcluster "github.com/giantswarm/tenantcluster/v4/pkg/tenantcluster"
 	"github.com/giantswarm/versionbundle"
+	"github.com/prometheus/client_golang/prometheus"
 	"github.com/spf13/viper"
+	corev1 "k8s.io/api/core/v1"
+	typedcorev1 "k8s.io/client-go/kubernetes/typed/core/v1"
 	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/leaderelection"
+	"k8s.io/client-go/tools/leaderelection/resourcelock"
+	"k8s.io/client-go/tools/record"
 
 	"github.com/giantswarm/kvm-operator/v4/flag"
 	"github.com/giantswarm/kvm-operator/v4/pkg/project"
+	"github.com/giantswarm/kvm-operator/v4/pkg/shard"
 	"github.com/giantswarm/kvm-operator/v4/service/controller"
+	"github.com/giantswarm/kvm-operator/v4/service/inventory"
+	"github.com/giantswarm/kvm-operator/v4/service/metric"
+	"github.com/giantswarm/kvm-operator/v4/service/readiness"
 )
 
 // Config represents the configuration used to create a new service.
@@ -34,7 +47,11 @@ type Config struct {
 }
 
 type Service struct {
-	Version *version.Service
+	Inventory *inventory.Service
+	Readiness *readiness.Service
+	Version   *version.Service
+
+	logger micrologger.Logger
 
 	bootOnce                          sync.Once
 	clusterController                 *controller.Cluster
@@ -42,6 +59,13 @@ type Service struct {
 	drainerController                 *controller.Drainer
 	unhealthyNodeTerminatorController *controller.UnhealthyNodeTerminator
 	statusResourceCollector           *statusresource.CollectorSet
+	statusResourceCollectorBooted     chan struct{}
+
+	// leaderElection is nil in case leader election is disabled. Otherwise
+	// the controllers are only booted once leading is closed, which is when
+	// this replica acquired the leader election lease.
+	leaderElection *leaderelection.LeaderElectionConfig
+	leading        chan struct{}
 }
 
 // New creates a new service with given configuration.
@@ -65,6 +89,19 @@ func New(config Config) (*Service, error) {
 
 	var err error
 
+	var clusterShard shard.Shard
+	{
+		clusterShard = shard.Shard{
+			Count: config.Viper.GetInt(config.Flag.Service.Shard.Count),
+			Index: config.Viper.GetInt(config.Flag.Service.Shard.Index),
+		}
+
+		err = clusterShard.Validate()
+		if err != nil {
+			return nil, microerror.Maskf(invalidConfigError, "%T.Flag.Service.Shard is invalid: %s", config, err)
+		}
+	}
+
 	var restConfig *rest.Config
 	{
 		c := k8srestconfig.Config{
@@ -103,6 +140,68 @@ func New(config Config) (*Service, error) {
 		}
 	}
 
+	var eventRecorder record.EventRecorder
+	{
+		broadcaster := record.NewBroadcaster()
+		broadcaster.StartRecordingToSink(&typedcorev1.EventSinkImpl{
+			Interface: k8sClient.K8sClient().CoreV1().Events(""),
+		})
+
+		eventRecorder = broadcaster.NewRecorder(k8sClient.Scheme(), corev1.EventSource{
+			Component: project.Name(),
+		})
+	}
+
+	var inventoryService *inventory.Service
+	{
+		c := inventory.Config{
+			G8sClient: k8sClient.G8sClient(),
+			K8sClient: k8sClient.K8sClient(),
+			Logger:    config.Logger,
+
+			Shard: clusterShard,
+		}
+
+		inventoryService, err = inventory.New(c)
+		if err != nil {
+			return nil, microerror.Mask(err)
+		}
+	}
+
+	{
+		c := metric.CollectorConfig{
+			Inventory: inventoryService,
+			Logger:    config.Logger,
+		}
+
+		collector, err := metric.NewCollector(c)
+		if err != nil {
+			return nil, microerror.Mask(err)
+		}
+
+		err = prometheus.Register(collector)
+		if err != nil {
+			return nil, microerror.Mask(err)
+		}
+	}
+
+	{
+		c := metric.HostCollectorConfig{
+			Inventory: inventoryService,
+			Logger:    config.Logger,
+		}
+
+		collector, err := metric.NewHostCollector(c)
+		if err != nil {
+			return nil, microerror.Mask(err)
+		}
+
+		err = prometheus.Register(collector)
+		if err != nil {
+			return nil, microerror.Mask(err)
+		}
+	}
+
 	var certsSearcher certs.Interface
 	{
 		c := certs.Config{
@@ -137,6 +236,7 @@ func New(config Config) (*Service, error) {
 	{
 		c := controller.ClusterConfig{
 			CertsSearcher:   certsSearcher,
+			EventRecorder:   eventRecorder,
 			K8sClient:       k8sClient,
 			Logger:          config.Logger,
 			WorkloadCluster: workloadCluster,
@@ -144,9 +244,28 @@ func New(config Config) (*Service, error) {
 			ClusterRoleGeneral: config.Viper.GetString(config.Flag.Service.RBAC.ClusterRole.General),
 			ClusterRolePSP:     config.Viper.GetString(config.Flag.Service.RBAC.ClusterRole.PSP),
 
+			Audit: controller.ClusterConfigAudit{
+				Endpoint: config.Viper.GetString(config.Flag.Service.Installation.Workload.Kubernetes.API.Audit.Endpoint),
+				Policy:   config.Viper.GetString(config.Flag.Service.Installation.Workload.Kubernetes.API.Audit.Policy),
+			},
 			DNSServers:   config.Viper.GetString(config.Flag.Service.Installation.DNS.Servers),
 			IgnitionPath: config.Viper.GetString(config.Flag.Service.Workload.Ignition.Path),
-			NTPServers:   config.Viper.GetString(config.Flag.Service.Installation.NTP.Servers),
+			Ingress: controller.ClusterConfigIngress{
+				Class: config.Viper.GetString(config.Flag.Service.Installation.Workload.Ingress.Class),
+				Gateway: controller.ClusterConfigGateway{
+					Name:        config.Viper.GetString(config.Flag.Service.Installation.Workload.Ingress.Gateway.Name),
+					Namespace:   config.Viper.GetString(config.Flag.Service.Installation.Workload.Ingress.Gateway.Namespace),
+					SectionName: config.Viper.GetString(config.Flag.Service.Installation.Workload.Ingress.Gateway.SectionName),
+				},
+				NodePortRange:          config.Viper.GetString(config.Flag.Service.Installation.Workload.Ingress.NodePortRange),
+				PassthroughAnnotations: config.Viper.GetString(config.Flag.Service.Installation.Workload.Ingress.PassthroughAnnotations),
+				Provider:               config.Viper.GetString(config.Flag.Service.Installation.Workload.Ingress.Provider),
+			},
+			NetworkPolicy: controller.ClusterConfigNetworkPolicy{
+				IngressControllerSelector: config.Viper.GetString(config.Flag.Service.Installation.Workload.NetworkPolicy.IngressControllerSelector),
+				MonitoringSelector:        config.Viper.GetString(config.Flag.Service.Installation.Workload.NetworkPolicy.MonitoringSelector),
+			},
+			NTPServers: config.Viper.GetString(config.Flag.Service.Installation.NTP.Servers),
 			OIDC: controller.ClusterConfigOIDC{
 				ClientID:       config.Viper.GetString(config.Flag.Service.Installation.Workload.Kubernetes.API.Auth.Provider.OIDC.ClientID),
 				IssuerURL:      config.Viper.GetString(config.Flag.Service.Installation.Workload.Kubernetes.API.Auth.Provider.OIDC.IssuerURL),
@@ -165,6 +284,8 @@ func New(config Config) (*Service, error) {
 			DockerhubToken:  config.Viper.GetString(config.Flag.Service.Registry.DockerhubToken),
 			RegistryDomain:  config.Viper.GetString(config.Flag.Service.Registry.Domain),
 			RegistryMirrors: config.Viper.GetStringSlice(config.Flag.Service.Registry.Mirrors),
+
+			Shard: clusterShard,
 		}
 
 		clusterController, err = controller.NewCluster(c)
@@ -177,11 +298,18 @@ func New(config Config) (*Service, error) {
 	{
 		c := controller.DeleterConfig{
 			CertsSearcher:   certsSearcher,
+			EventRecorder:   eventRecorder,
 			K8sClient:       k8sClient,
 			Logger:          config.Logger,
 			WorkloadCluster: workloadCluster,
 
+			OrphanPod: controller.DeleterConfigOrphanPod{
+				GracePeriod: config.Viper.GetDuration(config.Flag.Service.Installation.Workload.OrphanPod.GracePeriod),
+				MaxRecycled: config.Viper.GetInt(config.Flag.Service.Installation.Workload.OrphanPod.MaxRecycled),
+				Recycle:     config.Viper.GetBool(config.Flag.Service.Installation.Workload.OrphanPod.Recycle),
+			},
 			ProjectName: project.Name(),
+			Shard:       clusterShard,
 		}
 
 		deleterController, err = controller.NewDeleter(c)
@@ -197,6 +325,7 @@ func New(config Config) (*Service, error) {
 			Logger:    config.Logger,
 
 			ProjectName: project.Name(),
+			Shard:       clusterShard,
 		}
 
 		drainerController, err = controller.NewDrainer(c)
@@ -213,6 +342,7 @@ func New(config Config) (*Service, error) {
 			WorkloadCluster: workloadCluster,
 
 			ProjectName:             project.Name(),
+			Shard:                   clusterShard,
 			TerminateUnhealthyNodes: config.Viper.GetBool(config.Flag.Service.TerminateUnhealthyNodes),
 		}
 
@@ -235,6 +365,81 @@ func New(config Config) (*Service, error) {
 		}
 	}
 
+	var leaderElection *leaderelection.LeaderElectionConfig
+	var leading chan struct{}
+	if config.Viper.GetBool(config.Flag.Service.LeaderElection.Enabled) {
+		namespace := config.Viper.GetString(config.Flag.Service.LeaderElection.Namespace)
+		if namespace == "" {
+			return nil, microerror.Maskf(invalidConfigError, "%T.Flag.Service.LeaderElection.Namespace must not be empty", config)
+		}
+
+		identity, err := os.Hostname()
+		if err != nil {
+			return nil, microerror.Mask(err)
+		}
+
+		// Every operator version reconciles its own KVMConfigs, so every
+		// version elects its own leader. The same applies to every shard of
+		// clusters.
+		name := fmt.Sprintf("%s-%s", project.Name(), project.Version())
+		if clusterShard.Enabled() {
+			name = fmt.Sprintf("%s-shard-%d", name, clusterShard.Index)
+		}
+
+		lock, err := resourcelock.New(
+			resourcelock.LeasesResourceLock,
+			namespace,
+			name,
+			k8sClient.K8sClient().CoreV1(),
+			k8sClient.K8sClient().CoordinationV1(),
+			resourcelock.ResourceLockConfig{
+				Identity:      identity,
+				EventRecorder: eventRecorder,
+			},
+		)
+		if err != nil {
+			return nil, microerror.Mask(err)
+		}
+
+		// The lease is not released on shutdown. The controllers are not
+		// stopped by the context of the leader election and keep reconciling
+		// until the operator exits, so a standby replica must only take over
+		// once the lease expired.
+		leaderElection = &leaderelection.LeaderElectionConfig{
+			Lock:          lock,
+			LeaseDuration: config.Viper.GetDuration(config.Flag.Service.LeaderElection.LeaseDuration),
+			RenewDeadline: config.Viper.GetDuration(config.Flag.Service.LeaderElection.RenewDeadline),
+			RetryPeriod:   config.Viper.GetDuration(config.Flag.Service.LeaderElection.RetryPeriod),
+			Name:          name,
+		}
+		leading = make(chan struct{})
+	}
+
+	statusResourceCollectorBooted := make(chan struct{})
+
+	var readinessService *readiness.Service
+	{
+		c := readiness.Config{
+			Components: []readiness.Component{
+				{Name: "clusterController", Booted: clusterController.Booted(), Leader: true},
+				{Name: "deleterController", Booted: deleterController.Booted(), Leader: true},
+				{Name: "drainerController", Booted: drainerController.Booted(), Leader: true},
+				{Name: "unhealthyNodeTerminatorController", Booted: unhealthyNodeTerminatorController.Booted(), Leader: true},
+				{Name: "statusResourceCollector", Booted: statusResourceCollectorBooted},
+			},
+			Inventory: inventoryService,
+			Leading:   leading,
+			Logger:    config.Logger,
+
+			MaxReconcileAge: 3 * operatorkitcontroller.DefaultResyncPeriod,
+		}
+
+		readinessService, err = readiness.New(c)
+		if err != nil {
+			return nil, microerror.Mask(err)
+		}
+	}
+
 	var versionService *version.Service
 	{
 		versionConfig := version.Config{
@@ -253,7 +458,11 @@ func New(config Config) (*Service, error) {
 	}
 
 	newService := &Service{
-		Version: versionService,
+		Inventory: inventoryService,
+		Readiness: readinessService,
+		Version:   versionService,
+
+		logger: config.Logger,
 
 		bootOnce:                          sync.Once{},
 		clusterController:                 clusterController,
@@ -261,6 +470,10 @@ func New(config Config) (*Service, error) {
 		drainerController:                 drainerController,
 		unhealthyNodeTerminatorController: unhealthyNodeTerminatorController,
 		statusResourceCollector:           statusResourceCollector,
+		statusResourceCollectorBooted:     statusResourceCollectorBooted,
+
+		leaderElection: leaderElection,
+		leading:        leading,
 	}
 
 	return newService, nil
@@ -273,11 +486,43 @@ func (s *Service) Boot() {
 			if err != nil {
 				panic(microerror.JSON(err))
 			}
+			close(s.statusResourceCollectorBooted)
 		}()
 
-		go s.clusterController.Boot(context.Background())
-		go s.deleterController.Boot(context.Background())
-		go s.drainerController.Boot(context.Background())
-		go s.unhealthyNodeTerminatorController.Boot(context.Background())
+		go s.Inventory.Boot(context.Background())
+
+		if s.leaderElection == nil {
+			s.bootControllers(context.Background())
+			return
+		}
+
+		config := *s.leaderElection
+		config.Callbacks = leaderelection.LeaderCallbacks{
+			OnStartedLeading: func(ctx context.Context) {
+				s.logger.Debugf(ctx, "acquired leader election lease")
+				close(s.leading)
+				s.bootControllers(ctx)
+			},
+			OnStoppedLeading: func() {
+				// Controllers cannot be booted again once stopped, so the
+				// operator exits and restarts as standby replica.
+				s.logger.Errorf(context.Background(), microerror.Mask(leaderElectionLostError), "stopping operator")
+				os.Exit(1)
+			},
+		}
+
+		leaderElector, err := leaderelection.NewLeaderElector(config)
+		if err != nil {
+			panic(microerror.JSON(err))
+		}
+
+		go leaderElector.Run(context.Background())
 	})
 }
+
+func (s *Service) bootControllers(ctx context.Context) {
+	go s.clusterController.Boot(ctx)
+	go s.deleterController.Boot(ctx)
+	go s.drainerController.Boot(ctx)
+	go s.unhealthyNodeTerminatorController.Boot(ctx)
+}